@@ -1,20 +1,56 @@
 package main
 
 import (
-	"log"
+	"flag"
+	"log/slog"
+	"os"
 
 	"github.com/erizkiatama/gotu-assignment/internal/app"
 	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
 )
 
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("failed to initialize config: %v", err)
+		fatal("failed to initialize config", err)
 	}
 
-	log.Println("starting application...")
-	if err := app.Initialize(cfg); err != nil {
-		log.Fatalf("failed to initialize app: %v", err)
+	log, err := logger.New(cfg.Log, os.Stdout)
+	if err != nil {
+		fatal("failed to initialize logger", err)
+	}
+	slog.SetDefault(log)
+
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		createAdmin(cfg, os.Args[2:])
+		return
+	}
+
+	slog.Info("starting application")
+	if err := app.Initialize(cfg, log); err != nil {
+		fatal("failed to initialize app", err)
+	}
+}
+
+// fatal logs the error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// createAdmin handles `create-admin -email <email> -password <password> -name <name>`
+func createAdmin(cfg *config.Config, args []string) {
+	var req user.RegisterRequest
+
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	fs.StringVar(&req.Email, "email", "", "email of the admin account")
+	fs.StringVar(&req.Password, "password", "", "password of the admin account, only required when the user does not exist yet")
+	fs.StringVar(&req.Name, "name", "Admin", "name of the admin account")
+	_ = fs.Parse(args)
+
+	if err := app.CreateAdmin(cfg, req); err != nil {
+		fatal("failed to create admin", err)
 	}
 }
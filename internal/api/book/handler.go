@@ -2,18 +2,28 @@ package book
 
 import (
 	"context"
-	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
 //go:generate mockgen -source=handler.go -package=book -destination=handler_mock_test.go
 type bookService interface {
-	List(ctx context.Context) (book.BookResponses, error)
+	List(ctx context.Context, req book.ListBookRequest) (book.BookResponses, *response.Pagination, error)
+	Search(ctx context.Context, req book.SearchBookRequest) (book.BookResponses, error)
+	Detail(ctx context.Context, id int64) (*book.BookResponse, time.Time, error)
+	Create(ctx context.Context, req book.BookRequest) (*book.BookResponse, error)
+	Update(ctx context.Context, id int64, req book.BookRequest) (*book.BookResponse, error)
+	Patch(ctx context.Context, id int64, req book.PatchBookRequest) (*book.BookResponse, error)
+	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*book.BookResponse, error)
 }
 
 type Handler struct {
@@ -27,12 +37,165 @@ func New(bookSvc bookService) *Handler {
 }
 
 func (h *Handler) List(c *gin.Context) {
-	res, err := h.bookSvc.List(c.Request.Context())
+	var req book.ListBookRequest
+
+	if !helpers.BindQuery(c, &req) {
+		return
+	}
+
+	res, pagination, err := h.bookSvc.List(c.Request.Context(), req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.List", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res, Pagination: pagination})
+}
+
+func (h *Handler) Search(c *gin.Context) {
+	var req book.SearchBookRequest
+
+	if !helpers.BindQuery(c, &req) {
+		return
+	}
+
+	res, err := h.bookSvc.Search(c.Request.Context(), req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Search", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+// Detail responds with 304 Not Modified when the client already has the current version of the book
+func (h *Handler) Detail(c *gin.Context) {
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	res, lastModified, err := h.bookSvc.Detail(c.Request.Context(), bookID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Detail", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	etag := helpers.ETag(res.ID, lastModified)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+
+	if helpers.IsNotModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req book.BookRequest
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	res, err := h.bookSvc.Create(c.Request.Context(), req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Create", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Response{Result: res})
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	var req book.BookRequest
+
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	res, err := h.bookSvc.Update(c.Request.Context(), bookID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Update", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	var req book.PatchBookRequest
+
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	res, err := h.bookSvc.Patch(c.Request.Context(), bookID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Patch", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.bookSvc.Delete(c.Request.Context(), bookID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Delete", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) Restore(c *gin.Context) {
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.bookSvc.Restore(c.Request.Context(), bookID)
 	if err != nil {
-		log.Printf("[BookHandler.List] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "BookHandler.Restore", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Response{Result: res})
 }
+
+// bookIDParam parses the book_id path parameter, responding with a bad request when it is invalid
+func bookIDParam(c *gin.Context) (int64, bool) {
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if bookID == 0 || err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, "invalid parameters: book_id is required", nil)
+		return 0, false
+	}
+
+	return bookID, true
+}
@@ -12,8 +12,10 @@ package book
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	book "github.com/erizkiatama/gotu-assignment/internal/model/book"
+	response "github.com/erizkiatama/gotu-assignment/internal/model/response"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,17 +42,123 @@ func (m *MockbookService) EXPECT() *MockbookServiceMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockbookService) Create(ctx context.Context, req book.BookRequest) (*book.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*book.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockbookServiceMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockbookService)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockbookService) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockbookServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockbookService)(nil).Delete), ctx, id)
+}
+
+// Detail mocks base method.
+func (m *MockbookService) Detail(ctx context.Context, id int64) (*book.BookResponse, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detail", ctx, id)
+	ret0, _ := ret[0].(*book.BookResponse)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Detail indicates an expected call of Detail.
+func (mr *MockbookServiceMockRecorder) Detail(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detail", reflect.TypeOf((*MockbookService)(nil).Detail), ctx, id)
+}
+
 // List mocks base method.
-func (m *MockbookService) List(ctx context.Context) (book.BookResponses, error) {
+func (m *MockbookService) List(ctx context.Context, req book.ListBookRequest) (book.BookResponses, *response.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
+	ret := m.ctrl.Call(m, "List", ctx, req)
 	ret0, _ := ret[0].(book.BookResponses)
+	ret1, _ := ret[1].(*response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockbookServiceMockRecorder) List(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockbookService)(nil).List), ctx, req)
+}
+
+// Patch mocks base method.
+func (m *MockbookService) Patch(ctx context.Context, id int64, req book.PatchBookRequest) (*book.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, req)
+	ret0, _ := ret[0].(*book.BookResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockbookServiceMockRecorder) List(ctx any) *gomock.Call {
+// Patch indicates an expected call of Patch.
+func (mr *MockbookServiceMockRecorder) Patch(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockbookService)(nil).Patch), ctx, id, req)
+}
+
+// Restore mocks base method.
+func (m *MockbookService) Restore(ctx context.Context, id int64) (*book.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*book.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockbookServiceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockbookService)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockbookService) Search(ctx context.Context, req book.SearchBookRequest) (book.BookResponses, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, req)
+	ret0, _ := ret[0].(book.BookResponses)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockbookServiceMockRecorder) Search(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockbookService)(nil).Search), ctx, req)
+}
+
+// Update mocks base method.
+func (m *MockbookService) Update(ctx context.Context, id int64, req book.BookRequest) (*book.BookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req)
+	ret0, _ := ret[0].(*book.BookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockbookServiceMockRecorder) Update(ctx, id, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockbookService)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockbookService)(nil).Update), ctx, id, req)
 }
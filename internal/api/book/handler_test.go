@@ -6,9 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 	gomock "go.uber.org/mock/gomock"
 
@@ -16,6 +20,8 @@ import (
 )
 
 func newMock(mockBookSvc *MockbookService) *Handler {
+	// The server registers the validation rules before any request is bound
+	validation.Register()
 	return New(mockBookSvc)
 }
 
@@ -27,18 +33,30 @@ func Test_handler_List(t *testing.T) {
 
 	h := newMock(bookSvc)
 
+	maxPrice := int64(200000)
+
 	tests := []struct {
-		name       string
-		mock       func(c *gin.Context)
-		wantStatus int
-		want       book.BookResponses
-		wantErr    bool
-		err        string
+		name           string
+		target         string
+		mock           func(c *gin.Context)
+		wantStatus     int
+		want           book.BookResponses
+		wantPagination *response.Pagination
+		wantErr        bool
+		err            string
 	}{
 		{
-			name: "success",
+			name:   "success",
+			target: "/api/v1/book?limit=2&sort=price&order=desc&author=author&max_price=200000&cursor=abc",
 			mock: func(c *gin.Context) {
-				bookSvc.EXPECT().List(gomock.Any()).Return(book.BookResponses{
+				bookSvc.EXPECT().List(gomock.Any(), book.ListBookRequest{
+					Cursor:   "abc",
+					Limit:    2,
+					Sort:     "price",
+					Order:    "desc",
+					Author:   "author",
+					MaxPrice: &maxPrice,
+				}).Return(book.BookResponses{
 					{
 						ID:          1,
 						Title:       "Book 1",
@@ -53,7 +71,7 @@ func Test_handler_List(t *testing.T) {
 						Description: "Description 2",
 						Price:       250000,
 					},
-				}, nil)
+				}, &response.Pagination{Limit: 2, HasMore: true, NextCursor: "next"}, nil)
 			},
 			wantStatus: http.StatusOK,
 			want: book.BookResponses{
@@ -72,11 +90,21 @@ func Test_handler_List(t *testing.T) {
 					Price:       250000,
 				},
 			},
+			wantPagination: &response.Pagination{Limit: 2, HasMore: true, NextCursor: "next"},
 		},
 		{
-			name: "error from service",
+			name:       "invalid parameters",
+			target:     "/api/v1/book?limit=abc",
+			mock:       func(c *gin.Context) {},
+			wantStatus: http.StatusBadRequest,
+			err:        "invalid parameters: strconv.ParseInt: parsing \"abc\": invalid syntax",
+			wantErr:    true,
+		},
+		{
+			name:   "error from service",
+			target: "/api/v1/book",
 			mock: func(c *gin.Context) {
-				bookSvc.EXPECT().List(gomock.Any()).Return(nil, errors.New("error from service"))
+				bookSvc.EXPECT().List(gomock.Any(), book.ListBookRequest{}).Return(nil, nil, errors.New("error from service"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			err:        constant.ErrorInternalServer,
@@ -85,6 +113,121 @@ func Test_handler_List(t *testing.T) {
 	}
 
 	Convey("Test Book Handler - List", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			Convey(tt.name, func() {
+				tt.mock(c)
+				h.List(c)
+				So(w.Code, ShouldEqual, tt.wantStatus)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got struct {
+						Result     book.BookResponses   `json:"result"`
+						Pagination *response.Pagination `json:"pagination"`
+					}
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got.Result, ShouldResemble, tt.want)
+					So(got.Pagination, ShouldResemble, tt.wantPagination)
+				}
+
+			})
+		}
+	})
+}
+
+func Test_handler_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	type args struct {
+		req        book.BookRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    book.BookResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid parameters",
+			args: args{
+				statusCode: http.StatusBadRequest,
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"price": "free"}, http.MethodPost)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal string into Go struct field BookRequest.price of type int64",
+		},
+		{
+			name: "validation failed",
+			args: args{
+				statusCode: http.StatusUnprocessableEntity,
+				req:        book.BookRequest{Title: "Book 1", Price: -1},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+			},
+			wantErr: true,
+			err:     constant.ErrorValidationFailed,
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusInternalServerError,
+				req:        book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				bookSvc.EXPECT().Create(gomock.Any(), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusInternalServerError,
+					Msg:  constant.ErrorCreateBookFailed,
+					Err:  errors.New("error"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorCreateBookFailed,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusCreated,
+				req:        book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				bookSvc.EXPECT().Create(gomock.Any(), arg.req).Return(&book.BookResponse{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}, nil)
+			},
+			want: book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  150000,
+			},
+		},
+	}
+
+	Convey("Test Book Handler - Create", t, func() {
 		for _, tt := range tests {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
@@ -94,20 +237,635 @@ func Test_handler_List(t *testing.T) {
 			}
 
 			Convey(tt.name, func() {
-				tt.mock(c)
-				h.List(c)
+				tt.mock(tt.args, c)
+				h.Create(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]book.BookResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Update(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	type args struct {
+		bookID     string
+		req        book.BookRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    book.BookResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid book id",
+			args: args{
+				bookID:     "abc",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: book_id is required",
+		},
+		{
+			name: "invalid parameters",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusBadRequest,
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"title": 1}, http.MethodPut)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal number into Go struct field BookRequest.title of type string",
+		},
+		{
+			name: "error from service",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusNotFound,
+				req:        book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPut)
+				bookSvc.EXPECT().Update(gomock.Any(), int64(1), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorBookNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorBookNotFound,
+		},
+		{
+			name: "success",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusOK,
+				req:        book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPut)
+				bookSvc.EXPECT().Update(gomock.Any(), int64(1), arg.req).Return(&book.BookResponse{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}, nil)
+			},
+			want: book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  150000,
+			},
+		},
+	}
+
+	Convey("Test Book Handler - Update", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.args.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.Update(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]book.BookResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Patch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	price := int64(175000)
+
+	type args struct {
+		bookID     string
+		req        book.PatchBookRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    book.BookResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid book id",
+			args: args{
+				bookID:     "0",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: book_id is required",
+		},
+		{
+			name: "error from service",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusInternalServerError,
+				req:        book.PatchBookRequest{Price: &price},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPatch)
+				bookSvc.EXPECT().Patch(gomock.Any(), int64(1), arg.req).Return(nil, errors.New("error from service"))
+			},
+			wantErr: true,
+			err:     constant.ErrorInternalServer,
+		},
+		{
+			name: "success",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusOK,
+				req:        book.PatchBookRequest{Price: &price},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPatch)
+				bookSvc.EXPECT().Patch(gomock.Any(), int64(1), arg.req).Return(&book.BookResponse{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  price,
+				}, nil)
+			},
+			want: book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  price,
+			},
+		},
+	}
+
+	Convey("Test Book Handler - Patch", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.args.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.Patch(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]book.BookResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Delete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	type args struct {
+		bookID     string
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args)
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid book id",
+			args: args{
+				bookID:     "abc",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args) {},
+			wantErr: true,
+			err:     "invalid parameters: book_id is required",
+		},
+		{
+			name: "error from service",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusNotFound,
+			},
+			mock: func(arg args) {
+				bookSvc.EXPECT().Delete(gomock.Any(), int64(1)).Return(&response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorBookNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorBookNotFound,
+		},
+		{
+			name: "success",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusNoContent,
+			},
+			mock: func(arg args) {
+				bookSvc.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			},
+		},
+	}
+
+	Convey("Test Book Handler - Delete", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.args.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				h.Delete(c)
+
+				if tt.wantErr {
+					So(w.Code, ShouldEqual, tt.args.statusCode)
+
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					So(c.Writer.Status(), ShouldEqual, tt.args.statusCode)
+					So(w.Body.Len(), ShouldEqual, 0)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Restore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	type args struct {
+		bookID     string
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args)
+		want    book.BookResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid book id",
+			args: args{
+				bookID:     "abc",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args) {},
+			wantErr: true,
+			err:     "invalid parameters: book_id is required",
+		},
+		{
+			name: "error from service",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusNotFound,
+			},
+			mock: func(arg args) {
+				bookSvc.EXPECT().Restore(gomock.Any(), int64(1)).Return(nil, &response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorBookNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorBookNotFound,
+		},
+		{
+			name: "success",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusOK,
+			},
+			mock: func(arg args) {
+				bookSvc.EXPECT().Restore(gomock.Any(), int64(1)).Return(&book.BookResponse{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}, nil)
+			},
+			want: book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  150000,
+			},
+		},
+	}
+
+	Convey("Test Book Handler - Restore", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.args.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				h.Restore(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]book.BookResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(bookSvc)
+
+	tests := []struct {
+		name       string
+		target     string
+		mock       func()
+		wantStatus int
+		want       book.BookResponses
+		wantErr    bool
+		err        string
+	}{
+		{
+			name:   "success",
+			target: "/api/v1/book/search?q=gatsby&limit=5",
+			mock: func() {
+				bookSvc.EXPECT().Search(gomock.Any(), book.SearchBookRequest{Query: "gatsby", Limit: 5}).Return(book.BookResponses{
+					{
+						ID:        1,
+						Title:     "The Great Gatsby",
+						Author:    "F. Scott Fitzgerald",
+						Price:     100000,
+						Highlight: &book.BookHighlight{Title: "The Great <mark>Gatsby</mark>"},
+					},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			want: book.BookResponses{
+				{
+					ID:        1,
+					Title:     "The Great Gatsby",
+					Author:    "F. Scott Fitzgerald",
+					Price:     100000,
+					Highlight: &book.BookHighlight{Title: "The Great <mark>Gatsby</mark>"},
+				},
+			},
+		},
+		{
+			name:       "invalid parameters",
+			target:     "/api/v1/book/search?q=gatsby&limit=abc",
+			mock:       func() {},
+			wantStatus: http.StatusBadRequest,
+			err:        "invalid parameters: strconv.ParseInt: parsing \"abc\": invalid syntax",
+			wantErr:    true,
+		},
+		{
+			name:       "validation failed",
+			target:     "/api/v1/book/search",
+			mock:       func() {},
+			wantStatus: http.StatusUnprocessableEntity,
+			err:        constant.ErrorValidationFailed,
+			wantErr:    true,
+		},
+		{
+			name:   "error from service",
+			target: "/api/v1/book/search?q=gatsby",
+			mock: func() {
+				bookSvc.EXPECT().Search(gomock.Any(), book.SearchBookRequest{Query: "gatsby"}).Return(nil, &response.ServiceError{
+					Code: http.StatusInternalServerError,
+					Msg:  constant.ErrorSearchBooksFailed,
+					Err:  errors.New("error"),
+				})
+			},
+			wantStatus: http.StatusInternalServerError,
+			err:        constant.ErrorSearchBooksFailed,
+			wantErr:    true,
+		},
+	}
+
+	Convey("Test Book Handler - Search", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.Search(c)
 				So(w.Code, ShouldEqual, tt.wantStatus)
 
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
 					var got map[string]book.BookResponses
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
 					So(got["result"], ShouldResemble, tt.want)
 				}
+			})
+		}
+	})
+}
+
+func Test_handler_Detail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	bookSvc := NewMockbookService(mockCtrl)
+	defer mockCtrl.Finish()
 
+	h := newMock(bookSvc)
+
+	lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	etag := helpers.ETag(1, lastModified)
+	res := &book.BookResponse{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000}
+
+	tests := []struct {
+		name       string
+		bookID     string
+		headers    map[string]string
+		mock       func()
+		wantStatus int
+		want       book.BookResponse
+		wantErr    bool
+		err        string
+	}{
+		{
+			name:       "invalid book id",
+			bookID:     "abc",
+			mock:       func() {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			err:        "invalid parameters: book_id is required",
+		},
+		{
+			name:   "error from service",
+			bookID: "1",
+			mock: func() {
+				bookSvc.EXPECT().Detail(gomock.Any(), int64(1)).Return(nil, time.Time{}, &response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorBookNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+			err:        constant.ErrorBookNotFound,
+		},
+		{
+			name:   "success",
+			bookID: "1",
+			mock: func() {
+				bookSvc.EXPECT().Detail(gomock.Any(), int64(1)).Return(res, lastModified, nil)
+			},
+			wantStatus: http.StatusOK,
+			want:       *res,
+		},
+		{
+			name:    "stale etag",
+			bookID:  "1",
+			headers: map[string]string{"If-None-Match": `"1-1"`},
+			mock: func() {
+				bookSvc.EXPECT().Detail(gomock.Any(), int64(1)).Return(res, lastModified, nil)
+			},
+			wantStatus: http.StatusOK,
+			want:       *res,
+		},
+		{
+			name:    "not modified",
+			bookID:  "1",
+			headers: map[string]string{"If-None-Match": etag},
+			mock: func() {
+				bookSvc.EXPECT().Detail(gomock.Any(), int64(1)).Return(res, lastModified, nil)
+			},
+			wantStatus: http.StatusNotModified,
+		},
+	}
+
+	Convey("Test Book Handler - Detail", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+			router.GET("/api/v1/book/:book_id", h.Detail)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/book/"+tt.bookID, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			Convey(tt.name, func() {
+				tt.mock()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, tt.wantStatus)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+					return
+				}
+
+				So(w.Header().Get("ETag"), ShouldEqual, etag)
+				So(w.Header().Get("Last-Modified"), ShouldEqual, "Mon, 01 Jan 2024 00:00:00 GMT")
+
+				if tt.wantStatus == http.StatusNotModified {
+					So(w.Body.Len(), ShouldEqual, 0)
+				} else {
+					var got map[string]book.BookResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
 			})
 		}
 	})
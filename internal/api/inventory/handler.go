@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -package=inventory -destination=handler_mock_test.go
+type inventoryService interface {
+	GetStock(ctx context.Context, bookID int64, req inventory.GetStockRequest) (*inventory.StockResponse, error)
+	AdjustStock(ctx context.Context, userID, bookID int64, req inventory.AdjustStockRequest) (*inventory.StockAdjustmentResponse, error)
+}
+
+type Handler struct {
+	inventorySvc inventoryService
+}
+
+func New(inventorySvc inventoryService) *Handler {
+	return &Handler{
+		inventorySvc: inventorySvc,
+	}
+}
+
+func (h *Handler) GetStock(c *gin.Context) {
+	var req inventory.GetStockRequest
+
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindQuery(c, &req) {
+		return
+	}
+
+	res, err := h.inventorySvc.GetStock(c.Request.Context(), bookID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "InventoryHandler.GetStock", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) AdjustStock(c *gin.Context) {
+	var req inventory.AdjustStockRequest
+
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	res, err := h.inventorySvc.AdjustStock(c.Request.Context(), userID.(int64), bookID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "InventoryHandler.AdjustStock", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Response{Result: res})
+}
+
+// bookIDParam parses the book_id path parameter, responding with a bad request when it is invalid
+func bookIDParam(c *gin.Context) (int64, bool) {
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if bookID == 0 || err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, "invalid parameters: book_id is required", nil)
+		return 0, false
+	}
+
+	return bookID, true
+}
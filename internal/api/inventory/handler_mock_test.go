@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -package=inventory -destination=handler_mock_test.go
+//
+
+// Package inventory is a generated GoMock package.
+package inventory
+
+import (
+	context "context"
+	reflect "reflect"
+
+	inventory "github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockinventoryService is a mock of inventoryService interface.
+type MockinventoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockinventoryServiceMockRecorder
+}
+
+// MockinventoryServiceMockRecorder is the mock recorder for MockinventoryService.
+type MockinventoryServiceMockRecorder struct {
+	mock *MockinventoryService
+}
+
+// NewMockinventoryService creates a new mock instance.
+func NewMockinventoryService(ctrl *gomock.Controller) *MockinventoryService {
+	mock := &MockinventoryService{ctrl: ctrl}
+	mock.recorder = &MockinventoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinventoryService) EXPECT() *MockinventoryServiceMockRecorder {
+	return m.recorder
+}
+
+// AdjustStock mocks base method.
+func (m *MockinventoryService) AdjustStock(ctx context.Context, userID, bookID int64, req inventory.AdjustStockRequest) (*inventory.StockAdjustmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", ctx, userID, bookID, req)
+	ret0, _ := ret[0].(*inventory.StockAdjustmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockinventoryServiceMockRecorder) AdjustStock(ctx, userID, bookID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockinventoryService)(nil).AdjustStock), ctx, userID, bookID, req)
+}
+
+// GetStock mocks base method.
+func (m *MockinventoryService) GetStock(ctx context.Context, bookID int64, req inventory.GetStockRequest) (*inventory.StockResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, bookID, req)
+	ret0, _ := ret[0].(*inventory.StockResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockinventoryServiceMockRecorder) GetStock(ctx, bookID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockinventoryService)(nil).GetStock), ctx, bookID, req)
+}
@@ -0,0 +1,244 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
+	"github.com/gin-gonic/gin"
+	gomock "go.uber.org/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newMock(mockInventorySvc *MockinventoryService) *Handler {
+	// The server registers the validation rules before any request is bound
+	validation.Register()
+	return New(mockInventorySvc)
+}
+
+func Test_handler_GetStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	inventorySvc := NewMockinventoryService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(inventorySvc)
+
+	tests := []struct {
+		name       string
+		bookID     string
+		target     string
+		mock       func()
+		wantStatus int
+		want       inventory.StockResponse
+		wantErr    bool
+		err        string
+	}{
+		{
+			name:       "invalid book id",
+			bookID:     "abc",
+			target:     "/api/v1/admin/book/abc/stock",
+			mock:       func() {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			err:        "invalid parameters: book_id is required",
+		},
+		{
+			name:       "invalid parameters",
+			bookID:     "1",
+			target:     "/api/v1/admin/book/1/stock?limit=abc",
+			mock:       func() {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+			err:        "invalid parameters: strconv.ParseInt: parsing \"abc\": invalid syntax",
+		},
+		{
+			name:   "error from service",
+			bookID: "1",
+			target: "/api/v1/admin/book/1/stock",
+			mock: func() {
+				inventorySvc.EXPECT().GetStock(gomock.Any(), int64(1), inventory.GetStockRequest{}).Return(nil, &response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorBookNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+			err:        constant.ErrorBookNotFound,
+		},
+		{
+			name:   "success",
+			bookID: "1",
+			target: "/api/v1/admin/book/1/stock?limit=5",
+			mock: func() {
+				inventorySvc.EXPECT().GetStock(gomock.Any(), int64(1), inventory.GetStockRequest{Limit: 5}).Return(&inventory.StockResponse{
+					BookID:      1,
+					Stock:       10,
+					Adjustments: []inventory.StockAdjustmentResponse{},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			want: inventory.StockResponse{
+				BookID:      1,
+				Stock:       10,
+				Adjustments: []inventory.StockAdjustmentResponse{},
+			},
+		},
+	}
+
+	Convey("Test Inventory Handler - GetStock", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.GetStock(c)
+				So(w.Code, ShouldEqual, tt.wantStatus)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]inventory.StockResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_AdjustStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	inventorySvc := NewMockinventoryService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(inventorySvc)
+
+	type args struct {
+		bookID     string
+		req        inventory.AdjustStockRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    inventory.StockAdjustmentResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid book id",
+			args: args{
+				bookID:     "abc",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: book_id is required",
+		},
+		{
+			name: "invalid parameters",
+			args: args{
+				bookID:     "1",
+				statusCode: http.StatusBadRequest,
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"quantity_change": "ten"}, http.MethodPost)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal string into Go struct field AdjustStockRequest.quantity_change of type int64",
+		},
+		{
+			name: "error from service",
+			args: args{
+				bookID:     "1",
+				req:        inventory.AdjustStockRequest{QtyChange: -100, Reason: "damaged"},
+				statusCode: http.StatusConflict,
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				inventorySvc.EXPECT().AdjustStock(gomock.Any(), int64(2), int64(1), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Msg:  constant.ErrorInsufficientStock,
+					Err:  errors.New("insufficient stock"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorInsufficientStock,
+		},
+		{
+			name: "success",
+			args: args{
+				bookID:     "1",
+				req:        inventory.AdjustStockRequest{QtyChange: 10, Reason: "restock"},
+				statusCode: http.StatusCreated,
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				inventorySvc.EXPECT().AdjustStock(gomock.Any(), int64(2), int64(1), arg.req).Return(&inventory.StockAdjustmentResponse{
+					ID:        1,
+					BookID:    1,
+					UserID:    2,
+					QtyChange: 10,
+					Stock:     10,
+					Reason:    "restock",
+				}, nil)
+			},
+			want: inventory.StockAdjustmentResponse{
+				ID:        1,
+				BookID:    1,
+				UserID:    2,
+				QtyChange: 10,
+				Stock:     10,
+				Reason:    "restock",
+			},
+		},
+	}
+
+	Convey("Test Inventory Handler - AdjustStock", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Set("user_id", int64(2))
+			c.Params = append(c.Params, gin.Param{Key: "book_id", Value: tt.args.bookID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.AdjustStock(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]inventory.StockAdjustmentResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -2,22 +2,24 @@ package order
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
 //go:generate mockgen -source=handler.go -package=order -destination=handler_mock_test.go
 type orderService interface {
 	CreateOrder(ctx context.Context, userID int64, req order.CreateOrderRequest) (*order.OrderResponse, error)
-	ListOrder(ctx context.Context, userID int64) ([]order.OrderResponse, error)
+	ListOrder(ctx context.Context, userID int64, req order.ListOrderRequest) ([]order.OrderResponse, *response.Pagination, error)
 	DetailOrder(ctx context.Context, userID, orderID int64) (*order.OrderResponse, error)
+	UpdateStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error)
+	CancelOrder(ctx context.Context, userID, orderID int64, req order.CancelOrderRequest) (*order.OrderResponse, error)
 }
 
 type Handler struct {
@@ -33,17 +35,14 @@ func New(orderSvc orderService) *Handler {
 func (h *Handler) CreateOrder(c *gin.Context) {
 	var req order.CreateOrderRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			Error: fmt.Sprintf("invalid parameters: %s", err.Error()),
-		})
+	if !helpers.BindJSON(c, &req) {
 		return
 	}
 
 	userID, _ := c.Get("user_id")
 	res, err := h.orderSvc.CreateOrder(c.Request.Context(), userID.(int64), req)
 	if err != nil {
-		log.Printf("[OrderHandler.CreateOrder] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "OrderHandler.CreateOrder", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
@@ -52,35 +51,93 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 }
 
 func (h *Handler) ListOrder(c *gin.Context) {
+	var req order.ListOrderRequest
+
+	if !helpers.BindQuery(c, &req) {
+		return
+	}
+
 	userID, _ := c.Get("user_id")
-	res, err := h.orderSvc.ListOrder(c.Request.Context(), userID.(int64))
+	res, pagination, err := h.orderSvc.ListOrder(c.Request.Context(), userID.(int64), req)
 	if err != nil {
-		log.Printf("[OrderHandler.ListOrder] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "OrderHandler.ListOrder", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, response.Response{Result: res})
+	c.JSON(http.StatusOK, response.Response{Result: res, Pagination: pagination})
 }
 
 func (h *Handler) DetailOrder(c *gin.Context) {
 	userID, _ := c.Get("user_id")
-	orderIDStr := c.Param("order_id")
-
-	orderID, err := strconv.ParseInt(orderIDStr, 10, 64)
-	if orderID == 0 || err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			Error: "invalid parameters: order_id is required",
-		})
+	orderID, ok := orderIDParam(c)
+	if !ok {
 		return
 	}
 
 	res, err := h.orderSvc.DetailOrder(c.Request.Context(), userID.(int64), orderID)
 	if err != nil {
-		log.Printf("[OrderHandler.DetailOrder] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "OrderHandler.DetailOrder", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) UpdateStatus(c *gin.Context) {
+	var req order.UpdateOrderStatusRequest
+
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := c.Get("user_id")
+	res, err := h.orderSvc.UpdateStatus(c.Request.Context(), actorID.(int64), orderID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "OrderHandler.UpdateStatus", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Response{Result: res})
 }
+
+func (h *Handler) CancelOrder(c *gin.Context) {
+	var req order.CancelOrderRequest
+
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	res, err := h.orderSvc.CancelOrder(c.Request.Context(), userID.(int64), orderID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "OrderHandler.CancelOrder", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+// orderIDParam parses the order_id path parameter, responding with a bad request when it is invalid
+func orderIDParam(c *gin.Context) (int64, bool) {
+	orderID, err := strconv.ParseInt(c.Param("order_id"), 10, 64)
+	if orderID == 0 || err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, "invalid parameters: order_id is required", nil)
+		return 0, false
+	}
+
+	return orderID, true
+}
@@ -1,5 +1,10 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -package=order -destination=handler_mock_test.go
+//
 
 // Package order is a generated GoMock package.
 package order
@@ -9,6 +14,7 @@ import (
 	reflect "reflect"
 
 	order "github.com/erizkiatama/gotu-assignment/internal/model/order"
+	response "github.com/erizkiatama/gotu-assignment/internal/model/response"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -35,6 +41,21 @@ func (m *MockorderService) EXPECT() *MockorderServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockorderService) CancelOrder(ctx context.Context, userID, orderID int64, req order.CancelOrderRequest) (*order.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, userID, orderID, req)
+	ret0, _ := ret[0].(*order.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockorderServiceMockRecorder) CancelOrder(ctx, userID, orderID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockorderService)(nil).CancelOrder), ctx, userID, orderID, req)
+}
+
 // CreateOrder mocks base method.
 func (m *MockorderService) CreateOrder(ctx context.Context, userID int64, req order.CreateOrderRequest) (*order.OrderResponse, error) {
 	m.ctrl.T.Helper()
@@ -45,7 +66,7 @@ func (m *MockorderService) CreateOrder(ctx context.Context, userID int64, req or
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockorderServiceMockRecorder) CreateOrder(ctx, userID, req interface{}) *gomock.Call {
+func (mr *MockorderServiceMockRecorder) CreateOrder(ctx, userID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockorderService)(nil).CreateOrder), ctx, userID, req)
 }
@@ -60,22 +81,38 @@ func (m *MockorderService) DetailOrder(ctx context.Context, userID, orderID int6
 }
 
 // DetailOrder indicates an expected call of DetailOrder.
-func (mr *MockorderServiceMockRecorder) DetailOrder(ctx, userID, orderID interface{}) *gomock.Call {
+func (mr *MockorderServiceMockRecorder) DetailOrder(ctx, userID, orderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetailOrder", reflect.TypeOf((*MockorderService)(nil).DetailOrder), ctx, userID, orderID)
 }
 
 // ListOrder mocks base method.
-func (m *MockorderService) ListOrder(ctx context.Context, userID int64) ([]order.OrderResponse, error) {
+func (m *MockorderService) ListOrder(ctx context.Context, userID int64, req order.ListOrderRequest) ([]order.OrderResponse, *response.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListOrder", ctx, userID)
+	ret := m.ctrl.Call(m, "ListOrder", ctx, userID, req)
 	ret0, _ := ret[0].([]order.OrderResponse)
+	ret1, _ := ret[1].(*response.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOrder indicates an expected call of ListOrder.
+func (mr *MockorderServiceMockRecorder) ListOrder(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrder", reflect.TypeOf((*MockorderService)(nil).ListOrder), ctx, userID, req)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockorderService) UpdateStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, actorID, orderID, req)
+	ret0, _ := ret[0].(*order.OrderResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListOrder indicates an expected call of ListOrder.
-func (mr *MockorderServiceMockRecorder) ListOrder(ctx, userID interface{}) *gomock.Call {
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockorderServiceMockRecorder) UpdateStatus(ctx, actorID, orderID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrder", reflect.TypeOf((*MockorderService)(nil).ListOrder), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockorderService)(nil).UpdateStatus), ctx, actorID, orderID, req)
 }
@@ -9,7 +9,9 @@ import (
 
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 	gomock "go.uber.org/mock/gomock"
 
@@ -17,6 +19,8 @@ import (
 )
 
 func newMock(orderSvc *MockorderService) *Handler {
+	// The server registers the validation rules before any request is bound
+	validation.Register()
 	return New(orderSvc)
 }
 
@@ -59,6 +63,22 @@ func Test_handler_CreateOrder(t *testing.T) {
 			wantErr: true,
 			err:     "invalid parameters: json: cannot unmarshal string into Go struct field CreateOrderRequest.details of type []order.CreateOrderDetailRequest",
 		},
+		{
+			name: "validation failed",
+			args: args{
+				statusCode: http.StatusUnprocessableEntity,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{BookID: 1, Qty: 1},
+						{BookID: 1, Qty: 0},
+					}},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, "POST")
+			},
+			wantErr: true,
+			err:     constant.ErrorValidationFailed,
+		},
 		{
 			name: "error from service",
 			args: args{
@@ -146,7 +166,7 @@ func Test_handler_CreateOrder(t *testing.T) {
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
 					var got map[string]order.OrderResponse
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
@@ -166,23 +186,36 @@ func Test_handler_ListOrder(t *testing.T) {
 	h := newMock(orderSvc)
 
 	type args struct {
+		target     string
 		statusCode int
 	}
 	tests := []struct {
-		name    string
-		args    args
-		mock    func(arg args, c *gin.Context)
-		want    []order.OrderResponse
-		wantErr bool
-		err     string
+		name           string
+		args           args
+		mock           func(arg args, c *gin.Context)
+		want           []order.OrderResponse
+		wantPagination *response.Pagination
+		wantErr        bool
+		err            string
 	}{
+		{
+			name: "invalid parameters",
+			args: args{
+				target:     "/api/v1/order?limit=abc",
+				statusCode: http.StatusBadRequest,
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: strconv.ParseInt: parsing \"abc\": invalid syntax",
+		},
 		{
 			name: "error from service",
 			args: args{
+				target:     "/api/v1/order",
 				statusCode: http.StatusInternalServerError,
 			},
 			mock: func(arg args, c *gin.Context) {
-				orderSvc.EXPECT().ListOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New("error from service"))
+				orderSvc.EXPECT().ListOrder(gomock.Any(), int64(1), order.ListOrderRequest{}).Return(nil, nil, errors.New("error from service"))
 			},
 			wantErr: true,
 			err:     constant.ErrorInternalServer,
@@ -190,25 +223,26 @@ func Test_handler_ListOrder(t *testing.T) {
 		{
 			name: "success",
 			args: args{
+				target:     "/api/v1/order?limit=1&status=paid&from=2024-01-01&to=2024-02-01&cursor=abc&include=details",
 				statusCode: http.StatusOK,
 			},
 			mock: func(arg args, c *gin.Context) {
-				orderSvc.EXPECT().ListOrder(gomock.Any(), gomock.Any()).Return([]order.OrderResponse{
+				orderSvc.EXPECT().ListOrder(gomock.Any(), int64(1), order.ListOrderRequest{
+					Cursor:  "abc",
+					Limit:   1,
+					Status:  order.StatusPaid,
+					From:    "2024-01-01",
+					To:      "2024-02-01",
+					Include: order.IncludeDetails,
+				}).Return([]order.OrderResponse{
 					{
 						ID:         1,
 						UserID:     1,
 						TotalQty:   1,
 						TotalPrice: 1000,
-						Details: []order.OrderDetailResponse{
-							{
-								ID:     1,
-								BookID: 1,
-								Qty:    1,
-								Price:  1000,
-							},
-						},
+						Status:     order.StatusPaid,
 					},
-				}, nil)
+				}, &response.Pagination{Limit: 1, HasMore: true, NextCursor: "next"}, nil)
 			},
 			want: []order.OrderResponse{
 				{
@@ -216,16 +250,10 @@ func Test_handler_ListOrder(t *testing.T) {
 					UserID:     1,
 					TotalQty:   1,
 					TotalPrice: 1000,
-					Details: []order.OrderDetailResponse{
-						{
-							ID:     1,
-							BookID: 1,
-							Qty:    1,
-							Price:  1000,
-						},
-					},
+					Status:     order.StatusPaid,
 				},
 			},
+			wantPagination: &response.Pagination{Limit: 1, HasMore: true, NextCursor: "next"},
 		},
 	}
 
@@ -235,9 +263,7 @@ func Test_handler_ListOrder(t *testing.T) {
 			c, _ := gin.CreateTestContext(w)
 
 			c.Set("user_id", int64(1))
-			c.Request = &http.Request{
-				Header: make(http.Header),
-			}
+			c.Request = httptest.NewRequest(http.MethodGet, tt.args.target, nil)
 
 			Convey(tt.name, func() {
 				tt.mock(tt.args, c)
@@ -247,11 +273,15 @@ func Test_handler_ListOrder(t *testing.T) {
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
-					var got map[string][]order.OrderResponse
+					var got struct {
+						Result     []order.OrderResponse `json:"result"`
+						Pagination *response.Pagination  `json:"pagination"`
+					}
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["result"], ShouldResemble, tt.want)
+					So(got.Result, ShouldResemble, tt.want)
+					So(got.Pagination, ShouldResemble, tt.wantPagination)
 				}
 			})
 		}
@@ -369,7 +399,245 @@ func Test_handler_DetailOrder(t *testing.T) {
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]order.OrderResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_UpdateStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	orderSvc := NewMockorderService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(orderSvc)
+
+	type args struct {
+		orderID    string
+		req        order.UpdateOrderStatusRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    order.OrderResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid order id",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "abc",
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: order_id is required",
+		},
+		{
+			name: "invalid parameters",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "1",
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"status": 1}, http.MethodPost)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal number into Go struct field UpdateOrderStatusRequest.status of type string",
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusConflict,
+				orderID:    "1",
+				req:        order.UpdateOrderStatusRequest{Status: order.StatusCancelled},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				orderSvc.EXPECT().UpdateStatus(gomock.Any(), int64(9), int64(1), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Msg:  constant.ErrorInvalidOrderStatusTransition,
+					Err:  errors.New("illegal transition"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorInvalidOrderStatusTransition,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusOK,
+				orderID:    "1",
+				req:        order.UpdateOrderStatusRequest{Status: order.StatusFulfilled},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				orderSvc.EXPECT().UpdateStatus(gomock.Any(), int64(9), int64(1), arg.req).Return(&order.OrderResponse{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 1000,
+					Status:     order.StatusFulfilled,
+				}, nil)
+			},
+			want: order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 1000,
+				Status:     order.StatusFulfilled,
+			},
+		},
+	}
+
+	Convey("Test Order Handler - Update Status", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", int64(9))
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "order_id", Value: tt.args.orderID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.UpdateStatus(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]order.OrderResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_CancelOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	orderSvc := NewMockorderService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(orderSvc)
+
+	type args struct {
+		orderID    string
+		req        order.CancelOrderRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    order.OrderResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid order id",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "abc",
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: order_id is required",
+		},
+		{
+			name: "invalid parameters",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "1",
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"reason": 1}, http.MethodPost)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal number into Go struct field CancelOrderRequest.reason of type string",
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusConflict,
+				orderID:    "1",
+				req:        order.CancelOrderRequest{Reason: "changed my mind"},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				orderSvc.EXPECT().CancelOrder(gomock.Any(), int64(1), int64(1), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Msg:  constant.ErrorOrderNotCancellable,
+					Err:  errors.New("order is paid"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorOrderNotCancellable,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusOK,
+				orderID:    "1",
+				req:        order.CancelOrderRequest{Reason: "changed my mind"},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				orderSvc.EXPECT().CancelOrder(gomock.Any(), int64(1), int64(1), arg.req).Return(&order.OrderResponse{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 1000,
+					Status:     order.StatusCancelled,
+				}, nil)
+			},
+			want: order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 1000,
+				Status:     order.StatusCancelled,
+			},
+		},
+	}
+
+	Convey("Test Order Handler - Cancel Order", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", int64(1))
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "order_id", Value: tt.args.orderID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.CancelOrder(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
 					var got map[string]order.OrderResponse
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
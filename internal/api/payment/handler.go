@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=handler.go -package=payment -destination=handler_mock_test.go
+type paymentService interface {
+	CreatePayment(ctx context.Context, userID, orderID int64) (*payment.PaymentResponse, error)
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) error
+	RefundPayment(ctx context.Context, actorID, orderID int64, req payment.RefundPaymentRequest) (*order.OrderResponse, error)
+}
+
+type Handler struct {
+	paymentSvc paymentService
+}
+
+func New(paymentSvc paymentService) *Handler {
+	return &Handler{
+		paymentSvc: paymentSvc,
+	}
+}
+
+func (h *Handler) CreatePayment(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	res, err := h.paymentSvc.CreatePayment(c.Request.Context(), userID.(int64), orderID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "PaymentHandler.CreatePayment", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Response{Result: res})
+}
+
+// Webhook receives the notifications of the payment gateway. The raw body is passed on, as the
+// signature is computed over it.
+func (h *Handler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, fmt.Sprintf("invalid parameters: %s", err.Error()), nil)
+		return
+	}
+
+	if err := h.paymentSvc.HandleWebhook(c.Request.Context(), c.Request.Header, body); err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "PaymentHandler.Webhook", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) RefundPayment(c *gin.Context) {
+	var req payment.RefundPaymentRequest
+
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	actorID, _ := c.Get("user_id")
+	res, err := h.paymentSvc.RefundPayment(c.Request.Context(), actorID.(int64), orderID, req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "PaymentHandler.RefundPayment", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+// orderIDParam parses the order_id path parameter, responding with a bad request when it is invalid
+func orderIDParam(c *gin.Context) (int64, bool) {
+	orderID, err := strconv.ParseInt(c.Param("order_id"), 10, 64)
+	if orderID == 0 || err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, "invalid parameters: order_id is required", nil)
+		return 0, false
+	}
+
+	return orderID, true
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -package=payment -destination=handler_mock_test.go
+//
+
+// Package payment is a generated GoMock package.
+package payment
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	order "github.com/erizkiatama/gotu-assignment/internal/model/order"
+	payment "github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockpaymentService is a mock of paymentService interface.
+type MockpaymentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockpaymentServiceMockRecorder
+}
+
+// MockpaymentServiceMockRecorder is the mock recorder for MockpaymentService.
+type MockpaymentServiceMockRecorder struct {
+	mock *MockpaymentService
+}
+
+// NewMockpaymentService creates a new mock instance.
+func NewMockpaymentService(ctrl *gomock.Controller) *MockpaymentService {
+	mock := &MockpaymentService{ctrl: ctrl}
+	mock.recorder = &MockpaymentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpaymentService) EXPECT() *MockpaymentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreatePayment mocks base method.
+func (m *MockpaymentService) CreatePayment(ctx context.Context, userID, orderID int64) (*payment.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, userID, orderID)
+	ret0, _ := ret[0].(*payment.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockpaymentServiceMockRecorder) CreatePayment(ctx, userID, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockpaymentService)(nil).CreatePayment), ctx, userID, orderID)
+}
+
+// HandleWebhook mocks base method.
+func (m *MockpaymentService) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleWebhook", ctx, header, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleWebhook indicates an expected call of HandleWebhook.
+func (mr *MockpaymentServiceMockRecorder) HandleWebhook(ctx, header, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleWebhook", reflect.TypeOf((*MockpaymentService)(nil).HandleWebhook), ctx, header, body)
+}
+
+// RefundPayment mocks base method.
+func (m *MockpaymentService) RefundPayment(ctx context.Context, actorID, orderID int64, req payment.RefundPaymentRequest) (*order.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPayment", ctx, actorID, orderID, req)
+	ret0, _ := ret[0].(*order.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundPayment indicates an expected call of RefundPayment.
+func (mr *MockpaymentServiceMockRecorder) RefundPayment(ctx, actorID, orderID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPayment", reflect.TypeOf((*MockpaymentService)(nil).RefundPayment), ctx, actorID, orderID, req)
+}
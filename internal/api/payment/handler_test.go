@@ -0,0 +1,319 @@
+package payment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
+	"github.com/gin-gonic/gin"
+	gomock "go.uber.org/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newMock(paymentSvc *MockpaymentService) *Handler {
+	// The server registers the validation rules before any request is bound
+	validation.Register()
+	return New(paymentSvc)
+}
+
+func Test_handler_CreatePayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	paymentSvc := NewMockpaymentService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(paymentSvc)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		orderID    string
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    payment.PaymentResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid order id",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "abc",
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: order_id is required",
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusConflict,
+				orderID:    "1",
+			},
+			mock: func(arg args, c *gin.Context) {
+				paymentSvc.EXPECT().CreatePayment(gomock.Any(), int64(1), int64(1)).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Msg:  constant.ErrorOrderNotPayable,
+					Err:  errors.New("order is cancelled"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorOrderNotPayable,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusCreated,
+				orderID:    "1",
+			},
+			mock: func(arg args, c *gin.Context) {
+				paymentSvc.EXPECT().CreatePayment(gomock.Any(), int64(1), int64(1)).Return(&payment.PaymentResponse{
+					ID:        1,
+					OrderID:   1,
+					Provider:  "fake",
+					ChargeID:  "ch_fake_1",
+					Amount:    10000,
+					Currency:  "IDR",
+					Status:    payment.StatusPending,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			want: payment.PaymentResponse{
+				ID:        1,
+				OrderID:   1,
+				Provider:  "fake",
+				ChargeID:  "ch_fake_1",
+				Amount:    10000,
+				Currency:  "IDR",
+				Status:    payment.StatusPending,
+				CreatedAt: createdAt,
+			},
+		},
+	}
+
+	Convey("Test Payment Handler - Create Payment", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", int64(1))
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "order_id", Value: tt.args.orderID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.CreatePayment(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]payment.PaymentResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Webhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	paymentSvc := NewMockpaymentService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(paymentSvc)
+
+	body := []byte(`{"id":"evt_1","type":"charge.succeeded","data":{"charge_id":"ch_fake_1"}}`)
+
+	tests := []struct {
+		name       string
+		mock       func()
+		statusCode int
+		err        string
+	}{
+		{
+			name: "invalid signature",
+			mock: func() {
+				paymentSvc.EXPECT().HandleWebhook(gomock.Any(), gomock.Any(), body).Return(&response.ServiceError{
+					Code: http.StatusUnauthorized,
+					Msg:  constant.ErrorInvalidWebhookSignature,
+					Err:  errors.New("invalid signature"),
+				})
+			},
+			statusCode: http.StatusUnauthorized,
+			err:        constant.ErrorInvalidWebhookSignature,
+		},
+		{
+			name: "success",
+			mock: func() {
+				paymentSvc.EXPECT().HandleWebhook(gomock.Any(), gomock.Any(), body).Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	Convey("Test Payment Handler - Webhook", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Method: http.MethodPost,
+				Header: make(http.Header),
+				Body:   io.NopCloser(bytes.NewReader(body)),
+			}
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.Webhook(c)
+				So(c.Writer.Status(), ShouldEqual, tt.statusCode)
+
+				var got map[string]string
+				_ = json.Unmarshal(w.Body.Bytes(), &got)
+				So(got["detail"], ShouldEqual, tt.err)
+			})
+		}
+	})
+}
+
+func Test_handler_RefundPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	paymentSvc := NewMockpaymentService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(paymentSvc)
+
+	type args struct {
+		orderID    string
+		req        payment.RefundPaymentRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    order.OrderResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid order id",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "abc",
+			},
+			mock:    func(arg args, c *gin.Context) {},
+			wantErr: true,
+			err:     "invalid parameters: order_id is required",
+		},
+		{
+			name: "invalid parameters",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				orderID:    "1",
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, map[string]interface{}{"reason": 1}, http.MethodPost)
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal number into Go struct field RefundPaymentRequest.reason of type string",
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusConflict,
+				orderID:    "1",
+				req:        payment.RefundPaymentRequest{Reason: "damaged book"},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				paymentSvc.EXPECT().RefundPayment(gomock.Any(), int64(9), int64(1), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Msg:  constant.ErrorPaymentNotRefundable,
+					Err:  errors.New("no payment"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorPaymentNotRefundable,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusOK,
+				orderID:    "1",
+				req:        payment.RefundPaymentRequest{Reason: "damaged book"},
+			},
+			mock: func(arg args, c *gin.Context) {
+				helpers.MockJsonBinding(c, arg.req, http.MethodPost)
+				paymentSvc.EXPECT().RefundPayment(gomock.Any(), int64(9), int64(1), arg.req).Return(&order.OrderResponse{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 1000,
+					Status:     order.StatusRefunded,
+				}, nil)
+			},
+			want: order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 1000,
+				Status:     order.StatusRefunded,
+			},
+		},
+	}
+
+	Convey("Test Payment Handler - Refund Payment", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", int64(9))
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "order_id", Value: tt.args.orderID})
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.RefundPayment(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]order.OrderResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -2,13 +2,15 @@ package user
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,6 +18,10 @@ import (
 type userService interface {
 	Register(ctx context.Context, req user.RegisterRequest) (*user.TokenPairResponse, error)
 	Login(ctx context.Context, req user.LoginRequest) (*user.TokenPairResponse, error)
+	Refresh(ctx context.Context, req user.RefreshRequest) (*user.TokenPairResponse, error)
+	Logout(ctx context.Context, claim jwt.TokenClaim) error
+	LogoutAll(ctx context.Context, userID int64) error
+	Restore(ctx context.Context, userID int64) error
 }
 
 type Handler struct {
@@ -31,16 +37,13 @@ func New(userSvc userService) *Handler {
 func (h *Handler) Register(c *gin.Context) {
 	var req user.RegisterRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			Error: fmt.Sprintf("invalid parameters: %s", err.Error()),
-		})
+	if !helpers.BindJSON(c, &req) {
 		return
 	}
 
 	res, err := h.userSvc.Register(c.Request.Context(), req)
 	if err != nil {
-		log.Printf("[UserHandler.Register] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.Register", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
@@ -51,19 +54,71 @@ func (h *Handler) Register(c *gin.Context) {
 func (h *Handler) Login(c *gin.Context) {
 	var req user.LoginRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			Error: fmt.Sprintf("invalid parameters: %s", err.Error()),
-		})
+	if !helpers.BindJSON(c, &req) {
 		return
 	}
 
 	res, err := h.userSvc.Login(c.Request.Context(), req)
 	if err != nil {
-		log.Printf("[UserHandler.Login] %v", err)
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.Login", "error", err)
 		helpers.GenerateErrorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Response{Result: res})
 }
+
+func (h *Handler) Refresh(c *gin.Context) {
+	var req user.RefreshRequest
+
+	if !helpers.BindJSON(c, &req) {
+		return
+	}
+
+	res, err := h.userSvc.Refresh(c.Request.Context(), req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.Refresh", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Response{Result: res})
+}
+
+func (h *Handler) Logout(c *gin.Context) {
+	claim, _ := c.Get("token_claim")
+	if err := h.userSvc.Logout(c.Request.Context(), claim.(jwt.TokenClaim)); err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.Logout", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	if err := h.userSvc.LogoutAll(c.Request.Context(), userID.(int64)); err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.LogoutAll", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) Restore(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if userID == 0 || err != nil {
+		helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, "invalid parameters: user_id is required", nil)
+		return
+	}
+
+	if err := h.userSvc.Restore(c.Request.Context(), userID); err != nil {
+		logger.FromContext(c.Request.Context()).Error("request failed", "handler", "UserHandler.Restore", "error", err)
+		helpers.GenerateErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	user "github.com/erizkiatama/gotu-assignment/internal/model/user"
+	jwt "github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +56,49 @@ func (mr *MockuserServiceMockRecorder) Login(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockuserService)(nil).Login), ctx, req)
 }
 
+// Logout mocks base method.
+func (m *MockuserService) Logout(ctx context.Context, claim jwt.TokenClaim) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, claim)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockuserServiceMockRecorder) Logout(ctx, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockuserService)(nil).Logout), ctx, claim)
+}
+
+// LogoutAll mocks base method.
+func (m *MockuserService) LogoutAll(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockuserServiceMockRecorder) LogoutAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockuserService)(nil).LogoutAll), ctx, userID)
+}
+
+// Refresh mocks base method.
+func (m *MockuserService) Refresh(ctx context.Context, req user.RefreshRequest) (*user.TokenPairResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, req)
+	ret0, _ := ret[0].(*user.TokenPairResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockuserServiceMockRecorder) Refresh(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockuserService)(nil).Refresh), ctx, req)
+}
+
 // Register mocks base method.
 func (m *MockuserService) Register(ctx context.Context, req user.RegisterRequest) (*user.TokenPairResponse, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +113,17 @@ func (mr *MockuserServiceMockRecorder) Register(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockuserService)(nil).Register), ctx, req)
 }
+
+// Restore mocks base method.
+func (m *MockuserService) Restore(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockuserServiceMockRecorder) Restore(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockuserService)(nil).Restore), ctx, userID)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 	gomock "go.uber.org/mock/gomock"
 
@@ -19,6 +21,8 @@ import (
 )
 
 func newMock(userSvc *MockuserService) *Handler {
+	// The server registers the validation rules before any request is bound
+	validation.Register()
 	return New(userSvc)
 }
 
@@ -60,7 +64,7 @@ func Test_handler_Register(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 				req: user.RegisterRequest{
 					Email:    "test@testing.com",
-					Password: "password",
+					Password: "password123",
 					Name:     "test",
 				}},
 			mock: func(arg args, c *gin.Context) {
@@ -69,13 +73,27 @@ func Test_handler_Register(t *testing.T) {
 			wantErr: true,
 			err:     "invalid parameters: json: cannot unmarshal number into Go struct field RegisterRequest.email of type string",
 		},
+		{
+			name: "validation failed",
+			args: args{
+				statusCode: http.StatusUnprocessableEntity,
+				req: user.RegisterRequest{
+					Email:    "test",
+					Password: "password",
+				}},
+			mock: func(arg args, c *gin.Context) {
+				mockJsonBinding(c, arg.req, "POST")
+			},
+			wantErr: true,
+			err:     constant.ErrorValidationFailed,
+		},
 		{
 			name: "error from service",
 			args: args{
 				statusCode: http.StatusConflict,
 				req: user.RegisterRequest{
 					Email:    "test@testing.com",
-					Password: "password",
+					Password: "password123",
 					Name:     "test",
 				}},
 			mock: func(arg args, c *gin.Context) {
@@ -95,7 +113,7 @@ func Test_handler_Register(t *testing.T) {
 				statusCode: http.StatusCreated,
 				req: user.RegisterRequest{
 					Email:    "test@testing.com",
-					Password: "password",
+					Password: "password123",
 					Name:     "test",
 				}},
 			mock: func(arg args, c *gin.Context) {
@@ -128,7 +146,7 @@ func Test_handler_Register(t *testing.T) {
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
 					var got map[string]user.TokenPairResponse
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
@@ -229,7 +247,110 @@ func Test_handler_Login(t *testing.T) {
 				if tt.wantErr {
 					var got map[string]string
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
-					So(got["error"], ShouldEqual, tt.err)
+					So(got["detail"], ShouldEqual, tt.err)
+				} else {
+					var got map[string]user.TokenPairResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["result"], ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_handler_Refresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	userSvc := NewMockuserService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(userSvc)
+
+	type args struct {
+		req        user.RefreshRequest
+		statusCode int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(arg args, c *gin.Context)
+		want    user.TokenPairResponse
+		wantErr bool
+		err     string
+	}{
+		{
+			name: "invalid parameters",
+			args: args{
+				statusCode: http.StatusBadRequest,
+				req: user.RefreshRequest{
+					Refresh: "refresh_token",
+				},
+			},
+			mock: func(arg args, c *gin.Context) {
+				mockJsonBinding(c, map[string]interface{}{"refresh": 123}, "POST")
+			},
+			wantErr: true,
+			err:     "invalid parameters: json: cannot unmarshal number into Go struct field RefreshRequest.refresh of type string",
+		},
+		{
+			name: "error from service",
+			args: args{
+				statusCode: http.StatusUnauthorized,
+				req: user.RefreshRequest{
+					Refresh: "refresh_token",
+				},
+			},
+			mock: func(arg args, c *gin.Context) {
+				mockJsonBinding(c, arg.req, "POST")
+				userSvc.EXPECT().Refresh(gomock.Any(), arg.req).Return(nil, &response.ServiceError{
+					Code: http.StatusUnauthorized,
+					Msg:  constant.ErrorRefreshTokenReused,
+					Err:  errors.New("error refresh token reused"),
+				})
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenReused,
+		},
+		{
+			name: "success",
+			args: args{
+				statusCode: http.StatusOK,
+				req: user.RefreshRequest{
+					Refresh: "refresh_token",
+				},
+			},
+			mock: func(arg args, c *gin.Context) {
+				mockJsonBinding(c, arg.req, "POST")
+				userSvc.EXPECT().Refresh(gomock.Any(), arg.req).Return(&user.TokenPairResponse{
+					Access:  "new_access_token",
+					Refresh: "new_refresh_token",
+				}, nil)
+			},
+			want: user.TokenPairResponse{
+				Access:  "new_access_token",
+				Refresh: "new_refresh_token",
+			},
+		},
+	}
+
+	Convey("Test User Handler - Refresh", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			Convey(tt.name, func() {
+				tt.mock(tt.args, c)
+				h.Refresh(c)
+				So(w.Code, ShouldEqual, tt.args.statusCode)
+
+				if tt.wantErr {
+					var got map[string]string
+					_ = json.Unmarshal(w.Body.Bytes(), &got)
+					So(got["detail"], ShouldEqual, tt.err)
 				} else {
 					var got map[string]user.TokenPairResponse
 					_ = json.Unmarshal(w.Body.Bytes(), &got)
@@ -239,3 +360,186 @@ func Test_handler_Login(t *testing.T) {
 		}
 	})
 }
+
+func Test_handler_Logout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	userSvc := NewMockuserService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(userSvc)
+
+	claim := jwt.TokenClaim{Id: 1, TokenID: "token-id", FamilyID: "family-id"}
+	tests := []struct {
+		name       string
+		mock       func()
+		statusCode int
+		err        string
+	}{
+		{
+			name: "error from service",
+			mock: func() {
+				userSvc.EXPECT().Logout(gomock.Any(), claim).Return(&response.ServiceError{
+					Code: http.StatusInternalServerError,
+					Msg:  constant.ErrorLogoutFailed,
+					Err:  errors.New("error logout"),
+				})
+			},
+			statusCode: http.StatusInternalServerError,
+			err:        constant.ErrorLogoutFailed,
+		},
+		{
+			name: "success",
+			mock: func() {
+				userSvc.EXPECT().Logout(gomock.Any(), claim).Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	Convey("Test User Handler - Logout", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", claim.Id)
+			c.Set("token_claim", claim)
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.Logout(c)
+				So(c.Writer.Status(), ShouldEqual, tt.statusCode)
+
+				var got map[string]string
+				_ = json.Unmarshal(w.Body.Bytes(), &got)
+				So(got["detail"], ShouldEqual, tt.err)
+			})
+		}
+	})
+}
+
+func Test_handler_LogoutAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	userSvc := NewMockuserService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(userSvc)
+
+	tests := []struct {
+		name       string
+		mock       func()
+		statusCode int
+		err        string
+	}{
+		{
+			name: "error from service",
+			mock: func() {
+				userSvc.EXPECT().LogoutAll(gomock.Any(), int64(1)).Return(errors.New("error from service"))
+			},
+			statusCode: http.StatusInternalServerError,
+			err:        constant.ErrorInternalServer,
+		},
+		{
+			name: "success",
+			mock: func() {
+				userSvc.EXPECT().LogoutAll(gomock.Any(), int64(1)).Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	Convey("Test User Handler - LogoutAll", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Set("user_id", int64(1))
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.LogoutAll(c)
+				So(c.Writer.Status(), ShouldEqual, tt.statusCode)
+
+				var got map[string]string
+				_ = json.Unmarshal(w.Body.Bytes(), &got)
+				So(got["detail"], ShouldEqual, tt.err)
+			})
+		}
+	})
+}
+
+func Test_handler_Restore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCtrl := gomock.NewController(t)
+	userSvc := NewMockuserService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	h := newMock(userSvc)
+
+	tests := []struct {
+		name       string
+		userID     string
+		mock       func()
+		statusCode int
+		err        string
+	}{
+		{
+			name:       "invalid parameters",
+			userID:     "abc",
+			mock:       func() {},
+			statusCode: http.StatusBadRequest,
+			err:        "invalid parameters: user_id is required",
+		},
+		{
+			name:   "error from service",
+			userID: "2",
+			mock: func() {
+				userSvc.EXPECT().Restore(gomock.Any(), int64(2)).Return(&response.ServiceError{
+					Code: http.StatusNotFound,
+					Msg:  constant.ErrorUserIDNotFound,
+					Err:  errors.New("not found"),
+				})
+			},
+			statusCode: http.StatusNotFound,
+			err:        constant.ErrorUserIDNotFound,
+		},
+		{
+			name:   "success",
+			userID: "2",
+			mock: func() {
+				userSvc.EXPECT().Restore(gomock.Any(), int64(2)).Return(nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	Convey("Test User Handler - Restore", t, func() {
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+
+			c.Params = append(c.Params, gin.Param{Key: "user_id", Value: tt.userID})
+
+			Convey(tt.name, func() {
+				tt.mock()
+				h.Restore(c)
+				So(c.Writer.Status(), ShouldEqual, tt.statusCode)
+
+				var got map[string]string
+				_ = json.Unmarshal(w.Body.Bytes(), &got)
+				So(got["detail"], ShouldEqual, tt.err)
+			})
+		}
+	})
+}
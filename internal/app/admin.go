@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/denylist"
+
+	userRepository "github.com/erizkiatama/gotu-assignment/internal/repository/user"
+	userService "github.com/erizkiatama/gotu-assignment/internal/service/user"
+)
+
+// CreateAdmin bootstraps an admin account, granting the admin role to an existing user
+// with the same email instead when there is one
+func CreateAdmin(cfg *config.Config, req user.RegisterRequest) error {
+	if req.Email == "" {
+		return fmt.Errorf("email is required")
+	}
+
+	database := db.NewPostgresDatabase(cfg.Database.Postgres)
+	defer func() {
+		_ = database.Close()
+	}()
+
+	if cfg.FeatureFlag.EnableMigrations {
+		if err := db.RunDBMigrations(cfg.Database.Postgres, "file://scripts/migrations/"); err != nil {
+			return fmt.Errorf("failed to migrate database: %v", err)
+		}
+	}
+
+	userSvc := userService.New(userRepository.New(database), denylist.New())
+
+	userID, err := userSvc.CreateAdmin(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user is now an admin", "user_id", userID, "email", req.Email)
+	return nil
+}
@@ -1,10 +1,18 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/erizkiatama/gotu-assignment/internal/middleware"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/denylist"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/idempotency"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/payment"
 	"github.com/erizkiatama/gotu-assignment/internal/server"
 
 	userApi "github.com/erizkiatama/gotu-assignment/internal/api/user"
@@ -18,9 +26,35 @@ import (
 	orderApi "github.com/erizkiatama/gotu-assignment/internal/api/order"
 	orderRepository "github.com/erizkiatama/gotu-assignment/internal/repository/order"
 	orderService "github.com/erizkiatama/gotu-assignment/internal/service/order"
+
+	inventoryApi "github.com/erizkiatama/gotu-assignment/internal/api/inventory"
+	inventoryRepository "github.com/erizkiatama/gotu-assignment/internal/repository/inventory"
+	inventoryService "github.com/erizkiatama/gotu-assignment/internal/service/inventory"
+
+	paymentApi "github.com/erizkiatama/gotu-assignment/internal/api/payment"
+	paymentRepository "github.com/erizkiatama/gotu-assignment/internal/repository/payment"
+	paymentService "github.com/erizkiatama/gotu-assignment/internal/service/payment"
+
+	idempotencyRepository "github.com/erizkiatama/gotu-assignment/internal/repository/idempotency"
 )
 
-func Initialize(cfg *config.Config) error {
+func Initialize(cfg *config.Config, logger *slog.Logger) error {
+	// Load token signing keys, tokens are only signed with the server secret key locally and in tests
+	switch {
+	case len(cfg.Jwt.Keys) > 0:
+		if err := jwt.LoadKeys(cfg.Jwt); err != nil {
+			return fmt.Errorf("failed to load jwt keys: %v", err)
+		}
+	case !cfg.Server.AllowsSecretKeySigning():
+		return fmt.Errorf("no jwt signing key is configured for the %q environment", cfg.Server.Env)
+	}
+
+	// Initialize payment gateway
+	paymentGateway, err := payment.New(cfg.Payment)
+	if err != nil {
+		return fmt.Errorf("failed to initialize payment gateway: %v", err)
+	}
+
 	// Initialize database
 	database := db.NewPostgresDatabase(cfg.Database.Postgres)
 	defer func() {
@@ -34,25 +68,57 @@ func Initialize(cfg *config.Config) error {
 		}
 	}
 
-	// Initialize repository
+	// Initialize repository, repositories join the transaction started by the transactor
+	transactor := db.NewTransactor(database)
 	userRepo := userRepository.New(database)
 	bookRepo := bookRepository.New(database)
 	orderRepo := orderRepository.New(database)
+	inventoryRepo := inventoryRepository.New(database)
+	idempotencyRepo := idempotencyRepository.New(database)
+	paymentRepo := paymentRepository.New(database)
+
+	// Initialize token denylist
+	tokenDenylist := denylist.New()
+	if err := tokenDenylist.Reload(context.Background(), userRepo); err != nil {
+		return fmt.Errorf("failed to load token denylist: %v", err)
+	}
+	if cfg.Jwt.DenylistSyncIntervalSeconds > 0 {
+		go tokenDenylist.Sync(context.Background(), userRepo, time.Duration(cfg.Jwt.DenylistSyncIntervalSeconds)*time.Second)
+	}
+
+	// Clean up expired idempotency keys
+	if cfg.Idempotency.RetentionHours > 0 && cfg.Idempotency.CleanupIntervalSeconds > 0 {
+		go idempotency.Cleanup(
+			context.Background(),
+			idempotencyRepo,
+			time.Duration(cfg.Idempotency.RetentionHours)*time.Hour,
+			time.Duration(cfg.Idempotency.CleanupIntervalSeconds)*time.Second,
+		)
+	}
 
 	// Initialize service
-	userSvc := userService.New(userRepo)
+	userSvc := userService.New(userRepo, tokenDenylist)
 	bookSvc := bookService.New(bookRepo)
-	orderSvc := orderService.New(orderRepo)
+	orderSvc := orderService.New(transactor, orderRepo, bookRepo, inventoryRepo)
+	inventorySvc := inventoryService.New(transactor, inventoryRepo)
+	paymentSvc := paymentService.New(transactor, paymentGateway, paymentRepo, orderRepo, orderSvc)
 
 	// Initialize handler
 	userHandler := userApi.New(userSvc)
 	bookHandler := bookApi.New(bookSvc)
 	orderHandler := orderApi.New(orderSvc)
+	inventoryHandler := inventoryApi.New(inventorySvc)
+	paymentHandler := paymentApi.New(paymentSvc)
 
 	srv := server.Server{
-		UserHandler:  userHandler,
-		BookHandler:  bookHandler,
-		OrderHandler: orderHandler,
+		UserHandler:      userHandler,
+		BookHandler:      bookHandler,
+		OrderHandler:     orderHandler,
+		InventoryHandler: inventoryHandler,
+		PaymentHandler:   paymentHandler,
+		TokenDenylist:    tokenDenylist,
+		Idempotency:      middleware.Idempotency(idempotencyRepo, time.Duration(cfg.Idempotency.InFlightTimeoutSeconds)*time.Second),
+		Logger:           logger,
 	}
 
 	return srv.Run(cfg.Server.Port, cfg.Server.ShutdownTimeMillis)
@@ -1,9 +1,33 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
+// DevKeyPrefix starts the id of the signing keys meant for local development, they are refused in production
+const DevKeyPrefix = "dev-"
+
+// Environments the application runs in, see server.env
+const (
+	EnvLocal      = "local"
+	EnvTest       = "test"
+	EnvProduction = "production"
+)
+
+// asymmetricAlgorithms are the algorithms a production signing key can use, so tokens can be verified
+// with its public key alone
+var asymmetricAlgorithms = map[string]bool{
+	"RS256": true,
+	"RS384": true,
+	"RS512": true,
+	"EdDSA": true,
+}
+
 var config *Config
 
 // option defines configuration options
@@ -90,13 +114,58 @@ func Load(opts ...Option) (*Config, error) {
 	}
 
 	// Reading secret variables that will injected in production settings
-	if config.Server.Env == "production" {
+	if config.Server.Env == EnvProduction {
 		config.Database.Postgres.Host = viper.GetString("APP_POSTGRES_HOST")
 		config.Database.Postgres.Username = viper.GetString("APP_POSTGRES_USERNAME")
 		config.Database.Postgres.Password = viper.GetString("APP_POSTGRES_PASSWORD")
 		config.Database.Postgres.Database = viper.GetString("APP_POSTGRES_DATABASE")
 		config.Server.SecretKey = viper.GetString("APP_SERVER_SECRET_KEY")
+		config.Payment.WebhookSecret = viper.GetString("APP_PAYMENT_WEBHOOK_SECRET")
+		config.Jwt.ActiveKeyID = viper.GetString("APP_JWT_ACTIVE_KEY_ID")
+		config.Jwt.Keys = nil
+		if keys := viper.GetString("APP_JWT_KEYS"); keys != "" {
+			if err = json.Unmarshal([]byte(keys), &config.Jwt.Keys); err != nil {
+				return nil, fmt.Errorf("invalid APP_JWT_KEYS: %v", err)
+			}
+		}
+
+		if err = validateSigningKey(config); err != nil {
+			return nil, err
+		}
 	}
 
 	return config, nil
 }
+
+// AllowsSecretKeySigning tells whether tokens can be signed with HS256 using the server secret key
+// when no key is configured. It is only allowed locally and in tests, anywhere else every verifier
+// would need the shared secret.
+func (c ServerConfig) AllowsSecretKeySigning() bool {
+	return c.Env == EnvLocal || c.Env == EnvTest
+}
+
+// validateSigningKey makes sure tokens are signed with a production key: the active key of the
+// configured keys, which must be an RSA or Ed25519 key
+func validateSigningKey(cfg *Config) error {
+	if len(cfg.Jwt.Keys) == 0 {
+		return errors.New("no token signing key is configured")
+	}
+
+	if cfg.Jwt.ActiveKeyID == "" {
+		return errors.New("the active token signing key is not configured")
+	}
+	if strings.HasPrefix(cfg.Jwt.ActiveKeyID, DevKeyPrefix) {
+		return fmt.Errorf("the active token signing key %q is a development key", cfg.Jwt.ActiveKeyID)
+	}
+	for _, key := range cfg.Jwt.Keys {
+		if key.ID != cfg.Jwt.ActiveKeyID {
+			continue
+		}
+		if !asymmetricAlgorithms[key.Algorithm] {
+			return fmt.Errorf("the active token signing key %q uses %q, it must be RS256, RS384, RS512 or EdDSA", key.ID, key.Algorithm)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("the active token signing key %q is missing", cfg.Jwt.ActiveKeyID)
+}
@@ -30,3 +30,79 @@ func TestLoad(t *testing.T) {
 		})
 	})
 }
+
+func TestValidateSigningKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name:    "server secret key",
+			cfg:     Config{Server: ServerConfig{SecretKey: "secret"}},
+			wantErr: true,
+		},
+		{
+			name:    "no signing key",
+			cfg:     Config{},
+			wantErr: true,
+		},
+		{
+			name: "active key",
+			cfg: Config{Jwt: JwtConfig{
+				ActiveKeyID: "prod-ed25519-1",
+				Keys:        []JwtKeyConfig{{ID: "prod-ed25519-1", Algorithm: "EdDSA"}},
+			}},
+		},
+		{
+			name: "symmetric active key",
+			cfg: Config{Jwt: JwtConfig{
+				ActiveKeyID: "prod-hs256-1",
+				Keys:        []JwtKeyConfig{{ID: "prod-hs256-1", Algorithm: "HS256"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "no active key",
+			cfg: Config{Jwt: JwtConfig{
+				Keys: []JwtKeyConfig{{ID: "prod-ed25519-1"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "missing active key",
+			cfg: Config{Jwt: JwtConfig{
+				ActiveKeyID: "prod-ed25519-2",
+				Keys:        []JwtKeyConfig{{ID: "prod-ed25519-1"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "development key",
+			cfg: Config{Jwt: JwtConfig{
+				ActiveKeyID: "dev-ed25519-1",
+				Keys:        []JwtKeyConfig{{ID: "dev-ed25519-1"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test validate signing key", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				err := validateSigningKey(&tt.cfg)
+				So(err != nil, ShouldEqual, tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestAllowsSecretKeySigning(t *testing.T) {
+	Convey("Test allows secret key signing", t, func() {
+		So(ServerConfig{Env: EnvLocal}.AllowsSecretKeySigning(), ShouldBeTrue)
+		So(ServerConfig{Env: EnvTest}.AllowsSecretKeySigning(), ShouldBeTrue)
+		So(ServerConfig{Env: EnvProduction}.AllowsSecretKeySigning(), ShouldBeFalse)
+		So(ServerConfig{Env: "staging"}.AllowsSecretKeySigning(), ShouldBeFalse)
+		So(ServerConfig{}.AllowsSecretKeySigning(), ShouldBeFalse)
+	})
+}
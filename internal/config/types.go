@@ -6,6 +6,9 @@ type (
 		Database    DatabaseConfig    `yaml:"database"`
 		FeatureFlag FeatureFlagConfig `yaml:"featureFlag"`
 		Jwt         JwtConfig         `yaml:"jwt"`
+		Idempotency IdempotencyConfig `yaml:"idempotency"`
+		Payment     PaymentConfig     `yaml:"payment"`
+		Log         LogConfig         `yaml:"log"`
 	}
 
 	ServerConfig struct {
@@ -33,7 +36,44 @@ type (
 	}
 
 	JwtConfig struct {
-		AccessTokenExpiryHours  int64 `yaml:"accessTokenExpiryHours"`
-		RefreshTokenExpiryHours int64 `yaml:"refreshTokenExpiryHours"`
+		AccessTokenExpiryHours      int64          `yaml:"accessTokenExpiryHours"`
+		RefreshTokenExpiryHours     int64          `yaml:"refreshTokenExpiryHours"`
+		DenylistSyncIntervalSeconds int64          `yaml:"denylistSyncIntervalSeconds"`
+		Issuer                      string         `yaml:"issuer"`
+		Audience                    string         `yaml:"audience"`
+		LeewaySeconds               int64          `yaml:"leewaySeconds"`
+		ActiveKeyID                 string         `yaml:"activeKeyId"`
+		Keys                        []JwtKeyConfig `yaml:"keys"`
+	}
+
+	// IdempotencyConfig sets how long keys are kept and how long a request can hold a key before
+	// it is assumed to have crashed and the key can be taken by a retry
+	IdempotencyConfig struct {
+		RetentionHours         int64 `yaml:"retentionHours"`
+		CleanupIntervalSeconds int64 `yaml:"cleanupIntervalSeconds"`
+		InFlightTimeoutSeconds int64 `yaml:"inFlightTimeoutSeconds"`
+	}
+
+	// PaymentConfig selects the payment gateway, WebhookSecret verifies the signature of its notifications
+	PaymentConfig struct {
+		Provider      string `yaml:"provider"`
+		WebhookSecret string `yaml:"webhookSecret"`
+	}
+
+	// LogConfig sets the minimum level (debug, info, warn or error) and the format (json or text) of the logs
+	LogConfig struct {
+		Level  string `yaml:"level"`
+		Format string `yaml:"format"`
+	}
+
+	// JwtKeyConfig is a signing key. The PEM encoded keys are read from PrivateKeyFile and PublicKeyFile,
+	// or are given as is in PrivateKey and PublicKey.
+	JwtKeyConfig struct {
+		ID             string `yaml:"id"`
+		Algorithm      string `yaml:"algorithm"`
+		PrivateKeyFile string `yaml:"privateKeyFile"`
+		PublicKeyFile  string `yaml:"publicKeyFile"`
+		PrivateKey     string `yaml:"privateKey"`
+		PublicKey      string `yaml:"publicKey"`
 	}
 )
@@ -0,0 +1,111 @@
+package constant
+
+// Error codes are the stable, machine-readable counterpart of the error messages. Clients match on
+// them instead of the messages, so a code must never change once released.
+
+// Common error codes
+const (
+	CodeInternalServer    = "INTERNAL_SERVER_ERROR"
+	CodeInvalidParameters = "INVALID_PARAMETERS"
+	CodeValidationFailed  = "VALIDATION_FAILED"
+)
+
+// Auth error codes
+const (
+	CodeAuthorizationRequired  = "AUTHORIZATION_REQUIRED"
+	CodeInvalidToken           = "INVALID_TOKEN"
+	CodeTokenRevoked           = "TOKEN_REVOKED"
+	CodeInsufficientPermission = "INSUFFICIENT_PERMISSION"
+)
+
+// Idempotency error codes
+const (
+	CodeIdempotencyKeyTooLong    = "IDEMPOTENCY_KEY_TOO_LONG"
+	CodeReadRequestBody          = "READ_REQUEST_BODY_FAILED"
+	CodeIdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodeIdempotencyKeyReused     = "IDEMPOTENCY_KEY_REUSED"
+)
+
+// Pagination error codes
+const (
+	CodeInvalidCursor = "INVALID_CURSOR"
+)
+
+// User module error codes
+const (
+	CodeUserAlreadyExists = "USER_ALREADY_EXISTS"
+	CodeCreateUserFailed  = "CREATE_USER_FAILED"
+	CodeGenerateToken     = "GENERATE_TOKEN_FAILED"
+	CodeUserNotFound      = "USER_NOT_FOUND"
+	CodeGetUserFailed     = "GET_USER_FAILED"
+	CodePasswordNotMatch  = "PASSWORD_NOT_MATCH"
+
+	CodeInvalidRefreshToken = "INVALID_REFRESH_TOKEN"
+	CodeRefreshTokenReused  = "REFRESH_TOKEN_REUSED"
+	CodeRefreshTokenFailed  = "REFRESH_TOKEN_FAILED"
+	CodeLogoutFailed        = "LOGOUT_FAILED"
+	CodeAssignRoleFailed    = "ASSIGN_ROLE_FAILED"
+	CodeRestoreUserFailed   = "RESTORE_USER_FAILED"
+	CodeUserIDNotFound      = "USER_ID_NOT_FOUND"
+)
+
+// Book module error codes
+const (
+	CodeListBooksFailed   = "LIST_BOOKS_FAILED"
+	CodeGetBookFailed     = "GET_BOOK_FAILED"
+	CodeCreateBookFailed  = "CREATE_BOOK_FAILED"
+	CodeUpdateBookFailed  = "UPDATE_BOOK_FAILED"
+	CodeDeleteBookFailed  = "DELETE_BOOK_FAILED"
+	CodeRestoreBookFailed = "RESTORE_BOOK_FAILED"
+	CodeBookNotFound      = "BOOK_NOT_FOUND"
+	CodeInvalidBook       = "INVALID_BOOK"
+	CodeInvalidBookSort   = "INVALID_BOOK_SORT"
+	CodeSearchBooksFailed = "SEARCH_BOOKS_FAILED"
+	CodeEmptySearchQuery  = "EMPTY_SEARCH_QUERY"
+)
+
+// Order module error codes
+const (
+	CodeCreateOrderFailed       = "CREATE_ORDER_FAILED"
+	CodeCreateOrderDetailFailed = "CREATE_ORDER_DETAIL_FAILED"
+	CodeGetAllOrderFailed       = "GET_ALL_ORDER_FAILED"
+	CodeGetOrderDetailFailed    = "GET_ORDER_DETAIL_FAILED"
+	CodeOrderNotFound           = "ORDER_NOT_FOUND"
+	CodeInvalidOrderDetails     = "INVALID_ORDER_DETAILS"
+	CodeOrderBooksNotFound      = "ORDER_BOOKS_NOT_FOUND"
+	CodeOrderPriceChanged       = "ORDER_PRICE_CHANGED"
+	CodeOrderOutOfStock         = "ORDER_OUT_OF_STOCK"
+	CodeInvalidOrderDateRange   = "INVALID_ORDER_DATE_RANGE"
+	CodeInvalidOrderInclude     = "INVALID_ORDER_INCLUDE"
+
+	CodeInvalidOrderStatus           = "INVALID_ORDER_STATUS"
+	CodeInvalidOrderStatusTransition = "INVALID_ORDER_STATUS_TRANSITION"
+	CodeOrderStatusChanged           = "ORDER_STATUS_CHANGED"
+	CodeOrderStatusSetByPayment      = "ORDER_STATUS_SET_BY_PAYMENT"
+	CodeUpdateOrderStatusFailed      = "UPDATE_ORDER_STATUS_FAILED"
+	CodeOrderNotCancellable          = "ORDER_NOT_CANCELLABLE"
+	CodeCancelReasonRequired         = "CANCEL_REASON_REQUIRED"
+	CodeCancelOrderFailed            = "CANCEL_ORDER_FAILED"
+)
+
+// Inventory module error codes
+const (
+	CodeGetStockFailed         = "GET_STOCK_FAILED"
+	CodeAdjustStockFailed      = "ADJUST_STOCK_FAILED"
+	CodeInvalidStockAdjustment = "INVALID_STOCK_ADJUSTMENT"
+	CodeInsufficientStock      = "INSUFFICIENT_STOCK"
+)
+
+// Payment module error codes
+const (
+	CodeCreatePaymentFailed     = "CREATE_PAYMENT_FAILED"
+	CodeOrderNotPayable         = "ORDER_NOT_PAYABLE"
+	CodePaymentInProgress       = "PAYMENT_IN_PROGRESS"
+	CodePaymentGatewayFailed    = "PAYMENT_GATEWAY_FAILED"
+	CodePaymentNotFound         = "PAYMENT_NOT_FOUND"
+	CodeInvalidWebhookSignature = "INVALID_WEBHOOK_SIGNATURE"
+	CodeInvalidWebhookEvent     = "INVALID_WEBHOOK_EVENT"
+	CodeHandleWebhookFailed     = "HANDLE_WEBHOOK_FAILED"
+	CodePaymentNotRefundable    = "PAYMENT_NOT_REFUNDABLE"
+	CodeRefundPaymentFailed     = "REFUND_PAYMENT_FAILED"
+)
@@ -2,6 +2,33 @@ package constant
 
 var ErrorInternalServer = "internal server error"
 
+// ErrorInvalidParameters is the error of a malformed request, it is followed by the cause
+var ErrorInvalidParameters = "invalid parameters"
+
+// ErrorValidationFailed is the error of a request with fields that failed validation, the fields are listed in the details
+var ErrorValidationFailed = "validation failed"
+
+// Auth error messages
+var (
+	ErrorAuthorizationRequired  = "authorization header not given"
+	ErrorInvalidToken           = "invalid token"
+	ErrorTokenRevoked           = "token has been revoked"
+	ErrorInsufficientPermission = "insufficient permission"
+)
+
+// Idempotency error messages
+var (
+	ErrorIdempotencyKeyTooLong    = "idempotency key must be at most 255 characters"
+	ErrorReadRequestBody          = "failed to read request body"
+	ErrorIdempotencyKeyInProgress = "a request with the same idempotency key is in progress"
+	ErrorIdempotencyKeyReused     = "idempotency key has already been used for a different request"
+)
+
+// Pagination error messages
+var (
+	ErrorInvalidCursor = "invalid cursor"
+)
+
 // User module error messages
 var (
 	ErrorUserAlreadyExists = "email already exists"
@@ -10,11 +37,29 @@ var (
 	ErrorUserNotFound      = "user with that email does not found"
 	ErrorGetUserFailed     = "failed to get user details"
 	ErrorPasswordNotMatch  = "password does not match"
+
+	ErrorInvalidRefreshToken = "invalid refresh token"
+	ErrorRefreshTokenReused  = "refresh token has already been used"
+	ErrorRefreshTokenFailed  = "failed to refresh token"
+	ErrorLogoutFailed        = "failed to logout"
+	ErrorAssignRoleFailed    = "failed to assign role"
+	ErrorRestoreUserFailed   = "failed to restore user"
+	ErrorUserIDNotFound      = "user not found"
 )
 
 // Book module error messages
 var (
-	ErrorListBooksFailed = "failed to list all books"
+	ErrorListBooksFailed   = "failed to list all books"
+	ErrorGetBookFailed     = "failed to get book"
+	ErrorCreateBookFailed  = "failed to create book"
+	ErrorUpdateBookFailed  = "failed to update book"
+	ErrorDeleteBookFailed  = "failed to delete book"
+	ErrorRestoreBookFailed = "failed to restore book"
+	ErrorBookNotFound      = "book not found"
+	ErrorInvalidBook       = "title and author are required and price must not be negative"
+	ErrorInvalidBookSort   = "sort must be one of price, title or created_at and order must be asc or desc"
+	ErrorSearchBooksFailed = "failed to search books"
+	ErrorEmptySearchQuery  = "search query is required"
 )
 
 // Order module error messages
@@ -24,4 +69,41 @@ var (
 	ErrorGetAllOrderFailed       = "failed to get order list"
 	ErrorGetOrderDetailFailed    = "failed to get order detail"
 	ErrorOrderNotFound           = "order not found"
+	ErrorInvalidOrderDetails     = "order must have at least one book and every quantity must be positive"
+	ErrorOrderBooksNotFound      = "some books do not exist"
+	ErrorOrderPriceChanged       = "the price of some books has changed"
+	ErrorOrderOutOfStock         = "some books are out of stock"
+	ErrorInvalidOrderDateRange   = "from and to must be dates (YYYY-MM-DD) or RFC3339 times and from must be before to"
+	ErrorInvalidOrderInclude     = "include must be details"
+
+	ErrorInvalidOrderStatus           = "status must be one of pending, paid, fulfilled, completed, cancelled or refunded"
+	ErrorInvalidOrderStatusTransition = "order cannot move from its current status to the requested one"
+	ErrorOrderStatusChanged           = "order status has been changed by another request"
+	ErrorOrderStatusSetByPayment      = "paid and refunded are set by the payment of the order, refund it through its payment"
+	ErrorUpdateOrderStatusFailed      = "failed to update order status"
+	ErrorOrderNotCancellable          = "order can only be cancelled while it is pending"
+	ErrorCancelReasonRequired         = "reason is required to cancel an order"
+	ErrorCancelOrderFailed            = "failed to cancel order"
+)
+
+// Inventory module error messages
+var (
+	ErrorGetStockFailed         = "failed to get stock"
+	ErrorAdjustStockFailed      = "failed to adjust stock"
+	ErrorInvalidStockAdjustment = "quantity_change must not be zero and reason is required"
+	ErrorInsufficientStock      = "stock cannot go below zero"
+)
+
+// Payment module error messages
+var (
+	ErrorCreatePaymentFailed     = "failed to create payment"
+	ErrorOrderNotPayable         = "only pending orders can be paid"
+	ErrorPaymentInProgress       = "order already has a pending or settled payment"
+	ErrorPaymentGatewayFailed    = "payment gateway failed to process the request"
+	ErrorPaymentNotFound         = "payment not found"
+	ErrorInvalidWebhookSignature = "invalid webhook signature"
+	ErrorInvalidWebhookEvent     = "invalid webhook event"
+	ErrorHandleWebhookFailed     = "failed to handle payment notification"
+	ErrorPaymentNotRefundable    = "order has no settled payment to refund"
+	ErrorRefundPaymentFailed     = "failed to refund payment"
 )
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/idempotency"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	defaultReplayContentType = "application/json; charset=utf-8"
+	defaultInFlightTimeout   = time.Minute
+	attemptIDBytes           = 16
+)
+
+type idempotencyStore interface {
+	Create(ctx context.Context, req idempotency.IdempotencyKeyModel, staleBefore time.Time) (bool, error)
+	Get(ctx context.Context, userID int64, key string) (*idempotency.IdempotencyKeyModel, error)
+	Complete(ctx context.Context, req idempotency.IdempotencyKeyModel) error
+	Delete(ctx context.Context, userID int64, key, attemptID string) error
+}
+
+// responseRecorder keeps a copy of the response body written by the handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency deduplicates requests sent with an Idempotency-Key header. The first request with a key
+// is handled and its response stored, repeats of it get the stored response replayed. A repeat sent
+// while the first request is in flight gets 409 and a key reused for a different request gets 422.
+// Server errors and panics are not stored, so the request can be retried with the same key. A key held
+// longer than inFlightTimeout by a request that never completed is given to the next retry, so a crashed
+// process does not block it, and the request it was taken from can no longer complete or release it.
+// It must be registered after AuthorizeToken, keys are scoped to the user.
+func Idempotency(store idempotencyStore, inFlightTimeout time.Duration) gin.HandlerFunc {
+	if inFlightTimeout <= 0 {
+		inFlightTimeout = defaultInFlightTimeout
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeIdempotencyKeyTooLong, constant.ErrorIdempotencyKeyTooLong, nil)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			helpers.ErrorResponse(c, http.StatusBadRequest, constant.CodeReadRequestBody, constant.ErrorReadRequestBody, nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID := c.GetInt64("user_id")
+		entry := idempotency.IdempotencyKeyModel{
+			UserID:         userID,
+			IdempotencyKey: key,
+			RequestHash:    requestHash(c.Request.Method, c.Request.URL.Path, body),
+			AttemptID:      newAttemptID(),
+		}
+
+		created, err := store.Create(c.Request.Context(), entry, time.Now().UTC().Add(-inFlightTimeout))
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("failed to create idempotency key", "middleware", "Idempotency", "error", err)
+			helpers.ErrorResponse(c, http.StatusInternalServerError, constant.CodeInternalServer, constant.ErrorInternalServer, nil)
+			c.Abort()
+			return
+		}
+
+		if !created {
+			replay(c, store, entry)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			p := recover()
+			finish(c, store, entry, recorder, p != nil)
+			if p != nil {
+				panic(p)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// finish stores the response of the request for the key, or releases the key when the handler
+// panicked or failed with a server error
+func finish(c *gin.Context, store idempotencyStore, entry idempotency.IdempotencyKeyModel, recorder *responseRecorder, panicked bool) {
+	// The request context may already be canceled, the key still has to be completed or released
+	ctx := context.Background()
+	if panicked || recorder.Status() >= http.StatusInternalServerError {
+		if err := store.Delete(ctx, entry.UserID, entry.IdempotencyKey, entry.AttemptID); err != nil {
+			logFinishError(c, "failed to release idempotency key", err)
+		}
+		return
+	}
+
+	contentType := recorder.Header().Get("Content-Type")
+	entry.StatusCode = sql.NullInt64{Int64: int64(recorder.Status()), Valid: true}
+	entry.ContentType = sql.NullString{String: contentType, Valid: contentType != ""}
+	entry.ResponseBody = recorder.body.Bytes()
+	if err := store.Complete(ctx, entry); err != nil {
+		logFinishError(c, "failed to complete idempotency key", err)
+	}
+}
+
+// logFinishError logs why the key could not be completed or released. A key taken over by a retry
+// is expected once a request runs past the in-flight timeout, the retry owns it from then on.
+func logFinishError(c *gin.Context, msg string, err error) {
+	log := logger.FromContext(c.Request.Context())
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warn("idempotency key taken over by a retry", "middleware", "Idempotency", "error", err)
+		return
+	}
+	log.Error(msg, "middleware", "Idempotency", "error", err)
+}
+
+// replay responds to a repeated request with the response stored for its key
+func replay(c *gin.Context, store idempotencyStore, entry idempotency.IdempotencyKeyModel) {
+	stored, err := store.Get(c.Request.Context(), entry.UserID, entry.IdempotencyKey)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// The first request failed and released the key in the meantime
+		helpers.ErrorResponse(c, http.StatusConflict, constant.CodeIdempotencyKeyInProgress, constant.ErrorIdempotencyKeyInProgress, nil)
+	case err != nil:
+		logger.FromContext(c.Request.Context()).Error("failed to get idempotency key", "middleware", "Idempotency", "error", err)
+		helpers.ErrorResponse(c, http.StatusInternalServerError, constant.CodeInternalServer, constant.ErrorInternalServer, nil)
+	case stored.RequestHash != entry.RequestHash:
+		helpers.ErrorResponse(c, http.StatusUnprocessableEntity, constant.CodeIdempotencyKeyReused, constant.ErrorIdempotencyKeyReused, nil)
+	case !stored.StatusCode.Valid:
+		helpers.ErrorResponse(c, http.StatusConflict, constant.CodeIdempotencyKeyInProgress, constant.ErrorIdempotencyKeyInProgress, nil)
+	default:
+		contentType := defaultReplayContentType
+		if stored.ContentType.Valid {
+			contentType = stored.ContentType.String
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(int(stored.StatusCode.Int64), contentType, stored.ResponseBody)
+	}
+}
+
+func newAttemptID() string {
+	b := make([]byte, attemptIDBytes)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestHash fingerprints a request, so a key cannot be reused for a different one
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
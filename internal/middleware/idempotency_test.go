@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/idempotency"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeIdempotencyStore struct {
+	entries map[string]idempotency.IdempotencyKeyModel
+	err     error
+}
+
+func (s *fakeIdempotencyStore) Create(ctx context.Context, req idempotency.IdempotencyKeyModel, staleBefore time.Time) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	if entry, ok := s.entries[req.IdempotencyKey]; ok {
+		stale := !entry.StatusCode.Valid && entry.RequestHash == req.RequestHash && entry.CreatedAt.Before(staleBefore)
+		if !stale {
+			return false, nil
+		}
+	}
+	req.CreatedAt = time.Now().UTC()
+	s.entries[req.IdempotencyKey] = req
+	return true, nil
+}
+
+func (s *fakeIdempotencyStore) Get(ctx context.Context, userID int64, key string) (*idempotency.IdempotencyKeyModel, error) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &entry, nil
+}
+
+func (s *fakeIdempotencyStore) Complete(ctx context.Context, req idempotency.IdempotencyKeyModel) error {
+	if !s.holds(req.IdempotencyKey, req.AttemptID) {
+		return sql.ErrNoRows
+	}
+	s.entries[req.IdempotencyKey] = req
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Delete(ctx context.Context, userID int64, key, attemptID string) error {
+	if !s.holds(key, attemptID) {
+		return sql.ErrNoRows
+	}
+	delete(s.entries, key)
+	return nil
+}
+
+// holds tells whether the key is still in flight for the attempt
+func (s *fakeIdempotencyStore) holds(key, attemptID string) bool {
+	entry, ok := s.entries[key]
+	return ok && entry.AttemptID == attemptID && !entry.StatusCode.Valid
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	Convey("Test Idempotency", t, func() {
+		var calls int
+		var during func()
+		status := http.StatusCreated
+		store := &fakeIdempotencyStore{entries: make(map[string]idempotency.IdempotencyKeyModel)}
+
+		_, router := gin.CreateTestContext(httptest.NewRecorder())
+		router.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}))
+		router.POST("/order", func(c *gin.Context) {
+			c.Set("user_id", int64(1))
+		}, Idempotency(store, time.Minute), func(c *gin.Context) {
+			calls++
+			if during != nil {
+				during()
+			}
+			if status == 0 {
+				panic("handler panicked")
+			}
+			c.JSON(status, gin.H{"result": calls})
+		})
+
+		send := func(key, body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(body))
+			if key != "" {
+				req.Header.Set(IdempotencyKeyHeader, key)
+			}
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Convey("should handle every request without a key", func() {
+			send("", `{}`)
+			w := send("", `{}`)
+
+			So(calls, ShouldEqual, 2)
+			So(w.Body.String(), ShouldEqual, `{"result":2}`)
+		})
+
+		Convey("should replay the stored response of a repeated request", func() {
+			first := send("key", `{}`)
+			w := send("key", `{}`)
+
+			So(calls, ShouldEqual, 1)
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(w.Body.String(), ShouldEqual, first.Body.String())
+			So(w.Header().Get("Content-Type"), ShouldEqual, first.Header().Get("Content-Type"))
+			So(w.Header().Get(IdempotentReplayedHeader), ShouldEqual, "true")
+		})
+
+		Convey("should reject a key reused with a different body", func() {
+			send("key", `{"quantity":1}`)
+			w := send("key", `{"quantity":2}`)
+
+			So(calls, ShouldEqual, 1)
+			So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		})
+
+		Convey("should reject a repeat of a request in flight", func() {
+			store.entries["key"] = idempotency.IdempotencyKeyModel{
+				UserID:         1,
+				IdempotencyKey: "key",
+				RequestHash:    requestHash(http.MethodPost, "/order", []byte(`{}`)),
+				CreatedAt:      time.Now().UTC(),
+			}
+			w := send("key", `{}`)
+
+			So(calls, ShouldEqual, 0)
+			So(w.Code, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("should release the key when the request fails", func() {
+			status = http.StatusInternalServerError
+			send("key", `{}`)
+			So(store.entries, ShouldNotContainKey, "key")
+
+			status = http.StatusCreated
+			w := send("key", `{}`)
+			So(calls, ShouldEqual, 2)
+			So(w.Code, ShouldEqual, http.StatusCreated)
+		})
+
+		Convey("should release the key when the handler panics", func() {
+			status = 0
+			w := send("key", `{}`)
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(store.entries, ShouldNotContainKey, "key")
+
+			status = http.StatusCreated
+			w = send("key", `{}`)
+			So(calls, ShouldEqual, 2)
+			So(w.Code, ShouldEqual, http.StatusCreated)
+		})
+
+		Convey("should take over the key of a request in flight for too long", func() {
+			store.entries["key"] = idempotency.IdempotencyKeyModel{
+				UserID:         1,
+				IdempotencyKey: "key",
+				RequestHash:    requestHash(http.MethodPost, "/order", []byte(`{}`)),
+				CreatedAt:      time.Now().UTC().Add(-2 * time.Minute),
+			}
+			w := send("key", `{}`)
+
+			So(calls, ShouldEqual, 1)
+			So(w.Code, ShouldEqual, http.StatusCreated)
+		})
+
+		Convey("should leave the key to the retry that took it over", func() {
+			retry := idempotency.IdempotencyKeyModel{
+				UserID:         1,
+				IdempotencyKey: "key",
+				RequestHash:    requestHash(http.MethodPost, "/order", []byte(`{}`)),
+				AttemptID:      "retry",
+				CreatedAt:      time.Now().UTC(),
+			}
+			during = func() {
+				store.entries["key"] = retry
+			}
+
+			send("key", `{}`)
+			So(store.entries["key"], ShouldResemble, retry)
+
+			status = http.StatusInternalServerError
+			send("key", `{}`)
+			So(store.entries["key"], ShouldResemble, retry)
+		})
+
+		Convey("should reject a key that is too long", func() {
+			w := send(strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+			So(calls, ShouldEqual, 0)
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("should fail when the key cannot be stored", func() {
+			store.err = errors.New("error")
+			w := send("key", `{}`)
+
+			So(calls, ShouldEqual, 0)
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -1,37 +1,62 @@
 package middleware
 
 import (
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
-	"github.com/erizkiatama/gotu-assignment/internal/config"
-	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthorizeToken() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		var res response.Response
+type tokenDenylist interface {
+	IsRevoked(tokenID string, userID int64, issuedAt time.Time) bool
+}
 
+func AuthorizeToken(denylist tokenDenylist) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		const bearerSchema = "Bearer "
 		authHeader := c.Request.Header.Get("Authorization")
 		if authHeader == "" {
-			res.Error = "authorization header not given"
-			c.JSON(http.StatusUnauthorized, res)
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeAuthorizationRequired, constant.ErrorAuthorizationRequired, nil)
+			c.Abort()
+			return
+		}
+
+		if !strings.HasPrefix(authHeader, bearerSchema) {
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeInvalidToken, constant.ErrorInvalidToken, nil)
 			c.Abort()
 			return
 		}
 
 		tokenString := authHeader[len(bearerSchema):]
-		claim, err := jwt.AuthorizeToken(tokenString, config.Get().Server.SecretKey, false)
+		claim, err := jwt.AuthorizeToken(tokenString, jwt.TokenTypeAccess)
 		if err != nil {
-			res.Error = err.Error()
-			c.JSON(http.StatusUnauthorized, res)
+			logger.FromContext(c.Request.Context()).Warn("failed to authorize token", "middleware", "AuthorizeToken", "error", err)
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeInvalidToken, constant.ErrorInvalidToken, nil)
+			c.Abort()
+			return
+		}
+
+		if claim.TokenID == "" {
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeInvalidToken, constant.ErrorInvalidToken, nil)
+			c.Abort()
+			return
+		}
+
+		if denylist.IsRevoked(claim.TokenID, claim.Id, claim.IssuedAt) {
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeTokenRevoked, constant.ErrorTokenRevoked, nil)
 			c.Abort()
 			return
 		}
 
 		c.Set("user_id", claim.Id)
+		c.Set("token_claim", *claim)
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), slog.Int64("user_id", claim.Id)))
 		c.Next()
 	}
 }
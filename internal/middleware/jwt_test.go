@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeDenylist struct{}
+
+func (fakeDenylist) IsRevoked(tokenID string, userID int64, issuedAt time.Time) bool {
+	return false
+}
+
+func TestAuthorizeToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name: "without authorization header",
+			want: constant.ErrorAuthorizationRequired,
+		},
+		{
+			name:   "header shorter than the bearer schema",
+			header: "Bear",
+			want:   constant.ErrorInvalidToken,
+		},
+		{
+			name:   "header without the bearer schema",
+			header: "Basic dXNlcjpwYXNzd29yZA==",
+			want:   constant.ErrorInvalidToken,
+		},
+		{
+			name:   "malformed token",
+			header: "Bearer token",
+			want:   constant.ErrorInvalidToken,
+		},
+	}
+
+	Convey("Test Authorize Token", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				w := httptest.NewRecorder()
+				_, router := gin.CreateTestContext(w)
+				router.GET("/", AuthorizeToken(fakeDenylist{}), func(c *gin.Context) {
+					c.Status(http.StatusOK)
+				})
+
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.header != "" {
+					req.Header.Set("Authorization", tt.header)
+				}
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+				So(w.Body.String(), ShouldContainSubstring, tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Logger carries a logger in the request context with the request id, the method and the route of
+// the request, and logs every request once it is handled with its status and latency. It must be
+// registered after RequestID.
+func Logger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		reqLogger := base.With(
+			slog.String("request_id", c.GetString(helpers.RequestIDKey)),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+		)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		// The user id is only known once the request is authorized
+		reqLogger = logger.FromContext(c.Request.Context())
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		reqLogger.LogAttrs(c.Request.Context(), level, "request handled",
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
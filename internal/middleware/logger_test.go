@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// records decodes the records written by a json logger
+func records(buf *bytes.Buffer) []map[string]interface{} {
+	var res []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		_ = json.Unmarshal([]byte(line), &record)
+		res = append(res, record)
+	}
+	return res
+}
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		status    int
+		wantLevel string
+	}{
+		{name: "success", status: http.StatusOK, wantLevel: "INFO"},
+		{name: "client error", status: http.StatusNotFound, wantLevel: "WARN"},
+		{name: "server error", status: http.StatusInternalServerError, wantLevel: "ERROR"},
+	}
+
+	Convey("Test Logger", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				var buf bytes.Buffer
+				w := httptest.NewRecorder()
+				_, router := gin.CreateTestContext(w)
+				router.Use(RequestID(), Logger(slog.New(slog.NewJSONHandler(&buf, nil))))
+				router.GET("/order/:order_id", func(c *gin.Context) {
+					c.Request = c.Request.WithContext(logger.With(c.Request.Context(), slog.Int64("user_id", 1)))
+					logger.FromContext(c.Request.Context()).Info("handling")
+					c.Status(tt.status)
+				})
+
+				req := httptest.NewRequest(http.MethodGet, "/order/1", nil)
+				req.Header.Set(RequestIDHeader, "request-1")
+				router.ServeHTTP(w, req)
+
+				got := records(&buf)
+				So(got, ShouldHaveLength, 2)
+				for _, record := range got {
+					So(record["request_id"], ShouldEqual, "request-1")
+					So(record["method"], ShouldEqual, http.MethodGet)
+					So(record["route"], ShouldEqual, "/order/:order_id")
+					So(record["user_id"], ShouldEqual, 1)
+				}
+
+				So(got[1]["msg"], ShouldEqual, "request handled")
+				So(got[1]["level"], ShouldEqual, tt.wantLevel)
+				So(got[1]["status"], ShouldEqual, tt.status)
+				So(got[1], ShouldContainKey, "latency")
+			})
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission only lets requests through when the access token grants the given permission.
+// It must be registered after AuthorizeToken.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claim, ok := c.Get("token_claim")
+		if !ok {
+			helpers.ErrorResponse(c, http.StatusUnauthorized, constant.CodeAuthorizationRequired, constant.ErrorAuthorizationRequired, nil)
+			c.Abort()
+			return
+		}
+
+		if !claim.(jwt.TokenClaim).HasPermission(permission) {
+			helpers.ErrorResponse(c, http.StatusForbidden, constant.CodeInsufficientPermission, constant.ErrorInsufficientPermission, nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		claim      *jwt.TokenClaim
+		statusCode int
+	}{
+		{
+			name:       "without token claim",
+			claim:      nil,
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "without permission",
+			claim:      &jwt.TokenClaim{Id: 1, Permissions: []string{"order:read"}},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "with permission",
+			claim:      &jwt.TokenClaim{Id: 1, Permissions: []string{"book:write"}},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	Convey("Test Require Permission", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				w := httptest.NewRecorder()
+				_, router := gin.CreateTestContext(w)
+				router.GET("/", func(c *gin.Context) {
+					if tt.claim != nil {
+						c.Set("token_claim", *tt.claim)
+					}
+				}, RequirePermission("book:write"), func(c *gin.Context) {
+					c.Status(http.StatusOK)
+				})
+
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+				So(w.Code, ShouldEqual, tt.statusCode)
+			})
+		}
+	})
+}
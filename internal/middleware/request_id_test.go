@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{
+			name:      "without request id",
+			requestID: "",
+			generated: true,
+		},
+		{
+			name:      "with request id",
+			requestID: "request-1",
+			generated: false,
+		},
+		{
+			name:      "with too long request id",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			generated: true,
+		},
+	}
+
+	Convey("Test Request ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				var got string
+				w := httptest.NewRecorder()
+				_, router := gin.CreateTestContext(w)
+				router.GET("/", RequestID(), func(c *gin.Context) {
+					got = c.GetString(helpers.RequestIDKey)
+					c.Status(http.StatusOK)
+				})
+
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.requestID != "" {
+					req.Header.Set(RequestIDHeader, tt.requestID)
+				}
+				router.ServeHTTP(w, req)
+
+				So(w.Header().Get(RequestIDHeader), ShouldEqual, got)
+				if tt.generated {
+					So(got, ShouldHaveLength, requestIDBytes*2)
+				} else {
+					So(got, ShouldEqual, tt.requestID)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
+	"github.com/gin-gonic/gin"
+)
+
+// IncludeDeleted makes the repositories include soft deleted rows when the request
+// has the `include_deleted=true` query. It must only be registered on admin routes.
+func IncludeDeleted() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("include_deleted") == "true" {
+			c.Request = c.Request.WithContext(softdelete.WithDeleted(c.Request.Context()))
+		}
+
+		c.Next()
+	}
+}
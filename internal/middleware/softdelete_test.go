@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIncludeDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		target string
+		want   bool
+	}{
+		{
+			name:   "without query",
+			target: "/",
+			want:   false,
+		},
+		{
+			name:   "with include_deleted=false",
+			target: "/?include_deleted=false",
+			want:   false,
+		},
+		{
+			name:   "with include_deleted=true",
+			target: "/?include_deleted=true",
+			want:   true,
+		},
+	}
+
+	Convey("Test Include Deleted", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				var got bool
+
+				w := httptest.NewRecorder()
+				_, router := gin.CreateTestContext(w)
+				router.GET("/", IncludeDeleted(), func(c *gin.Context) {
+					got = softdelete.IncludeDeleted(c.Request.Context())
+				})
+
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+				So(got, ShouldEqual, tt.want)
+			})
+		}
+	})
+}
@@ -18,18 +18,97 @@ type (
 	}
 
 	BookModels []BookModel
+
+	// BookSearchModel is a book found by a search, with its relevance to the search query
+	// and the highlighted parts of it matching the query. The snippets are HTML, see BookHighlight.
+	BookSearchModel struct {
+		BookModel
+		Rank               float64        `db:"rank"`
+		TitleSnippet       sql.NullString `db:"title_snippet"`
+		DescriptionSnippet sql.NullString `db:"description_snippet"`
+	}
+
+	// BookFilter narrows down and orders the books returned by the repository.
+	// After is the position of the last book of the previous page.
+	BookFilter struct {
+		Author   string
+		MinPrice *int64
+		MaxPrice *int64
+		Sort     string
+		Desc     bool
+		After    *BookCursor
+		Limit    int
+	}
+
+	// BookCursor is the position of a book in a sorted list
+	BookCursor struct {
+		ID        int64     `json:"id"`
+		Sort      string    `json:"sort"`
+		Desc      bool      `json:"desc,omitempty"`
+		Price     int64     `json:"price,omitempty"`
+		Title     string    `json:"title,omitempty"`
+		CreatedAt time.Time `json:"created_at,omitempty"`
+	}
+)
+
+// Sort options of the book list
+const (
+	SortCreatedAt = "created_at"
+	SortPrice     = "price"
+	SortTitle     = "title"
 )
 
 // Requests
+type (
+	ListBookRequest struct {
+		Cursor   string `form:"cursor"`
+		Limit    int    `form:"limit" binding:"gte=0"`
+		Sort     string `form:"sort" binding:"omitempty,oneof=created_at price title"`
+		Order    string `form:"order" binding:"omitempty,oneof=asc desc"`
+		Author   string `form:"author"`
+		MinPrice *int64 `form:"min_price" binding:"omitempty,gte=0"`
+		MaxPrice *int64 `form:"max_price" binding:"omitempty,gte=0"`
+	}
+
+	SearchBookRequest struct {
+		Query string `form:"q" binding:"required"`
+		Limit int    `form:"limit" binding:"gte=0"`
+	}
+
+	// BookRequest is used to create a book or to replace every field of an existing one
+	BookRequest struct {
+		Title       string `json:"title" binding:"required,max=255"`
+		Author      string `json:"author" binding:"required,max=255"`
+		Description string `json:"description"`
+		Price       int64  `json:"price" binding:"gte=0"`
+	}
+
+	// PatchBookRequest only updates the fields that are present in the request body
+	PatchBookRequest struct {
+		Title       *string `json:"title" binding:"omitempty,min=1,max=255"`
+		Author      *string `json:"author" binding:"omitempty,min=1,max=255"`
+		Description *string `json:"description"`
+		Price       *int64  `json:"price" binding:"omitempty,gte=0"`
+	}
+)
 
 // Responses
 type (
 	BookResponse struct {
-		ID          int64  `json:"id"`
-		Title       string `json:"title"`
-		Author      string `json:"author"`
-		Description string `json:"description"`
-		Price       int64  `json:"price"`
+		ID          int64          `json:"id"`
+		Title       string         `json:"title"`
+		Author      string         `json:"author"`
+		Description string         `json:"description"`
+		Price       int64          `json:"price"`
+		IsDeleted   bool           `json:"is_deleted,omitempty"`
+		Highlight   *BookHighlight `json:"highlight,omitempty"`
+	}
+
+	// BookHighlight holds the parts of a book matching a search query, wrapped in <mark> tags. They are
+	// HTML: the text of the book is escaped, so the <mark> tags are the only markup.
+	BookHighlight struct {
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
 	}
 
 	BookResponses []BookResponse
@@ -0,0 +1,22 @@
+package idempotency
+
+import (
+	"database/sql"
+	"time"
+)
+
+// IdempotencyKeyModel is a request made with an Idempotency-Key header. StatusCode, ContentType
+// and ResponseBody are only set once the request has completed, to replay its response. AttemptID
+// identifies the request holding the key, a retry taking over a stale key holds it with its own.
+type IdempotencyKeyModel struct {
+	ID             int64          `db:"id"`
+	UserID         int64          `db:"user_id"`
+	IdempotencyKey string         `db:"idempotency_key"`
+	RequestHash    string         `db:"request_hash"`
+	AttemptID      string         `db:"attempt_id"`
+	StatusCode     sql.NullInt64  `db:"status_code"`
+	ContentType    sql.NullString `db:"content_type"`
+	ResponseBody   []byte         `db:"response_body"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      sql.NullTime   `db:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package inventory
+
+import "time"
+
+// StockModel is the quantity of a book left in stock
+type StockModel struct {
+	BookID int64 `db:"book_id"`
+	Stock  int64 `db:"stock"`
+}
+
+// StockChangeModel is a quantity taken from the stock of a book
+type StockChangeModel struct {
+	BookID int64
+	Qty    int64
+}
+
+// StockAdjustmentModel is the audit entry of a stock adjustment made by an admin.
+// Stock is the stock of the book after the adjustment.
+type StockAdjustmentModel struct {
+	ID        int64     `db:"id"`
+	BookID    int64     `db:"book_id"`
+	UserID    int64     `db:"user_id"`
+	QtyChange int64     `db:"quantity_change"`
+	Stock     int64     `db:"stock"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Requests
+type (
+	GetStockRequest struct {
+		Limit int `form:"limit" binding:"gte=0"`
+	}
+
+	// AdjustStockRequest adds QtyChange to the stock of a book, a negative QtyChange removes stock
+	AdjustStockRequest struct {
+		QtyChange int64  `json:"quantity_change" binding:"required"`
+		Reason    string `json:"reason" binding:"required,max=255"`
+	}
+)
+
+// Responses
+type (
+	StockResponse struct {
+		BookID      int64                     `json:"book_id"`
+		Stock       int64                     `json:"stock"`
+		Adjustments []StockAdjustmentResponse `json:"adjustments"`
+	}
+
+	StockAdjustmentResponse struct {
+		ID        int64     `json:"id"`
+		BookID    int64     `json:"book_id"`
+		UserID    int64     `json:"user_id"`
+		QtyChange int64     `json:"quantity_change"`
+		Stock     int64     `json:"stock"`
+		Reason    string    `json:"reason"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
@@ -1,49 +1,170 @@
 package order
 
+import (
+	"database/sql"
+	"time"
+)
+
+// Order statuses, see the state machine of the order service for the allowed transitions
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusFulfilled = "fulfilled"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusRefunded  = "refunded"
+)
+
+// MaxOrderLines is the maximum number of lines of an order, the maxorderlines rule of the binding enforces it
+const MaxOrderLines = 50
+
+// IncludeDetails is the include option of the order list that embeds the lines of every order
+const IncludeDetails = "details"
+
 type (
 	OrderModel struct {
-		ID         int64 `db:"id"`
-		UserID     int64 `db:"user_id"`
-		TotalQty   int64 `db:"total_quantity"`
-		TotalPrice int64 `db:"total_price"`
+		ID         int64        `db:"id"`
+		UserID     int64        `db:"user_id"`
+		TotalQty   int64        `db:"total_quantity"`
+		TotalPrice int64        `db:"total_price"`
+		Status     string       `db:"status"`
+		CreatedAt  time.Time    `db:"created_at"`
+		UpdatedAt  sql.NullTime `db:"updated_at"`
 	}
 
+	// OrderDetailModel is a line of an order. BookTitle and BookAuthor are only loaded with
+	// the lines of the order list and of the order detail.
 	OrderDetailModel struct {
-		ID      int64 `db:"id"`
-		OrderID int64 `db:"order_id"`
-		BookID  int64 `db:"book_id"`
-		Qty     int64 `db:"quantity"`
-		Price   int64 `db:"price"`
+		ID         int64  `db:"id"`
+		OrderID    int64  `db:"order_id"`
+		BookID     int64  `db:"book_id"`
+		Qty        int64  `db:"quantity"`
+		Price      int64  `db:"price"`
+		BookTitle  string `db:"book_title"`
+		BookAuthor string `db:"book_author"`
+	}
+
+	// OrderWithDetailRowModel is an order joined with one of its lines. The line columns are null
+	// when the order has no lines.
+	OrderWithDetailRowModel struct {
+		OrderModel
+		DetailID   sql.NullInt64  `db:"detail_id"`
+		BookID     sql.NullInt64  `db:"book_id"`
+		Qty        sql.NullInt64  `db:"quantity"`
+		Price      sql.NullInt64  `db:"price"`
+		BookTitle  sql.NullString `db:"book_title"`
+		BookAuthor sql.NullString `db:"book_author"`
+	}
+
+	// OrderStatusHistoryModel is a status change of an order. FromStatus is empty for the
+	// status the order was placed with and ActorID is empty for changes made by the system.
+	OrderStatusHistoryModel struct {
+		ID         int64          `db:"id"`
+		OrderID    int64          `db:"order_id"`
+		FromStatus sql.NullString `db:"from_status"`
+		ToStatus   string         `db:"to_status"`
+		ActorID    sql.NullInt64  `db:"actor_id"`
+		Reason     sql.NullString `db:"reason"`
+		CreatedAt  time.Time      `db:"created_at"`
+	}
+
+	// OrderFilter narrows down the orders of a user returned by the repository, newest first.
+	// CreatedFrom is inclusive, CreatedTo is exclusive and After is the position of the last
+	// order of the previous page.
+	OrderFilter struct {
+		Status      string
+		CreatedFrom *time.Time
+		CreatedTo   *time.Time
+		After       *OrderCursor
+		Limit       int
+	}
+
+	// OrderCursor is the position of an order in the order list
+	OrderCursor struct {
+		ID        int64     `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
 	}
 )
 
 // Requests
 type (
+	// ListOrderRequest filters the orders by status and by creation time. From and To are dates
+	// (YYYY-MM-DD) or RFC3339 times, From is inclusive and To is exclusive. Include set to
+	// IncludeDetails embeds the lines of every order.
+	ListOrderRequest struct {
+		Cursor  string `form:"cursor"`
+		Limit   int    `form:"limit" binding:"gte=0"`
+		Status  string `form:"status" binding:"omitempty,oneof=pending paid fulfilled completed cancelled refunded"`
+		From    string `form:"from"`
+		To      string `form:"to"`
+		Include string `form:"include" binding:"omitempty,oneof=details"`
+	}
+
+	// CreateOrderRequest has between 1 and MaxOrderLines lines, each of a different book
 	CreateOrderRequest struct {
-		Details []CreateOrderDetailRequest `json:"details"`
+		Details []CreateOrderDetailRequest `json:"details" binding:"required,min=1,maxorderlines,unique=BookID,dive"`
 	}
 
+	// CreateOrderDetailRequest is a line of the order. Price is optional and is the line price
+	// quoted to the customer; when set, the order is rejected if it differs from the current price.
 	CreateOrderDetailRequest struct {
-		BookID int64 `json:"book_id"`
-		Qty    int64 `json:"quantity"`
-		Price  int64 `json:"price"`
+		BookID int64 `json:"book_id" binding:"required,gt=0"`
+		Qty    int64 `json:"quantity" binding:"required,gt=0"`
+		Price  int64 `json:"price" binding:"gte=0"`
+	}
+
+	// UpdateOrderStatusRequest is a status change made by an admin. Paid and refunded are left out,
+	// only the payment of the order moves it there.
+	UpdateOrderStatusRequest struct {
+		Status string `json:"status" binding:"required,oneof=pending fulfilled completed cancelled"`
+		Reason string `json:"reason" binding:"max=255"`
+	}
+
+	CancelOrderRequest struct {
+		Reason string `json:"reason" binding:"required,max=255"`
 	}
 )
 
 // Responses
 type (
 	OrderResponse struct {
-		ID         int64                 `json:"id"`
-		UserID     int64                 `json:"user_id"`
-		TotalQty   int64                 `json:"total_quantity"`
-		TotalPrice int64                 `json:"total_price"`
-		Details    []OrderDetailResponse `json:"details"`
+		ID         int64                        `json:"id"`
+		UserID     int64                        `json:"user_id"`
+		TotalQty   int64                        `json:"total_quantity"`
+		TotalPrice int64                        `json:"total_price"`
+		Status     string                       `json:"status"`
+		CreatedAt  time.Time                    `json:"created_at"`
+		UpdatedAt  *time.Time                   `json:"updated_at,omitempty"`
+		Details    []OrderDetailResponse        `json:"details"`
+		History    []OrderStatusHistoryResponse `json:"history,omitempty"`
+	}
+
+	OrderStatusHistoryResponse struct {
+		FromStatus string    `json:"from_status,omitempty"`
+		Status     string    `json:"status"`
+		ActorID    *int64    `json:"actor_id,omitempty"`
+		Reason     string    `json:"reason,omitempty"`
+		CreatedAt  time.Time `json:"created_at"`
 	}
 
 	OrderDetailResponse struct {
-		ID     int64 `json:"id"`
-		BookID int64 `json:"book_id"`
-		Qty    int64 `json:"quantity"`
-		Price  int64 `json:"price"`
+		ID         int64  `json:"id"`
+		BookID     int64  `json:"book_id"`
+		BookTitle  string `json:"book_title,omitempty"`
+		BookAuthor string `json:"book_author,omitempty"`
+		Qty        int64  `json:"quantity"`
+		Price      int64  `json:"price"`
+	}
+
+	// BookIDsErrorDetails lists the books that made an order fail
+	BookIDsErrorDetails struct {
+		BookIDs []int64 `json:"book_ids"`
+	}
+
+	// PriceChangedErrorDetails is a line whose quoted price differs from the current price
+	PriceChangedErrorDetails struct {
+		BookID      int64 `json:"book_id"`
+		QuotedPrice int64 `json:"quoted_price"`
+		Price       int64 `json:"price"`
 	}
 )
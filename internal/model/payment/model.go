@@ -0,0 +1,47 @@
+package payment
+
+import "time"
+
+// Statuses of a payment, a payment is made pending and settled by the notifications of its gateway.
+// A payment is refunding from the moment its refund is committed until its gateway returned the charge.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusRefunding = "refunding"
+	StatusRefunded  = "refunded"
+)
+
+// PaymentModel is a charge made by a payment gateway for an order
+type PaymentModel struct {
+	ID        int64     `db:"id"`
+	OrderID   int64     `db:"order_id"`
+	Provider  string    `db:"provider"`
+	ChargeID  string    `db:"charge_id"`
+	Amount    int64     `db:"amount"`
+	Currency  string    `db:"currency"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Requests
+type (
+	RefundPaymentRequest struct {
+		Reason string `json:"reason" binding:"max=255"`
+	}
+)
+
+// Responses
+type (
+	PaymentResponse struct {
+		ID        int64     `json:"id"`
+		OrderID   int64     `json:"order_id"`
+		Provider  string    `json:"provider"`
+		ChargeID  string    `json:"charge_id"`
+		Amount    int64     `json:"amount"`
+		Currency  string    `json:"currency"`
+		Status    string    `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
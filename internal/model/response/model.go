@@ -2,15 +2,50 @@ package response
 
 // Response is the representation of http general response
 type Response struct {
-	Result interface{} `json:"result,omitempty"`
-	Error  string      `json:"error,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
 }
 
-// ServiceError is error returned by the service(s)
+// Problem is the representation of an error response as described in RFC 7807. Code and RequestID
+// are extension members, Details has the same content as in Response.
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Code      string      `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// Pagination is the metadata of a paginated list. NextCursor is only set when there are more results
+// and has to be sent back as the `cursor` query to get the next page.
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FieldError is a field of the request that failed validation. Code is the failing rule,
+// e.g. required or email.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServiceError is error returned by the service(s). Code is the HTTP status and ErrorCode is the stable
+// code of the error, see constant.Code*. Details is optional data that helps the client to handle
+// the error, e.g. the ids of the invalid items.
 type ServiceError struct {
-	Code int
-	Msg  string
-	Err  error
+	Code      int
+	ErrorCode string
+	Msg       string
+	Err       error
+	Details   interface{}
 }
 
 func (s *ServiceError) Error() string {
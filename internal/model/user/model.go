@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+// Roles and permissions seeded by the migrations
+const (
+	RoleAdmin = "admin"
+
+	PermissionBookWrite  = "book:write"
+	PermissionUserWrite  = "user:write"
+	PermissionOrderWrite = "order:write"
+)
+
 type UserModel struct {
 	ID        int64        `db:"id"`
 	Email     string       `db:"email"`
@@ -15,17 +24,54 @@ type UserModel struct {
 	IsDeleted bool         `db:"is_deleted"`
 }
 
+type RefreshTokenModel struct {
+	ID        int64        `db:"id"`
+	UserID    int64        `db:"user_id"`
+	FamilyID  string       `db:"family_id"`
+	TokenID   string       `db:"token_id"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	IsUsed    bool         `db:"is_used"`
+	IsRevoked bool         `db:"is_revoked"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	IsDeleted bool         `db:"is_deleted"`
+}
+
+// UserPermissionModel is a role of the user with one of the permissions granted by it
+type UserPermissionModel struct {
+	Role       string         `db:"role"`
+	Permission sql.NullString `db:"permission"`
+}
+
+// RevokedTokenModel is an entry of the token denylist. An entry without TokenID
+// revokes every token of the user issued up to CreatedAt.
+type RevokedTokenModel struct {
+	ID        int64          `db:"id"`
+	UserID    int64          `db:"user_id"`
+	TokenID   sql.NullString `db:"token_id"`
+	ExpiresAt time.Time      `db:"expires_at"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt sql.NullTime   `db:"updated_at"`
+	IsDeleted bool           `db:"is_deleted"`
+}
+
 // Requests
 type (
+	// RegisterRequest is the new user. The password has to be at least 8 characters and at most
+	// 72 bytes, and contain a letter and a digit.
 	RegisterRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Name     string `json:"name"`
+		Email    string `json:"email" binding:"required,email,max=255"`
+		Password string `json:"password" binding:"required,password"`
+		Name     string `json:"name" binding:"required,max=255"`
 	}
 
 	LoginRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	RefreshRequest struct {
+		Refresh string `json:"refresh" binding:"required"`
 	}
 )
 
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres errors the services handle, see Translate. They are matched with errors.Is.
+var (
+	ErrUniqueViolation      = errors.New("unique violation")
+	ErrForeignKeyViolation  = errors.New("foreign key violation")
+	ErrCheckViolation       = errors.New("check violation")
+	ErrSerializationFailure = errors.New("serialization failure")
+	ErrDeadlock             = errors.New("deadlock detected")
+	ErrQueryCanceled        = errors.New("query canceled")
+)
+
+// sqlStates maps the SQLSTATE codes of Postgres to the errors above
+var sqlStates = map[pq.ErrorCode]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23514": ErrCheckViolation,
+	"40001": ErrSerializationFailure,
+	"40P01": ErrDeadlock,
+	"57014": ErrQueryCanceled,
+}
+
+// Error is a Postgres error translated into one of the errors above. It still unwraps to the
+// *pq.Error it was translated from.
+type Error struct {
+	// Kind is the error it was translated into, e.g. ErrUniqueViolation
+	Kind error
+	// Constraint is the name of the violated constraint or index, it is empty for the errors
+	// that are not constraint violations
+	Constraint string
+	Err        *pq.Error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Translate translates a Postgres error whose SQLSTATE has an error above into an *Error.
+// Any other error, including one already translated, is returned as is.
+func Translate(err error) error {
+	var translated *Error
+	if errors.As(err, &translated) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	kind, ok := sqlStates[pqErr.Code]
+	if !ok {
+		return err
+	}
+
+	return &Error{
+		Kind:       kind,
+		Constraint: pqErr.Constraint,
+		Err:        pqErr,
+	}
+}
+
+// Constraint returns the name of the constraint or index violated by err, or an empty string
+// when err is not a translated constraint violation.
+func Constraint(err error) string {
+	var translated *Error
+	if errors.As(err, &translated) {
+		return translated.Constraint
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		code pq.ErrorCode
+		want error
+	}{
+		{code: "23505", want: ErrUniqueViolation},
+		{code: "23503", want: ErrForeignKeyViolation},
+		{code: "23514", want: ErrCheckViolation},
+		{code: "40001", want: ErrSerializationFailure},
+		{code: "40P01", want: ErrDeadlock},
+		{code: "57014", want: ErrQueryCanceled},
+	}
+
+	Convey("Translate", t, func() {
+		Convey("should translate the postgres errors", func() {
+			for _, tt := range tests {
+				pqErr := &pq.Error{Code: tt.code, Constraint: "constraint"}
+				err := fmt.Errorf("[Repo.Method] failed to execute query: %w", Translate(pqErr))
+
+				So(errors.Is(err, tt.want), ShouldBeTrue)
+				So(Constraint(err), ShouldEqual, "constraint")
+
+				var unwrapped *pq.Error
+				So(errors.As(err, &unwrapped), ShouldBeTrue)
+				So(unwrapped, ShouldEqual, pqErr)
+			}
+		})
+
+		Convey("should not match another error", func() {
+			err := Translate(&pq.Error{Code: "23505"})
+
+			So(errors.Is(err, ErrCheckViolation), ShouldBeFalse)
+		})
+
+		Convey("should keep the postgres errors without a translation", func() {
+			pqErr := &pq.Error{Code: "42P01"}
+
+			So(Translate(pqErr), ShouldEqual, pqErr)
+			So(Constraint(pqErr), ShouldBeEmpty)
+		})
+
+		Convey("should keep the other errors", func() {
+			So(Translate(sql.ErrNoRows), ShouldEqual, sql.ErrNoRows)
+			So(Translate(nil), ShouldBeNil)
+		})
+
+		Convey("should not translate an error twice", func() {
+			err := fmt.Errorf("error: %w", Translate(&pq.Error{Code: "40P01"}))
+
+			So(Translate(err), ShouldEqual, err)
+		})
+	})
+}
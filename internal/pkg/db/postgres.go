@@ -2,7 +2,7 @@ package db
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/erizkiatama/gotu-assignment/internal/config"
 	"github.com/golang-migrate/migrate/v4"
@@ -34,7 +34,7 @@ func RunDBMigrations(config config.PostgresConfig, url string) error {
 		config.Database,
 	)
 
-	log.Println("start migrating schema...")
+	slog.Info("start migrating schema")
 	migration, err := migrate.New(url, dsn)
 	if err != nil {
 		panic(err)
@@ -44,6 +44,6 @@ func RunDBMigrations(config config.PostgresConfig, url string) error {
 		panic(err)
 	}
 
-	log.Println("migration success")
+	slog.Info("migration success")
 	return nil
 }
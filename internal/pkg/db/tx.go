@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// Executor runs queries, it is implemented by both *sqlx.DB and *sqlx.Tx so repositories
+// can be constructed with either of them.
+type Executor interface {
+	sqlx.ExtContext
+	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
+}
+
+// Transactor is a unit of work, it runs several repository calls in one transaction.
+type Transactor struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+// WithinTx runs fn in a transaction carried by the context passed to it. The transaction is
+// committed when fn returns nil and rolled back when fn returns an error or panics. A call
+// made within a transaction joins it instead of starting a new one.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("[Transactor.WithinTx] failed to begin transaction: %w", Translate(err))
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("[Transactor.WithinTx] failed to commit transaction: %w", Translate(err))
+	}
+
+	return nil
+}
+
+// Conn returns the transaction carried by ctx, or executor when ctx is not within a transaction.
+func Conn(ctx context.Context, executor Executor) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return executor
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransactor_WithinTx(t *testing.T) {
+	Convey("WithinTx", t, func() {
+		mockDB, mock, _ := sqlmock.New()
+		defer mockDB.Close()
+
+		database := sqlx.NewDb(mockDB, "sqlmock")
+		transactor := NewTransactor(database)
+
+		Convey("should commit when fn succeeds", func() {
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				_, ok := Conn(ctx, database).(*sqlx.Tx)
+				So(ok, ShouldBeTrue)
+				return nil
+			})
+
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("should rollback and return the error when fn fails", func() {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+
+			fnErr := errors.New("error")
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				return fnErr
+			})
+
+			So(err, ShouldEqual, fnErr)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("should rollback and repanic when fn panics", func() {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+
+			So(func() {
+				_ = transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+					panic("boom")
+				})
+			}, ShouldPanicWith, "boom")
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("should join the outer transaction", func() {
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				outer := Conn(ctx, database)
+				return transactor.WithinTx(ctx, func(ctx context.Context) error {
+					So(Conn(ctx, database), ShouldEqual, outer)
+					return nil
+				})
+			})
+
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("should fail when the transaction cannot begin", func() {
+			mock.ExpectBegin().WillReturnError(errors.New("error"))
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				return nil
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should fail when the transaction cannot commit", func() {
+			mock.ExpectBegin()
+			mock.ExpectCommit().WillReturnError(errors.New("error"))
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				return nil
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestConn(t *testing.T) {
+	Convey("Conn should return the executor outside of a transaction", t, func() {
+		mockDB, _, _ := sqlmock.New()
+		defer mockDB.Close()
+
+		database := sqlx.NewDb(mockDB, "sqlmock")
+		So(Conn(context.Background(), database), ShouldEqual, database)
+	})
+}
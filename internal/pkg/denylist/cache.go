@@ -0,0 +1,102 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+)
+
+type revokedTokenSource interface {
+	GetRevokedTokens(ctx context.Context) ([]user.RevokedTokenModel, error)
+}
+
+// Cache is the in-process copy of the persisted token denylist
+type Cache struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[int64]time.Time
+}
+
+func New() *Cache {
+	return &Cache{
+		tokens: make(map[string]time.Time),
+		users:  make(map[int64]time.Time),
+	}
+}
+
+// Add puts a revoked token entry into the cache. An entry without token id revokes
+// every token of the user issued up to the time the entry was created.
+func (c *Cache) Add(entry user.RevokedTokenModel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(entry)
+}
+
+func (c *Cache) add(entry user.RevokedTokenModel) {
+	if entry.TokenID.Valid {
+		c.tokens[entry.TokenID.String] = entry.ExpiresAt
+		return
+	}
+
+	if revokedAt, ok := c.users[entry.UserID]; !ok || entry.CreatedAt.After(revokedAt) {
+		c.users[entry.UserID] = entry.CreatedAt
+	}
+}
+
+// IsRevoked reports whether the token with the given id, owner and issue time has been revoked.
+// A logout from all sessions revokes the tokens issued before it, not the ones issued right after.
+func (c *Cache) IsRevoked(tokenID string, userID int64, issuedAt time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.tokens[tokenID]; ok {
+		return true
+	}
+
+	if revokedAt, ok := c.users[userID]; ok && issuedAt.Before(revokedAt) {
+		return true
+	}
+
+	return false
+}
+
+// Reload replaces the cache content with the non-expired entries of the persisted denylist
+func (c *Cache) Reload(ctx context.Context, src revokedTokenSource) error {
+	entries, err := src.GetRevokedTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens = make(map[string]time.Time, len(entries))
+	c.users = make(map[int64]time.Time)
+	for _, entry := range entries {
+		c.add(entry)
+	}
+
+	return nil
+}
+
+// Sync periodically reloads the cache so revocations made by other instances are picked up.
+// It blocks until the context is done.
+func (c *Cache) Sync(ctx context.Context, src revokedTokenSource, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx, src); err != nil {
+				logger.FromContext(ctx).Error("failed to reload denylist", "job", "Denylist.Sync", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package denylist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeSource struct {
+	entries []user.RevokedTokenModel
+	err     error
+}
+
+func (f fakeSource) GetRevokedTokens(ctx context.Context) ([]user.RevokedTokenModel, error) {
+	return f.entries, f.err
+}
+
+func TestCache_IsRevoked(t *testing.T) {
+	revokedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	Convey("IsRevoked", t, func() {
+		cache := New()
+		cache.Add(user.RevokedTokenModel{
+			UserID:    1,
+			TokenID:   sql.NullString{String: "token-id", Valid: true},
+			ExpiresAt: revokedAt.Add(time.Hour),
+		})
+		cache.Add(user.RevokedTokenModel{
+			UserID:    2,
+			ExpiresAt: revokedAt.Add(24 * time.Hour),
+			CreatedAt: revokedAt,
+		})
+
+		Convey("should revoke token by token id", func() {
+			So(cache.IsRevoked("token-id", 1, revokedAt), ShouldBeTrue)
+			So(cache.IsRevoked("other-token-id", 1, revokedAt), ShouldBeFalse)
+		})
+
+		Convey("should revoke every token issued before user revocation", func() {
+			So(cache.IsRevoked("other-token-id", 2, revokedAt.Add(-time.Hour)), ShouldBeTrue)
+			So(cache.IsRevoked("other-token-id", 2, revokedAt.Add(-time.Millisecond)), ShouldBeTrue)
+			So(cache.IsRevoked("other-token-id", 2, revokedAt), ShouldBeFalse)
+			So(cache.IsRevoked("other-token-id", 2, revokedAt.Add(time.Millisecond)), ShouldBeFalse)
+		})
+
+		Convey("should keep the latest user revocation", func() {
+			cache.Add(user.RevokedTokenModel{
+				UserID:    2,
+				CreatedAt: revokedAt.Add(-time.Hour),
+			})
+			So(cache.IsRevoked("other-token-id", 2, revokedAt.Add(-time.Minute)), ShouldBeTrue)
+		})
+	})
+}
+
+func TestCache_Reload(t *testing.T) {
+	Convey("Reload", t, func() {
+		cache := New()
+		cache.Add(user.RevokedTokenModel{
+			UserID:  1,
+			TokenID: sql.NullString{String: "stale-token-id", Valid: true},
+		})
+
+		Convey("should replace cache content", func() {
+			err := cache.Reload(context.Background(), fakeSource{entries: []user.RevokedTokenModel{
+				{UserID: 1, TokenID: sql.NullString{String: "token-id", Valid: true}},
+			}})
+
+			So(err, ShouldBeNil)
+			So(cache.IsRevoked("token-id", 1, time.Now()), ShouldBeTrue)
+			So(cache.IsRevoked("stale-token-id", 1, time.Now()), ShouldBeFalse)
+		})
+
+		Convey("should keep cache content on error", func() {
+			err := cache.Reload(context.Background(), fakeSource{err: errors.New("error")})
+
+			So(err, ShouldNotBeNil)
+			So(cache.IsRevoked("stale-token-id", 1, time.Now()), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag returns a strong entity tag of a resource identified by its id and last modification time
+func ETag(id int64, lastModified time.Time) string {
+	return fmt.Sprintf(`"%d-%d"`, id, lastModified.UnixNano())
+}
+
+// IsNotModified reports whether the conditional request headers match the current version of the
+// resource, so the request can be answered with 304 Not Modified. If-None-Match takes precedence
+// over If-Modified-Since as described in RFC 9110.
+func IsNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// HTTP dates only have a second precision
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestETag(t *testing.T) {
+	Convey("ETag", t, func() {
+		lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		So(ETag(1, lastModified), ShouldEqual, `"1-1704067200000000000"`)
+		So(ETag(1, lastModified.Add(time.Microsecond)), ShouldNotEqual, ETag(1, lastModified))
+	})
+}
+
+func TestIsNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 1, 1, 10, 0, 0, 500, time.UTC)
+	etag := ETag(1, lastModified)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{
+			name: "without conditional headers",
+			want: false,
+		},
+		{
+			name:    "matching etag",
+			headers: map[string]string{"If-None-Match": etag},
+			want:    true,
+		},
+		{
+			name:    "matching weak etag in a list",
+			headers: map[string]string{"If-None-Match": `"1-1", W/` + etag},
+			want:    true,
+		},
+		{
+			name:    "any etag",
+			headers: map[string]string{"If-None-Match": "*"},
+			want:    true,
+		},
+		{
+			name:    "stale etag takes precedence over if-modified-since",
+			headers: map[string]string{"If-None-Match": `"1-1"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:    false,
+		},
+		{
+			name:    "not modified since",
+			headers: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:    true,
+		},
+		{
+			name:    "modified since",
+			headers: map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			want:    false,
+		},
+		{
+			name:    "invalid if-modified-since",
+			headers: map[string]string{"If-Modified-Since": "yesterday"},
+			want:    false,
+		},
+	}
+
+	Convey("IsNotModified", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				for k, v := range tt.headers {
+					r.Header.Set(k, v)
+				}
+
+				So(IsNotModified(r, etag, lastModified), ShouldEqual, tt.want)
+			})
+		}
+	})
+}
@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/i18n"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -23,21 +26,94 @@ func EncryptPassword(password []byte) (string, error) {
 	return string(hash), nil
 }
 
+// RequestIDKey is the key of the id of the request in the gin context, see middleware.RequestID
+const RequestIDKey = "request_id"
+
+// Content types of the error responses
+const (
+	ContentTypeProblem = "application/problem+json"
+	ContentTypeJSON    = "application/json"
+)
+
+// GenerateErrorResponse responds with the service error, any other error is an internal server error
 func GenerateErrorResponse(c *gin.Context, err error) {
 	var svcErr *response.ServiceError
 
 	if errors.As(err, &svcErr) {
-		c.JSON(svcErr.Code, response.Response{
-			Error: svcErr.Msg,
+		code := svcErr.ErrorCode
+		if code == "" {
+			code = codeFromStatus(svcErr.Code)
+		}
+		ErrorResponse(c, svcErr.Code, code, svcErr.Msg, svcErr.Details)
+		return
+	}
+
+	ErrorResponse(c, http.StatusInternalServerError, constant.CodeInternalServer, constant.ErrorInternalServer, nil)
+}
+
+// ErrorResponse responds with an application/problem+json error as described in RFC 7807. Clients
+// that only accept application/json still get the error in the response.Response envelope. msg is
+// translated into the locale requested by the Accept-Language header.
+func ErrorResponse(c *gin.Context, status int, code, msg string, details interface{}) {
+	locale := i18n.Locale(c.GetHeader("Accept-Language"))
+	msg = i18n.Error(locale, code, msg)
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept, Accept-Language")
+
+	if c.NegotiateFormat(ContentTypeProblem, ContentTypeJSON) == ContentTypeJSON {
+		c.JSON(status, response.Response{
+			Error:   msg,
+			Code:    code,
+			Details: details,
 		})
 		return
 	}
 
-	c.JSON(http.StatusInternalServerError, response.Response{
-		Error: constant.ErrorInternalServer,
+	c.Header("Content-Type", ContentTypeProblem)
+	c.JSON(status, response.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    msg,
+		Code:      code,
+		RequestID: c.GetString(RequestIDKey),
+		Details:   details,
 	})
 }
 
+// codeFromStatus is the error code of a service error without one, e.g. NOT_FOUND
+func codeFromStatus(status int) string {
+	if status >= http.StatusInternalServerError {
+		return constant.CodeInternalServer
+	}
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// BindJSON binds and validates the request body into obj. When it fails, it responds with the fields
+// that failed validation or with a bad request when the body is malformed, and returns false.
+func BindJSON(c *gin.Context, obj interface{}) bool {
+	return handleBindError(c, c.ShouldBindJSON(obj))
+}
+
+// BindQuery binds and validates the query parameters into obj, responding like BindJSON when it fails
+func BindQuery(c *gin.Context, obj interface{}) bool {
+	return handleBindError(c, c.ShouldBindQuery(obj))
+}
+
+func handleBindError(c *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if fields, ok := validation.FieldErrors(err, i18n.Locale(c.GetHeader("Accept-Language"))); ok {
+		ErrorResponse(c, http.StatusUnprocessableEntity, constant.CodeValidationFailed, constant.ErrorValidationFailed, fields)
+		return false
+	}
+
+	ErrorResponse(c, http.StatusBadRequest, constant.CodeInvalidParameters, fmt.Sprintf("%s: %s", constant.ErrorInvalidParameters, err.Error()), nil)
+	return false
+}
+
 func MockJsonBinding(c *gin.Context, content interface{}, method string) {
 	c.Request.Method = method
 	c.Request.Header.Set("Content-Type", "application/json")
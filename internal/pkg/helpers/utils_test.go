@@ -5,9 +5,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 	. "github.com/smartystreets/goconvey/convey"
 	"golang.org/x/crypto/bcrypt"
@@ -34,39 +37,245 @@ func TestEncryptPassword(t *testing.T) {
 	})
 }
 func TestGenerateErrorResponse(t *testing.T) {
-	Convey("When given a service error", t, func() {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		err := &response.ServiceError{
-			Code: http.StatusBadRequest,
-			Msg:  "Bad request",
+	tests := []struct {
+		name            string
+		accept          string
+		acceptLanguage  string
+		requestID       string
+		err             error
+		wantStatus      int
+		wantContentType string
+		wantLanguage    string
+		wantBody        string
+	}{
+		{
+			name: "service error",
+			err: &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeUserAlreadyExists,
+				Msg:       constant.ErrorUserAlreadyExists,
+			},
+			requestID:       "request-1",
+			wantStatus:      http.StatusConflict,
+			wantContentType: ContentTypeProblem,
+			wantBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"email already exists",` +
+				`"code":"USER_ALREADY_EXISTS","request_id":"request-1"}`,
+		},
+		{
+			name: "service error with details",
+			err: &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeOrderBooksNotFound,
+				Msg:       constant.ErrorOrderBooksNotFound,
+				Details:   map[string][]int64{"book_ids": {1, 2}},
+			},
+			accept:          "application/problem+json",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: ContentTypeProblem,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"some books do not exist",` +
+				`"code":"ORDER_BOOKS_NOT_FOUND","details":{"book_ids":[1,2]}}`,
+		},
+		{
+			name: "service error without error code",
+			err: &response.ServiceError{
+				Code: http.StatusNotFound,
+				Msg:  "Not found",
+			},
+			wantStatus:      http.StatusNotFound,
+			wantContentType: ContentTypeProblem,
+			wantBody:        `{"type":"about:blank","title":"Not Found","status":404,"detail":"Not found","code":"NOT_FOUND"}`,
+		},
+		{
+			name:            "non-service error",
+			err:             errors.New("Some error"),
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: ContentTypeProblem,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error",` +
+				`"code":"INTERNAL_SERVER_ERROR"}`,
+		},
+		{
+			name: "service error for a client accepting json",
+			err: &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeOrderBooksNotFound,
+				Msg:       constant.ErrorOrderBooksNotFound,
+				Details:   map[string][]int64{"book_ids": {1, 2}},
+			},
+			accept:          "application/json",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"error":"some books do not exist","code":"ORDER_BOOKS_NOT_FOUND","details":{"book_ids":[1,2]}}`,
+		},
+		{
+			name:            "non-service error for a client accepting json",
+			err:             errors.New("Some error"),
+			accept:          "application/json",
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"error":"internal server error","code":"INTERNAL_SERVER_ERROR"}`,
+		},
+		{
+			name: "service error in indonesian",
+			err: &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeUserAlreadyExists,
+				Msg:       constant.ErrorUserAlreadyExists,
+			},
+			acceptLanguage:  "id-ID,id;q=0.9,en;q=0.8",
+			wantStatus:      http.StatusConflict,
+			wantContentType: ContentTypeProblem,
+			wantLanguage:    "id",
+			wantBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"email sudah terdaftar",` +
+				`"code":"USER_ALREADY_EXISTS"}`,
+		},
+		{
+			name:            "non-service error in an unsupported language",
+			err:             errors.New("Some error"),
+			acceptLanguage:  "fr-FR",
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: ContentTypeProblem,
+			wantLanguage:    "en",
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error",` +
+				`"code":"INTERNAL_SERVER_ERROR"}`,
+		},
+	}
+
+	Convey("GenerateErrorResponse", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.accept != "" {
+					c.Request.Header.Set("Accept", tt.accept)
+				}
+				if tt.acceptLanguage != "" {
+					c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+				}
+				if tt.requestID != "" {
+					c.Set(RequestIDKey, tt.requestID)
+				}
+
+				GenerateErrorResponse(c, tt.err)
+
+				So(w.Code, ShouldEqual, tt.wantStatus)
+				So(w.Header().Get("Content-Type"), ShouldStartWith, tt.wantContentType)
+				if tt.wantLanguage != "" {
+					So(w.Header().Get("Content-Language"), ShouldEqual, tt.wantLanguage)
+				}
+				So(w.Body.String(), ShouldEqual, tt.wantBody)
+			})
 		}
+	})
+}
 
-		GenerateErrorResponse(c, err)
+func TestBindJSON(t *testing.T) {
+	validation.Register()
 
-		Convey("Should return the service error as JSON response", func() {
-			resp := w.Body.String()
-			expectedResp := `{"error":"Bad request"}`
+	type request struct {
+		Email string `json:"email" binding:"required,email"`
+		Qty   int64  `json:"quantity" binding:"gt=0"`
+	}
 
-			So(resp, ShouldEqual, expectedResp)
-		})
+	tests := []struct {
+		name           string
+		body           string
+		acceptLanguage string
+		want           bool
+		wantStatus     int
+		wantBody       string
+	}{
+		{
+			name:       "valid request",
+			body:       `{"email":"test@testing.com","quantity":1}`,
+			want:       true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed request",
+			body:       `{"email":1}`,
+			want:       false,
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"invalid parameters: json: cannot unmarshal number into Go struct field request.email of type string",` +
+				`"code":"INVALID_PARAMETERS"}`,
+		},
+		{
+			name:       "invalid fields",
+			body:       `{"email":"test","quantity":0}`,
+			want:       false,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,` +
+				`"detail":"validation failed","code":"VALIDATION_FAILED","details":[` +
+				`{"field":"email","code":"email","message":"must be a valid email address"},` +
+				`{"field":"quantity","code":"gt","message":"must be greater than 0"}]}`,
+		},
+		{
+			name:           "invalid fields in indonesian",
+			body:           `{"email":"test","quantity":0}`,
+			acceptLanguage: "id",
+			want:           false,
+			wantStatus:     http.StatusUnprocessableEntity,
+			wantBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,` +
+				`"detail":"validasi gagal","code":"VALIDATION_FAILED","details":[` +
+				`{"field":"email","code":"email","message":"harus berupa alamat email yang valid"},` +
+				`{"field":"quantity","code":"gt","message":"harus lebih besar dari 0"}]}`,
+		},
+	}
+
+	Convey("BindJSON", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+				c.Request.Header.Set("Content-Type", "application/json")
+				if tt.acceptLanguage != "" {
+					c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+				}
+
+				var req request
+				So(BindJSON(c, &req), ShouldEqual, tt.want)
+				So(w.Code, ShouldEqual, tt.wantStatus)
+				So(w.Body.String(), ShouldEqual, tt.wantBody)
+			})
+		}
 	})
+}
 
-	Convey("When given a non-service error", t, func() {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		err := errors.New("Some error")
+func TestBindQuery(t *testing.T) {
+	validation.Register()
 
-		GenerateErrorResponse(c, err)
+	type request struct {
+		Sort string `form:"sort" binding:"omitempty,oneof=price title"`
+	}
 
-		Convey("Should return the internal server error as JSON response", func() {
-			resp := w.Body.String()
-			expectedResp := `{"error":"internal server error"}`
+	Convey("BindQuery", t, func() {
+		Convey("valid query", func() {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/?sort=price", nil)
 
-			So(resp, ShouldEqual, expectedResp)
+			var req request
+			So(BindQuery(c, &req), ShouldBeTrue)
+			So(req.Sort, ShouldEqual, "price")
+		})
+
+		Convey("invalid query", func() {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/?sort=author", nil)
+
+			var req request
+			So(BindQuery(c, &req), ShouldBeFalse)
+			So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+			So(w.Body.String(), ShouldEqual,
+				`{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed",`+
+					`"code":"VALIDATION_FAILED","details":[{"field":"sort","code":"oneof","message":"must be one of price, title"}]}`)
 		})
 	})
 }
+
 func TestMockJsonBinding(t *testing.T) {
 	Convey("When given a valid content and method", t, func() {
 		content := struct {
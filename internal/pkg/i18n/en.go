@@ -0,0 +1,114 @@
+package i18n
+
+import "github.com/erizkiatama/gotu-assignment/internal/constant"
+
+// en is the default catalog, its error messages are the ones of the constant package
+var en = map[string]string{
+	// Common errors
+	constant.CodeInternalServer:    constant.ErrorInternalServer,
+	constant.CodeInvalidParameters: constant.ErrorInvalidParameters,
+	constant.CodeValidationFailed:  constant.ErrorValidationFailed,
+
+	// Auth errors
+	constant.CodeAuthorizationRequired:  constant.ErrorAuthorizationRequired,
+	constant.CodeInvalidToken:           constant.ErrorInvalidToken,
+	constant.CodeTokenRevoked:           constant.ErrorTokenRevoked,
+	constant.CodeInsufficientPermission: constant.ErrorInsufficientPermission,
+
+	// Idempotency errors
+	constant.CodeIdempotencyKeyTooLong:    constant.ErrorIdempotencyKeyTooLong,
+	constant.CodeReadRequestBody:          constant.ErrorReadRequestBody,
+	constant.CodeIdempotencyKeyInProgress: constant.ErrorIdempotencyKeyInProgress,
+	constant.CodeIdempotencyKeyReused:     constant.ErrorIdempotencyKeyReused,
+
+	// Pagination errors
+	constant.CodeInvalidCursor: constant.ErrorInvalidCursor,
+
+	// User module errors
+	constant.CodeUserAlreadyExists:   constant.ErrorUserAlreadyExists,
+	constant.CodeCreateUserFailed:    constant.ErrorCreateUserFailed,
+	constant.CodeGenerateToken:       constant.ErrorGenerateToken,
+	constant.CodeUserNotFound:        constant.ErrorUserNotFound,
+	constant.CodeGetUserFailed:       constant.ErrorGetUserFailed,
+	constant.CodePasswordNotMatch:    constant.ErrorPasswordNotMatch,
+	constant.CodeInvalidRefreshToken: constant.ErrorInvalidRefreshToken,
+	constant.CodeRefreshTokenReused:  constant.ErrorRefreshTokenReused,
+	constant.CodeRefreshTokenFailed:  constant.ErrorRefreshTokenFailed,
+	constant.CodeLogoutFailed:        constant.ErrorLogoutFailed,
+	constant.CodeAssignRoleFailed:    constant.ErrorAssignRoleFailed,
+	constant.CodeRestoreUserFailed:   constant.ErrorRestoreUserFailed,
+	constant.CodeUserIDNotFound:      constant.ErrorUserIDNotFound,
+
+	// Book module errors
+	constant.CodeListBooksFailed:   constant.ErrorListBooksFailed,
+	constant.CodeGetBookFailed:     constant.ErrorGetBookFailed,
+	constant.CodeCreateBookFailed:  constant.ErrorCreateBookFailed,
+	constant.CodeUpdateBookFailed:  constant.ErrorUpdateBookFailed,
+	constant.CodeDeleteBookFailed:  constant.ErrorDeleteBookFailed,
+	constant.CodeRestoreBookFailed: constant.ErrorRestoreBookFailed,
+	constant.CodeBookNotFound:      constant.ErrorBookNotFound,
+	constant.CodeInvalidBook:       constant.ErrorInvalidBook,
+	constant.CodeInvalidBookSort:   constant.ErrorInvalidBookSort,
+	constant.CodeSearchBooksFailed: constant.ErrorSearchBooksFailed,
+	constant.CodeEmptySearchQuery:  constant.ErrorEmptySearchQuery,
+
+	// Order module errors
+	constant.CodeCreateOrderFailed:            constant.ErrorCreateOrderFailed,
+	constant.CodeCreateOrderDetailFailed:      constant.ErrorCreateOrderDetailFailed,
+	constant.CodeGetAllOrderFailed:            constant.ErrorGetAllOrderFailed,
+	constant.CodeGetOrderDetailFailed:         constant.ErrorGetOrderDetailFailed,
+	constant.CodeOrderNotFound:                constant.ErrorOrderNotFound,
+	constant.CodeInvalidOrderDetails:          constant.ErrorInvalidOrderDetails,
+	constant.CodeOrderBooksNotFound:           constant.ErrorOrderBooksNotFound,
+	constant.CodeOrderPriceChanged:            constant.ErrorOrderPriceChanged,
+	constant.CodeOrderOutOfStock:              constant.ErrorOrderOutOfStock,
+	constant.CodeInvalidOrderDateRange:        constant.ErrorInvalidOrderDateRange,
+	constant.CodeInvalidOrderInclude:          constant.ErrorInvalidOrderInclude,
+	constant.CodeInvalidOrderStatus:           constant.ErrorInvalidOrderStatus,
+	constant.CodeInvalidOrderStatusTransition: constant.ErrorInvalidOrderStatusTransition,
+	constant.CodeOrderStatusChanged:           constant.ErrorOrderStatusChanged,
+	constant.CodeOrderStatusSetByPayment:      constant.ErrorOrderStatusSetByPayment,
+	constant.CodeUpdateOrderStatusFailed:      constant.ErrorUpdateOrderStatusFailed,
+	constant.CodeOrderNotCancellable:          constant.ErrorOrderNotCancellable,
+	constant.CodeCancelReasonRequired:         constant.ErrorCancelReasonRequired,
+	constant.CodeCancelOrderFailed:            constant.ErrorCancelOrderFailed,
+
+	// Inventory module errors
+	constant.CodeGetStockFailed:         constant.ErrorGetStockFailed,
+	constant.CodeAdjustStockFailed:      constant.ErrorAdjustStockFailed,
+	constant.CodeInvalidStockAdjustment: constant.ErrorInvalidStockAdjustment,
+	constant.CodeInsufficientStock:      constant.ErrorInsufficientStock,
+
+	// Payment module errors
+	constant.CodeCreatePaymentFailed:     constant.ErrorCreatePaymentFailed,
+	constant.CodeOrderNotPayable:         constant.ErrorOrderNotPayable,
+	constant.CodePaymentInProgress:       constant.ErrorPaymentInProgress,
+	constant.CodePaymentGatewayFailed:    constant.ErrorPaymentGatewayFailed,
+	constant.CodePaymentNotFound:         constant.ErrorPaymentNotFound,
+	constant.CodeInvalidWebhookSignature: constant.ErrorInvalidWebhookSignature,
+	constant.CodeInvalidWebhookEvent:     constant.ErrorInvalidWebhookEvent,
+	constant.CodeHandleWebhookFailed:     constant.ErrorHandleWebhookFailed,
+	constant.CodePaymentNotRefundable:    constant.ErrorPaymentNotRefundable,
+	constant.CodeRefundPaymentFailed:     constant.ErrorRefundPaymentFailed,
+
+	// Validation rules, see the validation package
+	"validation.required":           "is required",
+	"validation.email":              "must be a valid email address",
+	"validation.password":           "must be at least %d characters and at most %d bytes, and contain a letter and a digit",
+	"validation.oneof":              "must be one of %s",
+	"validation.gt":                 "must be greater than %s",
+	"validation.gte":                "must be at least %s",
+	"validation.lte":                "must be at most %s",
+	"validation.min":                "must be at least %s",
+	"validation.max":                "must be at most %s",
+	"validation.min.items":          "must have at least %s items",
+	"validation.min.items.one":      "must have at least %s item",
+	"validation.max.items":          "must have at most %s items",
+	"validation.max.items.one":      "must have at most %s item",
+	"validation.min.characters":     "must have at least %s characters",
+	"validation.min.characters.one": "must have at least %s character",
+	"validation.max.characters":     "must have at most %s characters",
+	"validation.max.characters.one": "must have at most %s character",
+	"validation.unique":             "must not contain duplicates",
+	"validation.invalid":            "is invalid",
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Supported locales
+const (
+	EN = "en"
+	ID = "id"
+
+	DefaultLocale = EN
+)
+
+// catalogs maps every supported locale to its messages, keyed by error code or by validation rule
+var catalogs = map[string]map[string]string{
+	EN: en,
+	ID: id,
+}
+
+// locales are the supported locales in the order of the matcher tags, the default one first
+var (
+	locales = []string{EN, ID}
+	matcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+)
+
+// Locale returns the supported locale that best matches the Accept-Language header, or the default
+// locale when none does
+func Locale(acceptLanguage string) string {
+	_, index := language.MatchStrings(matcher, acceptLanguage)
+	return locales[index]
+}
+
+// Lookup returns the message of the key in the locale, without falling back to another locale
+func Lookup(locale, key string) (string, bool) {
+	msg, ok := catalogs[locale][key]
+	return msg, ok
+}
+
+// Message returns the message of the key in the locale, falling back to the default locale. It
+// returns the key itself when no catalog has it.
+func Message(locale, key string) string {
+	if msg, ok := Lookup(locale, key); ok {
+		return msg
+	}
+	if msg, ok := Lookup(DefaultLocale, key); ok {
+		return msg
+	}
+
+	return key
+}
+
+// Error translates msg, the message of the error code in the default locale, into the locale. A cause
+// appended to the message after a colon is kept as is, e.g. "invalid parameters: book_id is required".
+// msg is returned unchanged when the code has no translation.
+func Error(locale, code, msg string) string {
+	if locale == DefaultLocale {
+		return msg
+	}
+
+	translated, ok := Lookup(locale, code)
+	if !ok {
+		return msg
+	}
+
+	if original, ok := Lookup(DefaultLocale, code); ok && strings.HasPrefix(msg, original+": ") {
+		return translated + msg[len(original):]
+	}
+
+	return translated
+}
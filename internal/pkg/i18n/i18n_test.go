@@ -0,0 +1,120 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// errorCodes returns every error code declared in the constant package
+func errorCodes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../constant/code.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse the error codes: %v", err)
+	}
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for _, value := range spec.Values {
+			if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				code, _ := strconv.Unquote(lit.Value)
+				codes = append(codes, code)
+			}
+		}
+		return true
+	})
+
+	return codes
+}
+
+func TestCatalogs(t *testing.T) {
+	codes := errorCodes(t)
+
+	Convey("Catalogs", t, func() {
+		So(codes, ShouldNotBeEmpty)
+
+		for _, locale := range locales {
+			Convey("should translate every error code in "+locale, func() {
+				for _, code := range codes {
+					_, ok := Lookup(locale, code)
+					So(ok, ShouldBeTrue)
+					if !ok {
+						t.Errorf("code %s has no %s translation", code, locale)
+					}
+				}
+			})
+
+			Convey("should translate every validation rule in "+locale, func() {
+				for key := range catalogs[DefaultLocale] {
+					if !strings.HasPrefix(key, "validation.") || strings.HasSuffix(key, ".one") {
+						continue
+					}
+					_, ok := Lookup(locale, key)
+					So(ok, ShouldBeTrue)
+					if !ok {
+						t.Errorf("validation rule %s has no %s translation", key, locale)
+					}
+				}
+			})
+
+			Convey("should only have known keys in "+locale, func() {
+				for key := range catalogs[locale] {
+					_, ok := Lookup(DefaultLocale, key)
+					So(ok, ShouldBeTrue)
+				}
+			})
+		}
+	})
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{acceptLanguage: "", want: EN},
+		{acceptLanguage: "id", want: ID},
+		{acceptLanguage: "id-ID,id;q=0.9,en-US;q=0.8", want: ID},
+		{acceptLanguage: "en-US,en;q=0.9,id;q=0.8", want: EN},
+		{acceptLanguage: "fr-FR,id;q=0.5", want: ID},
+		{acceptLanguage: "fr-FR", want: EN},
+		{acceptLanguage: "invalid;;", want: EN},
+	}
+
+	Convey("Locale", t, func() {
+		for _, tt := range tests {
+			So(Locale(tt.acceptLanguage), ShouldEqual, tt.want)
+		}
+	})
+}
+
+func TestError(t *testing.T) {
+	Convey("Error", t, func() {
+		Convey("should keep the message in the default locale", func() {
+			So(Error(EN, constant.CodeBookNotFound, constant.ErrorBookNotFound), ShouldEqual, constant.ErrorBookNotFound)
+		})
+
+		Convey("should translate the message", func() {
+			So(Error(ID, constant.CodeBookNotFound, constant.ErrorBookNotFound), ShouldEqual, "buku tidak ditemukan")
+		})
+
+		Convey("should keep the cause of the error", func() {
+			So(Error(ID, constant.CodeInvalidParameters, "invalid parameters: book_id is required"),
+				ShouldEqual, "parameter tidak valid: book_id is required")
+		})
+
+		Convey("should keep the message of an unknown code", func() {
+			So(Error(ID, "UNKNOWN", "unknown error"), ShouldEqual, "unknown error")
+		})
+	})
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import "github.com/erizkiatama/gotu-assignment/internal/constant"
+
+// id is the Indonesian catalog
+var id = map[string]string{
+	// Common errors
+	constant.CodeInternalServer:    "terjadi kesalahan pada server",
+	constant.CodeInvalidParameters: "parameter tidak valid",
+	constant.CodeValidationFailed:  "validasi gagal",
+
+	// Auth errors
+	constant.CodeAuthorizationRequired:  "header authorization tidak diberikan",
+	constant.CodeInvalidToken:           "token tidak valid",
+	constant.CodeTokenRevoked:           "token sudah dicabut",
+	constant.CodeInsufficientPermission: "izin tidak mencukupi",
+
+	// Idempotency errors
+	constant.CodeIdempotencyKeyTooLong:    "idempotency key maksimal 255 karakter",
+	constant.CodeReadRequestBody:          "gagal membaca isi permintaan",
+	constant.CodeIdempotencyKeyInProgress: "permintaan dengan idempotency key yang sama sedang diproses",
+	constant.CodeIdempotencyKeyReused:     "idempotency key sudah dipakai untuk permintaan lain",
+
+	// Pagination errors
+	constant.CodeInvalidCursor: "cursor tidak valid",
+
+	// User module errors
+	constant.CodeUserAlreadyExists:   "email sudah terdaftar",
+	constant.CodeCreateUserFailed:    "gagal membuat pengguna",
+	constant.CodeGenerateToken:       "gagal membuat token",
+	constant.CodeUserNotFound:        "pengguna dengan email tersebut tidak ditemukan",
+	constant.CodeGetUserFailed:       "gagal mengambil detail pengguna",
+	constant.CodePasswordNotMatch:    "kata sandi tidak cocok",
+	constant.CodeInvalidRefreshToken: "refresh token tidak valid",
+	constant.CodeRefreshTokenReused:  "refresh token sudah pernah dipakai",
+	constant.CodeRefreshTokenFailed:  "gagal memperbarui token",
+	constant.CodeLogoutFailed:        "gagal keluar",
+	constant.CodeAssignRoleFailed:    "gagal memberikan peran",
+	constant.CodeRestoreUserFailed:   "gagal memulihkan pengguna",
+	constant.CodeUserIDNotFound:      "pengguna tidak ditemukan",
+
+	// Book module errors
+	constant.CodeListBooksFailed:   "gagal menampilkan daftar buku",
+	constant.CodeGetBookFailed:     "gagal mengambil buku",
+	constant.CodeCreateBookFailed:  "gagal membuat buku",
+	constant.CodeUpdateBookFailed:  "gagal memperbarui buku",
+	constant.CodeDeleteBookFailed:  "gagal menghapus buku",
+	constant.CodeRestoreBookFailed: "gagal memulihkan buku",
+	constant.CodeBookNotFound:      "buku tidak ditemukan",
+	constant.CodeInvalidBook:       "judul dan penulis wajib diisi dan harga tidak boleh negatif",
+	constant.CodeInvalidBookSort:   "sort harus salah satu dari price, title atau created_at dan order harus asc atau desc",
+	constant.CodeSearchBooksFailed: "gagal mencari buku",
+	constant.CodeEmptySearchQuery:  "kata kunci pencarian wajib diisi",
+
+	// Order module errors
+	constant.CodeCreateOrderFailed:            "gagal membuat pesanan",
+	constant.CodeCreateOrderDetailFailed:      "gagal membuat detail pesanan",
+	constant.CodeGetAllOrderFailed:            "gagal mengambil daftar pesanan",
+	constant.CodeGetOrderDetailFailed:         "gagal mengambil detail pesanan",
+	constant.CodeOrderNotFound:                "pesanan tidak ditemukan",
+	constant.CodeInvalidOrderDetails:          "pesanan harus berisi minimal satu buku dan setiap jumlah harus positif",
+	constant.CodeOrderBooksNotFound:           "beberapa buku tidak ada",
+	constant.CodeOrderPriceChanged:            "harga beberapa buku telah berubah",
+	constant.CodeOrderOutOfStock:              "stok beberapa buku habis",
+	constant.CodeInvalidOrderDateRange:        "from dan to harus berupa tanggal (YYYY-MM-DD) atau waktu RFC3339 dan from harus sebelum to",
+	constant.CodeInvalidOrderInclude:          "include harus details",
+	constant.CodeInvalidOrderStatus:           "status harus salah satu dari pending, paid, fulfilled, completed, cancelled atau refunded",
+	constant.CodeInvalidOrderStatusTransition: "status pesanan tidak dapat berubah dari status saat ini ke status yang diminta",
+	constant.CodeOrderStatusChanged:           "status pesanan telah diubah oleh permintaan lain",
+	constant.CodeOrderStatusSetByPayment:      "paid dan refunded diatur oleh pembayaran pesanan, kembalikan dana melalui pembayarannya",
+	constant.CodeUpdateOrderStatusFailed:      "gagal memperbarui status pesanan",
+	constant.CodeOrderNotCancellable:          "pesanan hanya dapat dibatalkan selama masih pending",
+	constant.CodeCancelReasonRequired:         "alasan wajib diisi untuk membatalkan pesanan",
+	constant.CodeCancelOrderFailed:            "gagal membatalkan pesanan",
+
+	// Inventory module errors
+	constant.CodeGetStockFailed:         "gagal mengambil stok",
+	constant.CodeAdjustStockFailed:      "gagal menyesuaikan stok",
+	constant.CodeInvalidStockAdjustment: "quantity_change tidak boleh nol dan alasan wajib diisi",
+	constant.CodeInsufficientStock:      "stok tidak boleh kurang dari nol",
+
+	// Payment module errors
+	constant.CodeCreatePaymentFailed:     "gagal membuat pembayaran",
+	constant.CodeOrderNotPayable:         "hanya pesanan pending yang dapat dibayar",
+	constant.CodePaymentInProgress:       "pesanan sudah memiliki pembayaran yang pending atau selesai",
+	constant.CodePaymentGatewayFailed:    "payment gateway gagal memproses permintaan",
+	constant.CodePaymentNotFound:         "pembayaran tidak ditemukan",
+	constant.CodeInvalidWebhookSignature: "tanda tangan webhook tidak valid",
+	constant.CodeInvalidWebhookEvent:     "event webhook tidak valid",
+	constant.CodeHandleWebhookFailed:     "gagal memproses notifikasi pembayaran",
+	constant.CodePaymentNotRefundable:    "pesanan tidak memiliki pembayaran selesai yang dapat dikembalikan",
+	constant.CodeRefundPaymentFailed:     "gagal mengembalikan pembayaran",
+
+	// Validation rules, see the validation package
+	"validation.required":       "wajib diisi",
+	"validation.email":          "harus berupa alamat email yang valid",
+	"validation.password":       "minimal %d karakter dan maksimal %d byte, serta mengandung huruf dan angka",
+	"validation.oneof":          "harus salah satu dari %s",
+	"validation.gt":             "harus lebih besar dari %s",
+	"validation.gte":            "minimal %s",
+	"validation.lte":            "maksimal %s",
+	"validation.min":            "minimal %s",
+	"validation.max":            "maksimal %s",
+	"validation.min.items":      "minimal berisi %s item",
+	"validation.max.items":      "maksimal berisi %s item",
+	"validation.min.characters": "minimal %s karakter",
+	"validation.max.characters": "maksimal %s karakter",
+	"validation.unique":         "tidak boleh berisi duplikat",
+	"validation.invalid":        "tidak valid",
+}
@@ -0,0 +1,35 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+)
+
+type expiredKeyStore interface {
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Cleanup periodically deletes the idempotency keys older than retention, after which a key
+// can be used again. It blocks until the context is done.
+func Cleanup(ctx context.Context, store expiredKeyStore, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx, time.Now().UTC().Add(-retention))
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to delete expired idempotency keys", "job", "Idempotency.Cleanup", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.FromContext(ctx).Info("deleted expired idempotency keys", "job", "Idempotency.Cleanup", "deleted", deleted)
+			}
+		}
+	}
+}
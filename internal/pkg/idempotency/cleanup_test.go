@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeStore struct {
+	before chan time.Time
+}
+
+func (f fakeStore) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	f.before <- before
+	return 1, nil
+}
+
+func TestCleanup(t *testing.T) {
+	Convey("Cleanup should delete the keys older than the retention", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		store := fakeStore{before: make(chan time.Time, 1)}
+
+		done := make(chan struct{})
+		go func() {
+			Cleanup(ctx, store, time.Hour, time.Millisecond)
+			close(done)
+		}()
+
+		before := <-store.before
+		cancel()
+		<-done
+
+		So(before, ShouldHappenWithin, time.Minute, time.Now().Add(-time.Hour))
+	})
+}
@@ -0,0 +1,274 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a key used to sign and/or verify tokens. Retired keys have no signing key
+// and are only kept to verify tokens issued before the key rotation.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the active signing key and every key that tokens can still be verified with
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// JSONWebKey is the public part of a signing key as described in RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+var keySet *KeySet
+
+// LoadKeys builds the key set from the given config and uses it to sign and verify tokens
+func LoadKeys(cfg config.JwtConfig) error {
+	ks, err := NewKeySet(cfg)
+	if err != nil {
+		return err
+	}
+
+	keySet = ks
+	return nil
+}
+
+// getKeySet returns the loaded key set. When no keys were loaded, tokens are signed with HS256 using
+// the server secret key locally and in tests, anywhere else no token can be signed or verified.
+func getKeySet() *KeySet {
+	if keySet != nil {
+		return keySet
+	}
+
+	if !config.Get().Server.AllowsSecretKeySigning() {
+		return &KeySet{keys: map[string]*Key{}}
+	}
+
+	key := &Key{
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(config.Get().Server.SecretKey),
+		verifyKey: []byte(config.Get().Server.SecretKey),
+	}
+	return &KeySet{
+		active: key,
+		keys:   map[string]*Key{"": key},
+	}
+}
+
+func NewKeySet(cfg config.JwtConfig) (*KeySet, error) {
+	ks := &KeySet{
+		keys: make(map[string]*Key, len(cfg.Keys)),
+	}
+
+	for _, keyCfg := range cfg.Keys {
+		key, err := parseKey(keyCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q is not configured", cfg.ActiveKeyID)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", cfg.ActiveKeyID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+func parseKey(cfg config.JwtKeyConfig) (*Key, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("key id is required")
+	}
+
+	key := &Key{
+		ID:     cfg.ID,
+		Method: jwt.GetSigningMethod(cfg.Algorithm),
+	}
+
+	var privatePEM, publicPEM []byte
+	if cfg.PrivateKey != "" {
+		privatePEM = []byte(cfg.PrivateKey)
+	}
+	if cfg.PublicKey != "" {
+		publicPEM = []byte(cfg.PublicKey)
+	}
+	if cfg.PrivateKeyFile != "" {
+		b, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error read private key of key %q: %v", cfg.ID, err)
+		}
+		privatePEM = b
+	}
+	if cfg.PublicKeyFile != "" {
+		b, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error read public key of key %q: %v", cfg.ID, err)
+		}
+		publicPEM = b
+	}
+	if privatePEM == nil && publicPEM == nil {
+		return nil, fmt.Errorf("key %q has neither private nor public key", cfg.ID)
+	}
+
+	var err error
+	switch key.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		err = parseRSAKey(key, privatePEM, publicPEM)
+	case *jwt.SigningMethodEd25519:
+		err = parseEdKey(key, privatePEM, publicPEM)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q of key %q", cfg.Algorithm, cfg.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parse key %q: %v", cfg.ID, err)
+	}
+
+	return key, nil
+}
+
+func parseRSAKey(key *Key, privatePEM, publicPEM []byte) error {
+	if privatePEM != nil {
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return err
+		}
+		key.signKey = privateKey
+		key.verifyKey = &privateKey.PublicKey
+	}
+
+	if publicPEM != nil {
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return err
+		}
+		key.verifyKey = publicKey
+	}
+
+	return nil
+}
+
+func parseEdKey(key *Key, privatePEM, publicPEM []byte) error {
+	if privatePEM != nil {
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return err
+		}
+		key.signKey = privateKey
+		key.verifyKey = privateKey.(ed25519.PrivateKey).Public()
+	}
+
+	if publicPEM != nil {
+		publicKey, err := jwt.ParseEdPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return err
+		}
+		key.verifyKey = publicKey
+	}
+
+	return nil
+}
+
+// sign signs the token with the active key
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	if ks.active == nil {
+		return "", errors.New("no signing key is loaded")
+	}
+
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	if ks.active.ID != "" {
+		token.Header["kid"] = ks.active.ID
+	}
+
+	return token.SignedString(ks.active.signKey)
+}
+
+// keyFunc looks up the verification key by the `kid` header, making sure the token
+// was signed with the algorithm the key is configured for
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %v", token.Header["kid"])
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys of the loaded key set. Symmetric keys are never published.
+func JWKS() JSONWebKeySet {
+	return getKeySet().JWKS()
+}
+
+func (ks *KeySet) JWKS() JSONWebKeySet {
+	res := JSONWebKeySet{
+		Keys: []JSONWebKey{},
+	}
+
+	for _, key := range ks.keys {
+		switch publicKey := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			res.Keys = append(res.Keys, JSONWebKey{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			res.Keys = append(res.Keys, JSONWebKey{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	sort.Slice(res.Keys, func(i, j int) bool {
+		return res.Keys[i].Kid < res.Keys[j].Kid
+	})
+
+	return res
+}
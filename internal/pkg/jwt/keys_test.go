@@ -0,0 +1,305 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func generateEdKeyFiles(t *testing.T) (string, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	publicDER, _ := x509.MarshalPKIXPublicKey(publicKey)
+	return writePEM(t, "ed25519.pem", "PRIVATE KEY", privateDER), writePEM(t, "ed25519.pub", "PUBLIC KEY", publicDER)
+}
+
+func generateRSAKeyFiles(t *testing.T) (string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	return writePEM(t, "rsa.pem", "PRIVATE KEY", privateDER), writePEM(t, "rsa.pub", "PUBLIC KEY", publicDER)
+}
+
+func TestNewKeySet(t *testing.T) {
+	edPrivate, edPublic := generateEdKeyFiles(t)
+	rsaPrivate, _ := generateRSAKeyFiles(t)
+
+	Convey("NewKeySet", t, func() {
+		Convey("should load active and retired keys", func() {
+			ks, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "rsa-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaPrivate},
+					{ID: "ed-1", Algorithm: "EdDSA", PublicKeyFile: edPublic},
+				},
+			})
+
+			So(err, ShouldBeNil)
+			So(ks.active.ID, ShouldEqual, "rsa-1")
+			So(ks.keys, ShouldHaveLength, 2)
+		})
+
+		Convey("should load keys given as is", func() {
+			privatePEM, _ := os.ReadFile(edPrivate)
+			publicPEM, _ := os.ReadFile(edPublic)
+			ks, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "ed-2",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-2", Algorithm: "EdDSA", PrivateKey: string(privatePEM)},
+					{ID: "ed-1", Algorithm: "EdDSA", PublicKey: string(publicPEM)},
+				},
+			})
+
+			So(err, ShouldBeNil)
+			So(ks.active.ID, ShouldEqual, "ed-2")
+			So(ks.keys, ShouldHaveLength, 2)
+		})
+
+		Convey("should return error for unknown active key", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "unknown",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should return error for active key without private key", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PublicKeyFile: edPublic},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should return error for duplicate key id", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+					{ID: "ed-1", Algorithm: "EdDSA", PublicKeyFile: edPublic},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should return error for unsupported algorithm", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "hs-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "hs-1", Algorithm: "HS256", PrivateKeyFile: edPrivate},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should return error for mismatched key type", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "rsa-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: edPrivate},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should return error for missing key file", func() {
+			_, err := NewKeySet(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: filepath.Join(t.TempDir(), "missing.pem")},
+				},
+			})
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestLoadKeys(t *testing.T) {
+	edPrivate, _ := generateEdKeyFiles(t)
+	rsaPrivate, rsaPublic := generateRSAKeyFiles(t)
+	defer func() {
+		keySet = nil
+	}()
+
+	Convey("LoadKeys", t, func() {
+		config.Get().Jwt.AccessTokenExpiryHours = 1
+		config.Get().Jwt.RefreshTokenExpiryHours = 24
+
+		Convey("should sign tokens with the active key", func() {
+			err := LoadKeys(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tokenPair, err := GenerateTokenPair(TokenClaim{Id: 123})
+			So(err, ShouldBeNil)
+
+			token, err := validateToken(tokenPair.Access)
+			So(err, ShouldBeNil)
+			So(token.Header["kid"], ShouldEqual, "ed-1")
+			So(token.Method.Alg(), ShouldEqual, "EdDSA")
+		})
+
+		Convey("should verify tokens signed by a retired key", func() {
+			err := LoadKeys(config.JwtConfig{
+				ActiveKeyID: "rsa-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaPrivate},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tokenPair, err := GenerateTokenPair(TokenClaim{Id: 123})
+			So(err, ShouldBeNil)
+
+			err = LoadKeys(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+					{ID: "rsa-1", Algorithm: "RS256", PublicKeyFile: rsaPublic},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeAccess)
+			So(err, ShouldBeNil)
+			So(tokenClaim.Id, ShouldEqual, 123)
+		})
+
+		Convey("should reject tokens signed by an unknown key", func() {
+			err := LoadKeys(config.JwtConfig{
+				ActiveKeyID: "rsa-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaPrivate},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tokenPair, err := GenerateTokenPair(TokenClaim{Id: 123})
+			So(err, ShouldBeNil)
+
+			err = LoadKeys(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeAccess)
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+		})
+
+		Convey("should reject tokens signed with another algorithm than the key", func() {
+			err := LoadKeys(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "123"})
+			token.Header["kid"] = "ed-1"
+			tokenString, err := token.SignedString([]byte("secret"))
+			So(err, ShouldBeNil)
+
+			_, err = validateToken(tokenString)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestJWKS(t *testing.T) {
+	edPrivate, _ := generateEdKeyFiles(t)
+	_, rsaPublic := generateRSAKeyFiles(t)
+	defer func() {
+		keySet = nil
+		config.Get().Server.Env = config.EnvTest
+	}()
+
+	Convey("JWKS", t, func() {
+		Convey("should not publish symmetric keys", func() {
+			keySet = nil
+			config.Get().Server.Env = config.EnvTest
+			config.Get().Server.SecretKey = "secret"
+
+			So(JWKS().Keys, ShouldBeEmpty)
+		})
+
+		Convey("should not fall back to the server secret key outside of local and test", func() {
+			keySet = nil
+			config.Get().Server.Env = config.EnvProduction
+			config.Get().Server.SecretKey = "secret"
+
+			So(JWKS().Keys, ShouldBeEmpty)
+			_, err := getKeySet().sign(jwt.MapClaims{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should publish every public key", func() {
+			err := LoadKeys(config.JwtConfig{
+				ActiveKeyID: "ed-1",
+				Keys: []config.JwtKeyConfig{
+					{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: edPrivate},
+					{ID: "rsa-1", Algorithm: "RS256", PublicKeyFile: rsaPublic},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			jwks := JWKS()
+			So(jwks.Keys, ShouldHaveLength, 2)
+
+			So(jwks.Keys[0].Kid, ShouldEqual, "ed-1")
+			So(jwks.Keys[0].Kty, ShouldEqual, "OKP")
+			So(jwks.Keys[0].Crv, ShouldEqual, "Ed25519")
+			So(jwks.Keys[0].Alg, ShouldEqual, "EdDSA")
+			So(jwks.Keys[0].X, ShouldNotBeEmpty)
+
+			So(jwks.Keys[1].Kid, ShouldEqual, "rsa-1")
+			So(jwks.Keys[1].Kty, ShouldEqual, "RSA")
+			So(jwks.Keys[1].Alg, ShouldEqual, "RS256")
+			So(jwks.Keys[1].N, ShouldNotBeEmpty)
+			So(jwks.Keys[1].E, ShouldEqual, "AQAB")
+		})
+	})
+}
@@ -1,8 +1,11 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/config"
@@ -10,29 +13,80 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Token issue times are compared with the time of a logout from all sessions,
+// which the database records with a microsecond precision
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
+// Claims is the payload of the issued tokens
+type Claims struct {
+	jwt.RegisteredClaims
+	Type        string   `json:"typ"`
+	FamilyID    string   `json:"family,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
 type TokenClaim struct {
-	Id int64
+	Id          int64
+	TokenID     string
+	FamilyID    string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	Roles       []string
+	Permissions []string
+}
+
+// HasPermission reports whether the token grants the given permission
+func (c TokenClaim) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTokenID generates a random identifier used for the `jti` and `family` claims
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generate token id: %v", err)
+	}
+
+	return hex.EncodeToString(b), nil
 }
 
 func GenerateTokenPair(req TokenClaim) (*user.TokenPairResponse, error) {
-	access := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"access":  true,
-		"user_id": req.Id,
-		"expires": time.Now().Add(time.Duration(config.Get().Jwt.AccessTokenExpiryHours) * time.Hour).UTC().Unix(),
-	})
+	accessTokenID, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := getKeySet()
+	cfg := config.Get().Jwt
+	now := time.Now().UTC()
 
-	at, err := access.SignedString([]byte(config.Get().Server.SecretKey))
+	// Roles and permissions are only embedded in the access token, so changes to them
+	// are picked up the next time the token pair is refreshed
+	accessClaims := newClaims(req.Id, accessTokenID, req.FamilyID, TokenTypeAccess, now,
+		time.Duration(cfg.AccessTokenExpiryHours)*time.Hour)
+	accessClaims.Roles = req.Roles
+	accessClaims.Permissions = req.Permissions
+
+	at, err := keys.sign(accessClaims)
 	if err != nil {
 		return nil, fmt.Errorf("error generate access token: %v", err)
 	}
 
-	refresh := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"refresh": true,
-		"user_id": req.Id,
-		"expires": time.Now().Add(time.Duration(config.Get().Jwt.RefreshTokenExpiryHours) * time.Hour).UTC().Unix(),
-	})
-
-	rt, err := refresh.SignedString([]byte(config.Get().Server.SecretKey))
+	rt, err := keys.sign(newClaims(req.Id, req.TokenID, req.FamilyID, TokenTypeRefresh, now,
+		time.Duration(cfg.RefreshTokenExpiryHours)*time.Hour))
 	if err != nil {
 		return nil, fmt.Errorf("error generate refresh token: %v", err)
 	}
@@ -43,35 +97,75 @@ func GenerateTokenPair(req TokenClaim) (*user.TokenPairResponse, error) {
 	}, nil
 }
 
-func validateToken(encodedToken, secretKey string) (*jwt.Token, error) {
-	return jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+func newClaims(userID int64, tokenID, familyID, tokenType string, now time.Time, expiry time.Duration) *Claims {
+	cfg := config.Get().Jwt
 
-		}
-		return []byte(secretKey), nil
-	})
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.FormatInt(userID, 10),
+			Issuer:    cfg.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+		},
+		Type:     tokenType,
+		FamilyID: familyID,
+	}
+	if cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.Audience}
+	}
+
+	return claims
+}
+
+func validateToken(encodedToken string) (*jwt.Token, error) {
+	cfg := config.Get().Jwt
+
+	opts := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Duration(cfg.LeewaySeconds) * time.Second),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return jwt.ParseWithClaims(encodedToken, &Claims{}, getKeySet().keyFunc, opts...)
 }
 
-func AuthorizeToken(tokenString, secretKey string, isRefresh bool) (*TokenClaim, error) {
-	token, err := validateToken(tokenString, secretKey)
+// AuthorizeToken validates the token and makes sure it is of the expected type,
+// so refresh tokens can't be used as access tokens and the other way around
+func AuthorizeToken(tokenString, tokenType string) (*TokenClaim, error) {
+	token, err := validateToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if isRefresh && claims["refresh"] == nil {
-			return nil, errors.New("token was not a refresh token")
-		}
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
 
-		if int64(claims["expires"].(float64)) < time.Now().UTC().Unix() {
-			return nil, errors.New("token has expired")
-		}
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("invalid token type, expected %s token", tokenType)
+	}
 
-		return &TokenClaim{
-			Id: int64(claims["user_id"].(float64)),
-		}, nil
-	} else {
-		return nil, errors.New("invalid token")
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid token subject")
 	}
+
+	return &TokenClaim{
+		Id:          userID,
+		TokenID:     claims.ID,
+		FamilyID:    claims.FamilyID,
+		IssuedAt:    claims.IssuedAt.Time.UTC(),
+		ExpiresAt:   claims.ExpiresAt.Time.UTC(),
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+	}, nil
 }
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"testing"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/config"
 	"github.com/golang-jwt/jwt/v5"
@@ -10,83 +11,201 @@ import (
 
 func TestGenerateTokenPair(t *testing.T) {
 	req := TokenClaim{
-		Id: 123,
+		Id:          123,
+		TokenID:     "token-id",
+		FamilyID:    "family-id",
+		Roles:       []string{"admin"},
+		Permissions: []string{"book:write"},
 	}
 
 	Convey("GenerateTokenPair", t, func() {
 		Convey("should generate token pair", func() {
+			config.Get().Server.Env = config.EnvTest
 			config.Get().Server.SecretKey = "secret"
+			config.Get().Jwt.Issuer = "issuer"
+			config.Get().Jwt.Audience = "audience"
 			tokenPair, err := GenerateTokenPair(req)
 			So(err, ShouldBeNil)
 			So(tokenPair, ShouldNotBeNil)
 
 			Convey("should generate access token", func() {
-				accessToken, err := validateToken(tokenPair.Access, config.Get().Server.SecretKey)
+				accessToken, err := validateToken(tokenPair.Access)
 				So(err, ShouldBeNil)
 
-				accessClaims, ok := accessToken.Claims.(jwt.MapClaims)
+				accessClaims, ok := accessToken.Claims.(*Claims)
 				So(ok, ShouldBeTrue)
-
-				accessUserID, ok := accessClaims["user_id"].(float64)
-				So(ok, ShouldBeTrue)
-				So(int64(accessUserID), ShouldEqual, req.Id)
+				So(accessClaims.Subject, ShouldEqual, "123")
+				So(accessClaims.Type, ShouldEqual, TokenTypeAccess)
+				So(accessClaims.ID, ShouldNotBeEmpty)
+				So(accessClaims.ID, ShouldNotEqual, req.TokenID)
+				So(accessClaims.FamilyID, ShouldEqual, req.FamilyID)
+				So(accessClaims.Issuer, ShouldEqual, "issuer")
+				So(accessClaims.Audience, ShouldResemble, jwt.ClaimStrings{"audience"})
+				So(accessClaims.IssuedAt, ShouldNotBeNil)
+				So(accessClaims.NotBefore, ShouldNotBeNil)
+				So(accessClaims.ExpiresAt, ShouldNotBeNil)
+				So(accessClaims.Roles, ShouldResemble, req.Roles)
+				So(accessClaims.Permissions, ShouldResemble, req.Permissions)
 			})
 
 			Convey("should generate refresh token", func() {
-				refreshToken, err := validateToken(tokenPair.Refresh, config.Get().Server.SecretKey)
+				refreshToken, err := validateToken(tokenPair.Refresh)
 				So(err, ShouldBeNil)
 
-				refreshClaims, ok := refreshToken.Claims.(jwt.MapClaims)
+				refreshClaims, ok := refreshToken.Claims.(*Claims)
 				So(ok, ShouldBeTrue)
+				So(refreshClaims.Subject, ShouldEqual, "123")
+				So(refreshClaims.Type, ShouldEqual, TokenTypeRefresh)
+				So(refreshClaims.ID, ShouldEqual, req.TokenID)
+				So(refreshClaims.FamilyID, ShouldEqual, req.FamilyID)
+				So(refreshClaims.Roles, ShouldBeEmpty)
+				So(refreshClaims.Permissions, ShouldBeEmpty)
+			})
 
-				refreshUserID, ok := refreshClaims["user_id"].(float64)
-				So(ok, ShouldBeTrue)
-				So(int64(refreshUserID), ShouldEqual, req.Id)
+			Reset(func() {
+				config.Get().Jwt.Issuer = ""
+				config.Get().Jwt.Audience = ""
 			})
 		})
 	})
 }
+
+func TestTokenClaim_HasPermission(t *testing.T) {
+	Convey("HasPermission", t, func() {
+		claim := TokenClaim{Permissions: []string{"book:write"}}
+
+		So(claim.HasPermission("book:write"), ShouldBeTrue)
+		So(claim.HasPermission("order:write"), ShouldBeFalse)
+		So(TokenClaim{}.HasPermission("book:write"), ShouldBeFalse)
+	})
+}
+
+func TestNewTokenID(t *testing.T) {
+	Convey("NewTokenID", t, func() {
+		Convey("should generate unique token id", func() {
+			first, err := NewTokenID()
+			So(err, ShouldBeNil)
+			So(first, ShouldHaveLength, 32)
+
+			second, err := NewTokenID()
+			So(err, ShouldBeNil)
+			So(second, ShouldNotEqual, first)
+		})
+	})
+}
+
 func TestAuthorizeToken(t *testing.T) {
 	Convey("AuthorizeToken", t, func() {
+		config.Get().Server.Env = config.EnvTest
 		config.Get().Server.SecretKey = "secret"
-		tokenPair, err := GenerateTokenPair(TokenClaim{Id: 123})
+		config.Get().Jwt.AccessTokenExpiryHours = 1
+		config.Get().Jwt.RefreshTokenExpiryHours = 24
+		config.Get().Jwt.Issuer = "issuer"
+		config.Get().Jwt.Audience = "audience"
+		config.Get().Jwt.LeewaySeconds = 0
+		tokenPair, err := GenerateTokenPair(TokenClaim{Id: 123, TokenID: "token-id", FamilyID: "family-id"})
 		So(err, ShouldBeNil)
 		So(tokenPair, ShouldNotBeNil)
 
+		Reset(func() {
+			config.Get().Jwt.Issuer = ""
+			config.Get().Jwt.Audience = ""
+			config.Get().Jwt.LeewaySeconds = 0
+		})
+
 		Convey("should return token claim for valid access token", func() {
-			tokenClaim, err := AuthorizeToken(tokenPair.Access, config.Get().Server.SecretKey, false)
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeAccess)
 
 			So(err, ShouldBeNil)
 			So(tokenClaim, ShouldNotBeNil)
 			So(tokenClaim.Id, ShouldEqual, 123)
+			So(tokenClaim.TokenID, ShouldNotBeEmpty)
+			So(tokenClaim.FamilyID, ShouldEqual, "family-id")
+			So(tokenClaim.IssuedAt, ShouldHappenOnOrBefore, time.Now())
+			So(tokenClaim.ExpiresAt, ShouldHappenAfter, tokenClaim.IssuedAt)
 		})
 
 		Convey("should return token claim for valid refresh token", func() {
-			tokenClaim, err := AuthorizeToken(tokenPair.Refresh, config.Get().Server.SecretKey, true)
+			tokenClaim, err := AuthorizeToken(tokenPair.Refresh, TokenTypeRefresh)
 
 			So(err, ShouldBeNil)
 			So(tokenClaim, ShouldNotBeNil)
 			So(tokenClaim.Id, ShouldEqual, 123)
+			So(tokenClaim.TokenID, ShouldEqual, "token-id")
+			So(tokenClaim.FamilyID, ShouldEqual, "family-id")
 		})
 
 		Convey("should return error for invalid token", func() {
-			tokenClaim, err := AuthorizeToken("invalid_token", config.Get().Server.SecretKey, false)
+			tokenClaim, err := AuthorizeToken("invalid_token", TokenTypeAccess)
+
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+		})
+
+		Convey("should return error for access token when refresh token is expected", func() {
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeRefresh)
+
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+		})
+
+		Convey("should return error for refresh token when access token is expected", func() {
+			tokenClaim, err := AuthorizeToken(tokenPair.Refresh, TokenTypeAccess)
 
 			So(err, ShouldNotBeNil)
 			So(tokenClaim, ShouldBeNil)
 		})
 
-		Convey("should return error for access token when isRefresh is true", func() {
-			tokenClaim, err := AuthorizeToken(tokenPair.Access, config.Get().Server.SecretKey, true)
+		Convey("should return error for token of another issuer", func() {
+			config.Get().Jwt.Issuer = "another-issuer"
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeAccess)
 
 			So(err, ShouldNotBeNil)
 			So(tokenClaim, ShouldBeNil)
 		})
 
+		Convey("should return error for token of another audience", func() {
+			config.Get().Jwt.Audience = "another-audience"
+			tokenClaim, err := AuthorizeToken(tokenPair.Access, TokenTypeAccess)
+
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+		})
+
+		Convey("should return error for token without expiry", func() {
+			token, err := getKeySet().sign(&Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "123", Issuer: "issuer", Audience: jwt.ClaimStrings{"audience"}},
+				Type:             TokenTypeAccess,
+			})
+			So(err, ShouldBeNil)
+
+			tokenClaim, err := AuthorizeToken(token, TokenTypeAccess)
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+		})
+
+		Convey("should return error for token that is not valid yet", func() {
+			now := time.Now().Add(time.Minute)
+			token, err := getKeySet().sign(newClaims(123, "token-id", "", TokenTypeAccess, now, time.Hour))
+			So(err, ShouldBeNil)
+
+			tokenClaim, err := AuthorizeToken(token, TokenTypeAccess)
+			So(err, ShouldNotBeNil)
+			So(tokenClaim, ShouldBeNil)
+
+			Convey("should accept it within the leeway", func() {
+				config.Get().Jwt.LeewaySeconds = 120
+				tokenClaim, err := AuthorizeToken(token, TokenTypeAccess)
+
+				So(err, ShouldBeNil)
+				So(tokenClaim, ShouldNotBeNil)
+			})
+		})
+
 		Convey("should return error for expired token", func() {
 			config.Get().Jwt.AccessTokenExpiryHours = -1
 			expiredToken, _ := GenerateTokenPair(TokenClaim{Id: 123})
-			tokenClaim, err := AuthorizeToken(expiredToken.Access, config.Get().Server.SecretKey, false)
+			tokenClaim, err := AuthorizeToken(expiredToken.Access, TokenTypeAccess)
 
 			So(err, ShouldNotBeNil)
 			So(tokenClaim, ShouldBeNil)
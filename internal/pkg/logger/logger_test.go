@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNew(t *testing.T) {
+	Convey("New", t, func() {
+		Convey("should default to json at info", func() {
+			var buf bytes.Buffer
+			log, err := New(config.LogConfig{}, &buf)
+			So(err, ShouldBeNil)
+
+			log.Debug("debug")
+			log.Info("info")
+			So(buf.String(), ShouldNotContainSubstring, `"msg":"debug"`)
+			So(buf.String(), ShouldContainSubstring, `"msg":"info"`)
+		})
+
+		Convey("should log in text at the level", func() {
+			var buf bytes.Buffer
+			log, err := New(config.LogConfig{Level: "warn", Format: "text"}, &buf)
+			So(err, ShouldBeNil)
+
+			log.Info("info")
+			log.Warn("warn")
+			So(buf.String(), ShouldNotContainSubstring, "msg=info")
+			So(buf.String(), ShouldContainSubstring, "level=WARN msg=warn")
+		})
+
+		Convey("should reject an invalid level", func() {
+			_, err := New(config.LogConfig{Level: "verbose"}, &bytes.Buffer{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should reject an invalid format", func() {
+			_, err := New(config.LogConfig{Format: "xml"}, &bytes.Buffer{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	Convey("FromContext", t, func() {
+		Convey("should return the default logger without a logger in the context", func() {
+			So(FromContext(context.Background()), ShouldEqual, slog.Default())
+		})
+
+		Convey("should return the logger of the context with its attributes", func() {
+			var buf bytes.Buffer
+			ctx := WithContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+			ctx = With(ctx, "user_id", 1)
+
+			FromContext(ctx).Info("info")
+			So(buf.String(), ShouldContainSubstring, `"user_id":1`)
+		})
+	})
+}
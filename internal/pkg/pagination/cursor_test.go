@@ -0,0 +1,41 @@
+package pagination
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLimit(t *testing.T) {
+	Convey("Limit", t, func() {
+		So(Limit(0), ShouldEqual, DefaultLimit)
+		So(Limit(-1), ShouldEqual, DefaultLimit)
+		So(Limit(5), ShouldEqual, 5)
+		So(Limit(MaxLimit+1), ShouldEqual, MaxLimit)
+	})
+}
+
+func TestCursor(t *testing.T) {
+	type position struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	}
+
+	Convey("Cursor", t, func() {
+		Convey("should decode an encoded cursor", func() {
+			cursor, err := EncodeCursor(position{ID: 1, Title: "Book 1"})
+			So(err, ShouldBeNil)
+			So(cursor, ShouldNotBeEmpty)
+
+			var got position
+			So(DecodeCursor(cursor, &got), ShouldBeNil)
+			So(got, ShouldResemble, position{ID: 1, Title: "Book 1"})
+		})
+
+		Convey("should return error for invalid cursor", func() {
+			var got position
+			So(DecodeCursor("not a cursor!", &got), ShouldNotBeNil)
+			So(DecodeCursor("bm90IGpzb24", &got), ShouldNotBeNil)
+		})
+	})
+}
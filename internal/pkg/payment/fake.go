@@ -0,0 +1,154 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FakeProvider = "fake"
+
+	// FakeSignatureHeader carries the signature of the fake gateway webhooks, formatted as
+	// t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">
+	FakeSignatureHeader = "X-Fake-Signature"
+
+	fakeChargePrefix = "ch_fake_"
+)
+
+// FakeGateway is a gateway for development and tests. Charges are made without any provider and
+// are captured as soon as Capture is called, its webhooks can be sent with a signature from Sign.
+type FakeGateway struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// fakeEvent is the webhook body of the fake gateway
+type fakeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		ChargeID string `json:"charge_id"`
+	} `json:"data"`
+}
+
+func NewFakeGateway(secret string) *FakeGateway {
+	return &FakeGateway{
+		secret:    []byte(secret),
+		tolerance: 5 * time.Minute,
+		now:       time.Now,
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeProvider
+}
+
+func (g *FakeGateway) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("[FakeGateway.CreateCharge] invalid amount %d", req.Amount)
+	}
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("[FakeGateway.CreateCharge] failed to generate charge id: %v", err)
+	}
+
+	return &Charge{
+		ID:       fakeChargePrefix + hex.EncodeToString(id),
+		Status:   ChargePending,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, chargeID string) (*Charge, error) {
+	if !strings.HasPrefix(chargeID, fakeChargePrefix) {
+		return nil, fmt.Errorf("[FakeGateway.Capture] unknown charge %s", chargeID)
+	}
+
+	return &Charge{
+		ID:     chargeID,
+		Status: ChargeSucceeded,
+	}, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, chargeID string, amount int64) (*Charge, error) {
+	if !strings.HasPrefix(chargeID, fakeChargePrefix) {
+		return nil, fmt.Errorf("[FakeGateway.Refund] unknown charge %s", chargeID)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("[FakeGateway.Refund] invalid amount %d", amount)
+	}
+
+	return &Charge{
+		ID:     chargeID,
+		Status: ChargeRefunded,
+		Amount: amount,
+	}, nil
+}
+
+// ParseWebhook verifies the signature of the webhook, which must have been made in the last five
+// minutes so a captured request cannot be replayed later
+func (g *FakeGateway) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(header.Get(FakeSignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	expected := g.sign(timestamp, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrInvalidSignature
+	}
+
+	if age := g.now().Sub(time.Unix(unix, 0)); age > g.tolerance || age < -g.tolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	var event fakeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if event.ID == "" || event.Type == "" || event.Data.ChargeID == "" {
+		return nil, fmt.Errorf("%w: id, type and charge_id are required", ErrInvalidEvent)
+	}
+
+	return &Event{
+		ID:       event.ID,
+		Type:     event.Type,
+		ChargeID: event.Data.ChargeID,
+	}, nil
+}
+
+// Sign returns the signature header value of a webhook body sent at t
+func (g *FakeGateway) Sign(body []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, g.sign(timestamp, body))
+}
+
+func (g *FakeGateway) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
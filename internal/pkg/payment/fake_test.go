@@ -0,0 +1,136 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNew(t *testing.T) {
+	Convey("New", t, func() {
+		Convey("should return the fake gateway", func() {
+			gateway, err := New(config.PaymentConfig{Provider: FakeProvider, WebhookSecret: "secret"})
+			So(err, ShouldBeNil)
+			So(gateway.Name(), ShouldEqual, FakeProvider)
+		})
+
+		Convey("should fail without webhook secret", func() {
+			_, err := New(config.PaymentConfig{Provider: FakeProvider})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should fail with an unknown provider", func() {
+			_, err := New(config.PaymentConfig{Provider: "unknown", WebhookSecret: "secret"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestFakeGateway_Charge(t *testing.T) {
+	Convey("Fake gateway charges", t, func() {
+		gateway := NewFakeGateway("secret")
+		ctx := context.Background()
+
+		charge, err := gateway.CreateCharge(ctx, ChargeRequest{Reference: "order-1", Amount: 1000, Currency: "IDR"})
+		So(err, ShouldBeNil)
+		So(charge.ID, ShouldStartWith, fakeChargePrefix)
+		So(charge.Status, ShouldEqual, ChargePending)
+		So(charge.Amount, ShouldEqual, 1000)
+
+		other, _ := gateway.CreateCharge(ctx, ChargeRequest{Reference: "order-1", Amount: 1000, Currency: "IDR"})
+		So(other.ID, ShouldNotEqual, charge.ID)
+
+		captured, err := gateway.Capture(ctx, charge.ID)
+		So(err, ShouldBeNil)
+		So(captured.Status, ShouldEqual, ChargeSucceeded)
+
+		refunded, err := gateway.Refund(ctx, charge.ID, 1000)
+		So(err, ShouldBeNil)
+		So(refunded.Status, ShouldEqual, ChargeRefunded)
+
+		_, err = gateway.CreateCharge(ctx, ChargeRequest{Amount: 0})
+		So(err, ShouldNotBeNil)
+		_, err = gateway.Capture(ctx, "ch_other")
+		So(err, ShouldNotBeNil)
+		_, err = gateway.Refund(ctx, charge.ID, 0)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestFakeGateway_ParseWebhook(t *testing.T) {
+	gateway := NewFakeGateway("secret")
+	now := time.Unix(1704067200, 0)
+	gateway.now = func() time.Time { return now }
+
+	body := []byte(`{"id":"evt_1","type":"charge.succeeded","data":{"charge_id":"ch_fake_1"}}`)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		want      *Event
+		wantErr   error
+	}{
+		{
+			name:      "valid signature",
+			body:      body,
+			signature: gateway.Sign(body, now),
+			want:      &Event{ID: "evt_1", Type: EventChargeSucceeded, ChargeID: "ch_fake_1"},
+		},
+		{
+			name:    "missing signature",
+			body:    body,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:      "signed with another secret",
+			body:      body,
+			signature: NewFakeGateway("other").Sign(body, now),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered body",
+			body:      []byte(strings.Replace(string(body), "ch_fake_1", "ch_fake_2", 1)),
+			signature: gateway.Sign(body, now),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "signature too old",
+			body:      body,
+			signature: gateway.Sign(body, now.Add(-10*time.Minute)),
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "invalid event",
+			body:      []byte(`{"id":"evt_1"}`),
+			signature: gateway.Sign([]byte(`{"id":"evt_1"}`), now),
+			wantErr:   ErrInvalidEvent,
+		},
+	}
+
+	Convey("Fake gateway ParseWebhook", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				header := make(http.Header)
+				if tt.signature != "" {
+					header.Set(FakeSignatureHeader, tt.signature)
+				}
+
+				got, err := gateway.ParseWebhook(header, tt.body)
+				if tt.wantErr != nil {
+					So(errors.Is(err, tt.wantErr), ShouldBeTrue)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erizkiatama/gotu-assignment/internal/config"
+)
+
+// Statuses of a charge
+const (
+	ChargePending   = "pending"
+	ChargeSucceeded = "succeeded"
+	ChargeFailed    = "failed"
+	ChargeRefunded  = "refunded"
+)
+
+// Types of the events notified by a gateway. An authorized charge still has to be captured
+// before the customer is charged, gateways that capture right away only notify succeeded.
+const (
+	EventChargeAuthorized = "charge.authorized"
+	EventChargeSucceeded  = "charge.succeeded"
+	EventChargeFailed     = "charge.failed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidEvent     = errors.New("invalid webhook event")
+)
+
+// ChargeRequest asks the gateway to charge Amount, in the smallest unit of Currency, for the order
+// identified by Reference
+type ChargeRequest struct {
+	Reference string
+	Amount    int64
+	Currency  string
+}
+
+// Charge is a charge made by the gateway
+type Charge struct {
+	ID       string
+	Status   string
+	Amount   int64
+	Currency string
+}
+
+// Event is a webhook notification about a charge
+type Event struct {
+	ID       string
+	Type     string
+	ChargeID string
+}
+
+// Gateway is a payment provider. A provider is plugged in by implementing Gateway and adding it to New.
+type Gateway interface {
+	// Name identifies the provider, it is stored with the payments it made
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	Capture(ctx context.Context, chargeID string) (*Charge, error)
+	// Refund returns the amount of a charge. An interrupted refund is resumed by calling it again, so a
+	// charge must be refunded at most once however many times it is called.
+	Refund(ctx context.Context, chargeID string, amount int64) (*Charge, error)
+	// ParseWebhook verifies the signature of a webhook request and returns its event. It returns
+	// ErrInvalidSignature when the request was not sent by the provider.
+	ParseWebhook(header http.Header, body []byte) (*Event, error)
+}
+
+// New returns the gateway of the configured provider
+func New(cfg config.PaymentConfig) (Gateway, error) {
+	switch cfg.Provider {
+	case FakeProvider:
+		if cfg.WebhookSecret == "" {
+			return nil, errors.New("webhook secret is required")
+		}
+		return NewFakeGateway(cfg.WebhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,35 @@
+package softdelete
+
+import (
+	"context"
+	"fmt"
+)
+
+type includeDeletedKey struct{}
+
+// WithDeleted returns a copy of the context that makes repositories include soft deleted rows.
+// It is meant for admin tools only.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludeDeleted reports whether soft deleted rows should be included for the given context
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
+
+// Scope returns the condition that excludes soft deleted rows based on the given is_deleted
+// columns, or a condition that matches every row when the context includes deleted rows.
+func Scope(ctx context.Context, columns ...string) string {
+	if IncludeDeleted(ctx) || len(columns) == 0 {
+		return "true"
+	}
+
+	cond := fmt.Sprintf("%s = false", columns[0])
+	for _, column := range columns[1:] {
+		cond += fmt.Sprintf(" AND %s = false", column)
+	}
+
+	return cond
+}
@@ -0,0 +1,28 @@
+package softdelete
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScope(t *testing.T) {
+	Convey("Scope", t, func() {
+		ctx := context.Background()
+
+		Convey("should exclude deleted rows by default", func() {
+			So(IncludeDeleted(ctx), ShouldBeFalse)
+			So(Scope(ctx, "is_deleted"), ShouldEqual, "is_deleted = false")
+			So(Scope(ctx, "o.is_deleted", "od.is_deleted"), ShouldEqual, "o.is_deleted = false AND od.is_deleted = false")
+		})
+
+		Convey("should include deleted rows when asked to", func() {
+			ctx = WithDeleted(ctx)
+
+			So(IncludeDeleted(ctx), ShouldBeTrue)
+			So(Scope(ctx, "is_deleted"), ShouldEqual, "true")
+			So(Scope(ctx, "o.is_deleted", "od.is_deleted"), ShouldEqual, "true")
+		})
+	})
+}
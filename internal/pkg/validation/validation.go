@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/i18n"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// MinPasswordLength is the minimum length of the password of a new user
+const MinPasswordLength = 8
+
+// MaxPasswordBytes is the maximum length of a password in bytes, bcrypt does not hash longer ones
+const MaxPasswordBytes = 72
+
+// maxOrderLinesTag is the rule limiting the lines of an order to order.MaxOrderLines
+const maxOrderLinesTag = "maxorderlines"
+
+var registerOnce sync.Once
+
+// Register sets up the validator used by gin binding: the failing fields are named after their
+// json or form tag and the custom rules of the request models are added. It is called once when the
+// routes are set up, before any request is bound, and is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(fieldName)
+		_ = v.RegisterValidation("password", isStrongPassword)
+		v.RegisterAlias(maxOrderLinesTag, "max="+strconv.Itoa(order.MaxOrderLines))
+	})
+}
+
+// FieldErrors converts the validation errors returned by gin binding into one error per failing field,
+// with the messages in the locale. It returns false when err is not a validation error, e.g. a malformed body.
+func FieldErrors(err error, locale string) ([]response.FieldError, bool) {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil, false
+	}
+
+	res := make([]response.FieldError, len(errs))
+	for i, e := range errs {
+		res[i] = response.FieldError{
+			Field:   field(e),
+			Code:    e.ActualTag(),
+			Message: message(e, locale),
+		}
+	}
+
+	return res, true
+}
+
+// fieldName names a struct field after its json or form tag, falling back to the field name
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name := strings.SplitN(f.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+// field returns the path of the failing field without the name of the request, e.g. details[0].quantity
+func field(e validator.FieldError) string {
+	namespace := e.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
+
+// message is the message of the failing rule in the locale, see the validation keys of the i18n catalogs
+func message(e validator.FieldError, locale string) string {
+	param := e.Param()
+
+	var key string
+	switch e.ActualTag() {
+	case "required", "email", "gt", "gte", "lte", "unique":
+		key = "validation." + e.ActualTag()
+	case "password":
+		return fmt.Sprintf(i18n.Message(locale, "validation.password"), MinPasswordLength, MaxPasswordBytes)
+	case "oneof":
+		key, param = "validation.oneof", strings.Join(strings.Fields(param), ", ")
+	case "min", "max":
+		key = "validation." + e.ActualTag()
+		if unit := unit(e); unit != "" {
+			key += "." + unit
+		}
+	default:
+		key = "validation.invalid"
+	}
+
+	msg := i18n.Message(locale, key)
+	if param == "1" {
+		if one, ok := i18n.Lookup(locale, key+".one"); ok {
+			msg = one
+		}
+	}
+	if strings.Contains(msg, "%s") {
+		return fmt.Sprintf(msg, param)
+	}
+
+	return msg
+}
+
+// unit is what the length of the field counts, it is empty for numbers
+func unit(e validator.FieldError) string {
+	switch e.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	case reflect.String:
+		return "characters"
+	}
+
+	return ""
+}
+
+// isStrongPassword is the password rule of the request models, see IsStrongPassword
+func isStrongPassword(fl validator.FieldLevel) bool {
+	return IsStrongPassword(fl.Field().String())
+}
+
+// IsStrongPassword checks that the password is long enough, fits in bcrypt and mixes letters and digits.
+// The minimum counts characters while the maximum counts bytes, as bcrypt does.
+func IsStrongPassword(password string) bool {
+	if len([]rune(password)) < MinPasswordLength || len(password) > MaxPasswordBytes {
+		return false
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	return hasLetter && hasDigit
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/i18n"
+	"github.com/gin-gonic/gin/binding"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFieldErrors(t *testing.T) {
+	Register()
+
+	tests := []struct {
+		name   string
+		req    interface{}
+		want   []response.FieldError
+		wantOK bool
+	}{
+		{
+			name: "valid register request",
+			req: user.RegisterRequest{
+				Email:    "test@testing.com",
+				Password: "password123",
+				Name:     "test",
+			},
+			want:   nil,
+			wantOK: false,
+		},
+		{
+			name: "invalid register request",
+			req: user.RegisterRequest{
+				Email:    "test",
+				Password: "password",
+			},
+			want: []response.FieldError{
+				{Field: "email", Code: "email", Message: "must be a valid email address"},
+				{Field: "password", Code: "password", Message: "must be at least 8 characters and at most 72 bytes, and contain a letter and a digit"},
+				{Field: "name", Code: "required", Message: "is required"},
+			},
+			wantOK: true,
+		},
+		{
+			name:   "order without lines",
+			req:    order.CreateOrderRequest{Details: []order.CreateOrderDetailRequest{}},
+			want:   []response.FieldError{{Field: "details", Code: "min", Message: "must have at least 1 item"}},
+			wantOK: true,
+		},
+		{
+			name: "order with too many lines",
+			req:  order.CreateOrderRequest{Details: make([]order.CreateOrderDetailRequest, order.MaxOrderLines+1)},
+			want: []response.FieldError{
+				{Field: "details", Code: "max", Message: "must have at most 50 items"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "order with invalid lines",
+			req: order.CreateOrderRequest{Details: []order.CreateOrderDetailRequest{
+				{BookID: 1, Qty: 1},
+				{BookID: 2, Qty: -1},
+			}},
+			want: []response.FieldError{
+				{Field: "details[1].quantity", Code: "gt", Message: "must be greater than 0"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "order with the same book twice",
+			req: order.CreateOrderRequest{Details: []order.CreateOrderDetailRequest{
+				{BookID: 1, Qty: 1},
+				{BookID: 1, Qty: 2},
+			}},
+			want:   []response.FieldError{{Field: "details", Code: "unique", Message: "must not contain duplicates"}},
+			wantOK: true,
+		},
+	}
+
+	Convey("FieldErrors", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				got, ok := FieldErrors(binding.Validator.ValidateStruct(tt.req), i18n.EN)
+				So(ok, ShouldEqual, tt.wantOK)
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+
+		Convey("in another locale", func() {
+			req := order.CreateOrderRequest{Details: []order.CreateOrderDetailRequest{{BookID: 1, Qty: 0}}}
+			got, ok := FieldErrors(binding.Validator.ValidateStruct(req), i18n.ID)
+			So(ok, ShouldBeTrue)
+			So(got, ShouldResemble, []response.FieldError{
+				{Field: "details[0].quantity", Code: "required", Message: "wajib diisi"},
+			})
+		})
+
+		Convey("not a validation error", func() {
+			got, ok := FieldErrors(errors.New("error"), i18n.EN)
+			So(ok, ShouldBeFalse)
+			So(got, ShouldBeNil)
+		})
+	})
+}
+
+func TestIsStrongPassword(t *testing.T) {
+	Register()
+
+	type req struct {
+		Password string `json:"password" binding:"password"`
+	}
+
+	Convey("isStrongPassword", t, func() {
+		So(binding.Validator.ValidateStruct(req{Password: "password123"}), ShouldBeNil)
+		So(binding.Validator.ValidateStruct(req{Password: "kata sandi 1"}), ShouldBeNil)
+		So(binding.Validator.ValidateStruct(req{Password: "pass12"}), ShouldNotBeNil)
+		So(binding.Validator.ValidateStruct(req{Password: "password"}), ShouldNotBeNil)
+		So(binding.Validator.ValidateStruct(req{Password: "12345678"}), ShouldNotBeNil)
+		So(binding.Validator.ValidateStruct(req{Password: strings.Repeat("a", 71) + "1"}), ShouldBeNil)
+		// 37 characters but 73 bytes, bcrypt would refuse it
+		So(binding.Validator.ValidateStruct(req{Password: strings.Repeat("é", 36) + "1"}), ShouldNotBeNil)
+	})
+}
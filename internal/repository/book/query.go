@@ -3,8 +3,113 @@ package book
 var (
 	queryGetAll = `
 		SELECT 
-			id, title, author, description, price
+			id, title, author, description, price, created_at, is_deleted
 		FROM 
 			books
+		WHERE
+			%s
+		ORDER BY
+			%s
+		LIMIT ?
+	`
+
+	queryGetByID = `
+		SELECT
+			id, title, author, description, price, created_at, updated_at, is_deleted
+		FROM
+			books
+		WHERE
+			id = ?
+		AND
+			%s
+	`
+
+	queryGetByIDs = `
+		SELECT
+			id, title, author, description, price, created_at, updated_at, is_deleted
+		FROM
+			books
+		WHERE
+			id = ANY(?)
+		AND
+			%s
+	`
+
+	queryCreate = `
+		INSERT INTO books
+			(title, author, description, price)
+		VALUES
+			(?, ?, ?, ?)
+		RETURNING
+			id, created_at
+	`
+
+	queryUpdate = `
+		UPDATE
+			books
+		SET
+			title = ?, author = ?, description = ?, price = ?, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			%s
+		RETURNING
+			created_at, updated_at, is_deleted
+	`
+
+	queryDelete = `
+		UPDATE
+			books
+		SET
+			is_deleted = true, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			is_deleted = false
+	`
+
+	queryRestore = `
+		UPDATE
+			books
+		SET
+			is_deleted = false, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			is_deleted = true
+		RETURNING
+			id, title, author, description, price, created_at, updated_at, is_deleted
+	`
+
+	querySearch = `
+		SELECT
+			id, title, author, description, price, created_at, is_deleted,
+			ts_rank(search_vector, query) AS rank,
+			ts_headline('english', escape_html(title), query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_snippet,
+			ts_headline('english', escape_html(COALESCE(description, '')), query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS description_snippet
+		FROM
+			books, websearch_to_tsquery('english', ?) query
+		WHERE
+			search_vector @@ query
+		AND
+			%s
+		ORDER BY
+			rank DESC, id ASC
+		LIMIT ?
+	`
+
+	querySearchSimilar = `
+		SELECT
+			id, title, author, description, price, created_at, is_deleted,
+			GREATEST(word_similarity(?, title), word_similarity(?, author)) AS rank
+		FROM
+			books
+		WHERE
+			(? <%% title OR ? <%% author)
+		AND
+			%s
+		ORDER BY
+			rank DESC, id ASC
+		LIMIT ?
 	`
 )
@@ -2,36 +2,272 @@ package book
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
-	"github.com/jmoiron/sqlx"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
+	"github.com/lib/pq"
 )
 
 type repository struct {
-	db *sqlx.DB
+	db db.Executor
 }
 
-func New(db *sqlx.DB) *repository {
+func New(db db.Executor) *repository {
 	return &repository{
 		db: db,
 	}
 }
 
-func (r *repository) GetAll(ctx context.Context) (book.BookModels, error) {
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
+// sortColumns maps the sort options to the columns the books are ordered by
+var sortColumns = map[string]string{
+	book.SortCreatedAt: "created_at",
+	book.SortPrice:     "price",
+	book.SortTitle:     "title",
+}
+
+// List returns a page of books matching the filter, ordered by the sort column and then by id
+// so books with the same sort value keep a stable order across pages
+func (r *repository) List(ctx context.Context, filter book.BookFilter) (book.BookModels, error) {
 	var res book.BookModels
 
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryGetAll))
+	query, args, err := buildListQuery(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.List] failed to build query: %w", db.Translate(err))
+	}
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(query))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.List] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.SelectContext(ctx, &res, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.List] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+func buildListQuery(ctx context.Context, filter book.BookFilter) (string, []interface{}, error) {
+	column, ok := sortColumns[filter.Sort]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown sort option %q", filter.Sort)
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	var args []interface{}
+	conditions := []string{softdelete.Scope(ctx, "is_deleted")}
+
+	if filter.Author != "" {
+		conditions = append(conditions, "author ILIKE ?")
+		args = append(args, "%"+filter.Author+"%")
+	}
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "price >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "price <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison))
+		switch filter.Sort {
+		case book.SortPrice:
+			args = append(args, filter.After.Price)
+		case book.SortTitle:
+			args = append(args, filter.After.Title)
+		default:
+			args = append(args, filter.After.CreatedAt)
+		}
+		args = append(args, filter.After.ID)
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(queryGetAll,
+		strings.Join(conditions, " AND "),
+		fmt.Sprintf("%s %s, id %s", column, direction, direction),
+	)
+
+	return query, args, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id int64) (*book.BookModel, error) {
+	var res book.BookModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetByID, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.GetByID] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.GetContext(ctx, &res, id)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.GetByID] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// GetByIDs returns the books with the given ids. Books that do not exist are left out.
+func (r *repository) GetByIDs(ctx context.Context, ids []int64) (book.BookModels, error) {
+	var res book.BookModels
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetByIDs, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.GetByIDs] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.SelectContext(ctx, &res, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.GetByIDs] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+func (r *repository) Create(ctx context.Context, req book.BookModel) (*book.BookModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreate))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Create] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.Title, req.Author, req.Description, req.Price).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Create] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+// Update replaces the fields of a book that is not deleted, unless the context includes
+// deleted rows. It returns sql.ErrNoRows when there is no such book.
+func (r *repository) Update(ctx context.Context, req book.BookModel) (*book.BookModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryUpdate, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Update] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.Title, req.Author, req.Description, req.Price, req.ID).
+		Scan(&req.CreatedAt, &req.UpdatedAt, &req.IsDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Update] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+// Delete soft deletes a book. It returns sql.ErrNoRows when the book does not exist
+// or has already been deleted.
+func (r *repository) Delete(ctx context.Context, id int64) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryDelete))
+	if err != nil {
+		return fmt.Errorf("[BookRepo.Delete] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("[BookRepo.Delete] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[BookRepo.Delete] failed to get affected rows: %w", db.Translate(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("[BookRepo.Delete] book %d not found: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// Restore undoes the soft deletion of a book. It returns sql.ErrNoRows when the book
+// does not exist or is not deleted.
+func (r *repository) Restore(ctx context.Context, id int64) (*book.BookModel, error) {
+	var res book.BookModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryRestore))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Restore] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.GetContext(ctx, &res, id)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Restore] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// Search returns the books matching the full-text query, the most relevant first
+func (r *repository) Search(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error) {
+	var res []book.BookSearchModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(querySearch, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Search] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.SelectContext(ctx, &res, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[BookRepo.Search] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+// SearchSimilar returns the books whose title or author contains a word similar to the query,
+// the most similar first. It tolerates typos the full-text search cannot match.
+func (r *repository) SearchSimilar(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error) {
+	var res []book.BookSearchModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(querySearchSimilar, softdelete.Scope(ctx, "is_deleted"))))
 	if err != nil {
-		return nil, fmt.Errorf("[BookRepo.GetAll] failed to prepare query: %v", err)
+		return nil, fmt.Errorf("[BookRepo.SearchSimilar] failed to prepare query: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = stmt.Close()
 	}()
 
-	err = stmt.SelectContext(ctx, &res)
+	err = stmt.SelectContext(ctx, &res, query, query, query, query, limit)
 	if err != nil {
-		return nil, fmt.Errorf("[BookRepo.GetAll] failed to execute query: %v", err)
+		return nil, fmt.Errorf("[BookRepo.SearchSimilar] failed to execute query: %w", db.Translate(err))
 	}
 
 	return res, nil
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -18,20 +22,25 @@ func newMock() (*repository, sqlmock.Sqlmock, *sql.DB) {
 	return New(sqlx.NewDb(db, "sqlmock")), mock, db
 }
 
-func Test_repository_GetAll(t *testing.T) {
+func Test_repository_List(t *testing.T) {
 	repo, mock, db := newMock()
 	defer db.Close()
 
+	filter := book.BookFilter{Sort: book.SortCreatedAt, Limit: 3}
+	query, _, _ := buildListQuery(context.Background(), filter)
+
 	tests := []struct {
 		name       string
+		filter     book.BookFilter
 		mock       func()
 		wantResult book.BookModels
 		wantErr    bool
 	}{
 		{
-			name: "success",
+			name:   "success",
+			filter: filter,
 			mock: func() {
-				mock.ExpectPrepare(queryGetAll).ExpectQuery().WillReturnRows(
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(3).WillReturnRows(
 					sqlmock.NewRows([]string{"id", "title", "author"}).AddRow(1, "Book 1", "Author 1").AddRow(2, "Book 2", "Author 2"),
 				)
 			},
@@ -42,28 +51,584 @@ func Test_repository_GetAll(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "error when preparing query",
+			name:       "error when building query",
+			filter:     book.BookFilter{Sort: "author", Limit: 3},
+			mock:       func() {},
+			wantResult: nil,
+			wantErr:    true,
+		},
+		{
+			name:   "error when preparing query",
+			filter: filter,
 			mock: func() {
-				mock.ExpectPrepare(queryGetAll).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
 			},
 			wantResult: nil,
 			wantErr:    true,
 		},
 		{
-			name: "error when executing query",
+			name:   "error when executing query",
+			filter: filter,
 			mock: func() {
-				mock.ExpectPrepare(queryGetAll).ExpectQuery().WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(3).WillReturnError(errors.New("error"))
 			},
 			wantResult: nil,
 			wantErr:    true,
 		},
 	}
 
-	Convey("Test Book Repository - Get All", t, func() {
+	Convey("Test Book Repository - List", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.List(context.Background(), tt.filter)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_buildListQuery(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minPrice, maxPrice := int64(100000), int64(200000)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		filter    book.BookFilter
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "default",
+			ctx:       context.Background(),
+			filter:    book.BookFilter{Sort: book.SortCreatedAt, Limit: 21},
+			wantQuery: fmt.Sprintf(queryGetAll, "is_deleted = false", "created_at ASC, id ASC"),
+			wantArgs:  []interface{}{21},
+		},
+		{
+			name:      "including deleted books",
+			ctx:       softdelete.WithDeleted(context.Background()),
+			filter:    book.BookFilter{Sort: book.SortTitle, Limit: 21},
+			wantQuery: fmt.Sprintf(queryGetAll, "true", "title ASC, id ASC"),
+			wantArgs:  []interface{}{21},
+		},
+		{
+			name: "with filters and cursor",
+			ctx:  context.Background(),
+			filter: book.BookFilter{
+				Author:   "hoover",
+				MinPrice: &minPrice,
+				MaxPrice: &maxPrice,
+				Sort:     book.SortPrice,
+				Desc:     true,
+				After:    &book.BookCursor{ID: 2, Sort: book.SortPrice, Desc: true, Price: 150000},
+				Limit:    21,
+			},
+			wantQuery: fmt.Sprintf(queryGetAll,
+				"is_deleted = false AND author ILIKE ? AND price >= ? AND price <= ? AND (price, id) < (?, ?)",
+				"price DESC, id DESC",
+			),
+			wantArgs: []interface{}{"%hoover%", minPrice, maxPrice, int64(150000), int64(2), 21},
+		},
+		{
+			name: "with created_at cursor",
+			ctx:  context.Background(),
+			filter: book.BookFilter{
+				Sort:  book.SortCreatedAt,
+				After: &book.BookCursor{ID: 2, Sort: book.SortCreatedAt, CreatedAt: createdAt},
+				Limit: 21,
+			},
+			wantQuery: fmt.Sprintf(queryGetAll, "is_deleted = false AND (created_at, id) > (?, ?)", "created_at ASC, id ASC"),
+			wantArgs:  []interface{}{createdAt, int64(2), 21},
+		},
+		{
+			name:    "unknown sort",
+			ctx:     context.Background(),
+			filter:  book.BookFilter{Sort: "author", Limit: 21},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test Book Repository - Build List Query", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				gotQuery, gotArgs, err := buildListQuery(tt.ctx, tt.filter)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(gotQuery, ShouldEqual, tt.wantQuery)
+					So(gotArgs, ShouldResemble, tt.wantArgs)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetByID(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult *book.BookModel
+		wantErr    error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByID, "is_deleted = false")).ExpectQuery().WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "title", "author", "price"}).AddRow(1, "Book 1", "Author 1", 150000),
+				)
+			},
+			wantResult: &book.BookModel{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByID, "is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByID, "is_deleted = false")).ExpectQuery().WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	Convey("Test Book Repository - Get By ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.GetByID(context.Background(), 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_Create(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	now := time.Now()
+	req := book.BookModel{
+		Title:       "Book 1",
+		Author:      "Author 1",
+		Description: sql.NullString{String: "Description 1", Valid: true},
+		Price:       150000,
+	}
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult *book.BookModel
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectQuery().WithArgs(req.Title, req.Author, req.Description, req.Price).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, now))
+			},
+			wantResult: &book.BookModel{
+				ID:          1,
+				Title:       "Book 1",
+				Author:      "Author 1",
+				Description: sql.NullString{String: "Description 1", Valid: true},
+				Price:       150000,
+				CreatedAt:   now,
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectQuery().WithArgs(req.Title, req.Author, req.Description, req.Price).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test Book Repository - Create", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.Create(context.Background(), req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_Update(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	now := time.Now()
+	req := book.BookModel{
+		ID:     1,
+		Title:  "Book 1",
+		Author: "Author 1",
+		Price:  150000,
+	}
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult *book.BookModel
+		wantErr    error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryUpdate, "is_deleted = false")).ExpectQuery().WithArgs(req.Title, req.Author, req.Description, req.Price, req.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "is_deleted"}).AddRow(now, now, false))
+			},
+			wantResult: &book.BookModel{
+				ID:        1,
+				Title:     "Book 1",
+				Author:    "Author 1",
+				Price:     150000,
+				CreatedAt: now,
+				UpdatedAt: sql.NullTime{Time: now, Valid: true},
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryUpdate, "is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryUpdate, "is_deleted = false")).ExpectQuery().WithArgs(req.Title, req.Author, req.Description, req.Price, req.ID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	Convey("Test Book Repository - Update", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.Update(context.Background(), req)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_Delete(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	Convey("Test Book Repository - Delete", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.Delete(context.Background(), 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_Restore(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult *book.BookModel
+		wantErr    error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryRestore).ExpectQuery().WithArgs(int64(1)).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "title", "author", "price", "is_deleted"}).AddRow(1, "Book 1", "Author 1", 150000, false),
+				)
+			},
+			wantResult: &book.BookModel{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryRestore).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(queryRestore).ExpectQuery().WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	Convey("Test Book Repository - Restore", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.Restore(context.Background(), 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_Search(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	query := fmt.Sprintf(querySearch, "is_deleted = false")
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult []book.BookSearchModel
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs("gatsby", 20).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "title", "author", "price", "rank", "title_snippet", "description_snippet"}).
+						AddRow(1, "The Great Gatsby", "F. Scott Fitzgerald", 100000, 0.6, "The Great <mark>Gatsby</mark>", "Description for The Great <mark>Gatsby</mark>"),
+				)
+			},
+			wantResult: []book.BookSearchModel{
+				{
+					BookModel:          book.BookModel{ID: 1, Title: "The Great Gatsby", Author: "F. Scott Fitzgerald", Price: 100000},
+					Rank:               0.6,
+					TitleSnippet:       sql.NullString{String: "The Great <mark>Gatsby</mark>", Valid: true},
+					DescriptionSnippet: sql.NullString{String: "Description for The Great <mark>Gatsby</mark>", Valid: true},
+				},
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs("gatsby", 20).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test Book Repository - Search", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.Search(context.Background(), "gatsby", 20)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_SearchSimilar(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	query := fmt.Sprintf(querySearchSimilar, "is_deleted = false")
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult []book.BookSearchModel
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs("gatsbi", "gatsbi", "gatsbi", "gatsbi", 20).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "title", "author", "price", "rank"}).
+						AddRow(1, "The Great Gatsby", "F. Scott Fitzgerald", 100000, 0.5),
+				)
+			},
+			wantResult: []book.BookSearchModel{
+				{
+					BookModel: book.BookModel{ID: 1, Title: "The Great Gatsby", Author: "F. Scott Fitzgerald", Price: 100000},
+					Rank:      0.5,
+				},
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs("gatsbi", "gatsbi", "gatsbi", "gatsbi", 20).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test Book Repository - Search Similar", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, err := repo.SearchSimilar(context.Background(), "gatsbi", 20)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(gotResult, ShouldResemble, tt.wantResult)
+			})
+		}
+	})
+}
+
+func Test_repository_GetByIDs(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	query := fmt.Sprintf(queryGetByIDs, "is_deleted = false")
+	ids := []int64{1, 2}
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult book.BookModels
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(pq.Array(ids)).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "title", "author", "price"}).AddRow(1, "Book 1", "Author 1", 150000),
+				)
+			},
+			wantResult: book.BookModels{
+				{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000},
+			},
+		},
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(pq.Array(ids)).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	Convey("Test Book Repository - Get By IDs", t, func() {
 		for _, tt := range tests {
 			Convey(tt.name, func() {
 				tt.mock()
-				gotResult, err := repo.GetAll(context.Background())
+				gotResult, err := repo.GetByIDs(context.Background(), ids)
 				if tt.wantErr {
 					So(err, ShouldNotBeNil)
 				}
@@ -0,0 +1,66 @@
+package idempotency
+
+var (
+	queryCreate = `
+		INSERT INTO idempotency_keys
+			(user_id, idempotency_key, request_hash, attempt_id)
+		VALUES
+			(?, ?, ?, ?)
+		ON CONFLICT
+			(user_id, idempotency_key)
+		DO UPDATE SET
+			attempt_id = EXCLUDED.attempt_id, created_at = TIMEZONE('UTC', NOW())
+		WHERE
+			idempotency_keys.status_code IS NULL
+		AND
+			idempotency_keys.request_hash = EXCLUDED.request_hash
+		AND
+			idempotency_keys.created_at < ?
+	`
+
+	queryGet = `
+		SELECT
+			id, user_id, idempotency_key, request_hash, status_code, content_type, response_body, created_at
+		FROM
+			idempotency_keys
+		WHERE
+			user_id = ?
+		AND
+			idempotency_key = ?
+	`
+
+	queryComplete = `
+		UPDATE
+			idempotency_keys
+		SET
+			status_code = ?, content_type = ?, response_body = ?, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			user_id = ?
+		AND
+			idempotency_key = ?
+		AND
+			attempt_id = ?
+		AND
+			status_code IS NULL
+	`
+
+	queryDelete = `
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			user_id = ?
+		AND
+			idempotency_key = ?
+		AND
+			attempt_id = ?
+		AND
+			status_code IS NULL
+	`
+
+	queryDeleteExpired = `
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			created_at < ?
+	`
+)
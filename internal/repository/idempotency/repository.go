@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/idempotency"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+)
+
+type repository struct {
+	db db.Executor
+}
+
+func New(db db.Executor) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
+// Create reserves the idempotency key of the user for a request. It returns false when
+// the key is already reserved, either by a request in flight or by a completed one. A key
+// reserved for the same request before staleBefore and never completed is reserved again for
+// req.AttemptID, the request holding it is assumed to have crashed.
+func (r *repository) Create(ctx context.Context, req idempotency.IdempotencyKeyModel, staleBefore time.Time) (bool, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreate))
+	if err != nil {
+		return false, fmt.Errorf("[IdempotencyRepo.Create] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, req.UserID, req.IdempotencyKey, req.RequestHash, req.AttemptID, staleBefore)
+	if err != nil {
+		return false, fmt.Errorf("[IdempotencyRepo.Create] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[IdempotencyRepo.Create] failed to get affected rows: %w", db.Translate(err))
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) Get(ctx context.Context, userID int64, key string) (*idempotency.IdempotencyKeyModel, error) {
+	var res idempotency.IdempotencyKeyModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGet))
+	if err != nil {
+		return nil, fmt.Errorf("[IdempotencyRepo.Get] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.GetContext(ctx, &res, userID, key); err != nil {
+		return nil, fmt.Errorf("[IdempotencyRepo.Get] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// Complete stores the response of the request so it can be replayed. It returns sql.ErrNoRows when
+// the key is no longer held by req.AttemptID, a retry took it over.
+func (r *repository) Complete(ctx context.Context, req idempotency.IdempotencyKeyModel) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryComplete))
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Complete] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, req.StatusCode, req.ContentType, req.ResponseBody, req.UserID, req.IdempotencyKey, req.AttemptID)
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Complete] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Complete] failed to get affected rows: %w", db.Translate(err))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("[IdempotencyRepo.Complete] key %s is not held by attempt %s: %w", req.IdempotencyKey, req.AttemptID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// Delete releases the idempotency key held by the attempt so the request can be retried. It returns
+// sql.ErrNoRows when a retry took the key over, the key is then left to the retry.
+func (r *repository) Delete(ctx context.Context, userID int64, key, attemptID string) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryDelete))
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Delete] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, userID, key, attemptID)
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Delete] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[IdempotencyRepo.Delete] failed to get affected rows: %w", db.Translate(err))
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("[IdempotencyRepo.Delete] key %s is not held by attempt %s: %w", key, attemptID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the idempotency keys created before the given time and returns how many were deleted
+func (r *repository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryDeleteExpired))
+	if err != nil {
+		return 0, fmt.Errorf("[IdempotencyRepo.DeleteExpired] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("[IdempotencyRepo.DeleteExpired] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("[IdempotencyRepo.DeleteExpired] failed to get affected rows: %w", db.Translate(err))
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,325 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erizkiatama/gotu-assignment/internal/model/idempotency"
+	"github.com/jmoiron/sqlx"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newMock() (*repository, sqlmock.Sqlmock, *sql.DB) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	return New(sqlx.NewDb(db, "sqlmock")), mock, db
+}
+
+func Test_repository_Create(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	req := idempotency.IdempotencyKeyModel{
+		UserID:         1,
+		IdempotencyKey: "key",
+		RequestHash:    "hash",
+		AttemptID:      "attempt",
+	}
+	staleBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectExec().WithArgs(req.UserID, req.IdempotencyKey, req.RequestHash, req.AttemptID, staleBefore).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "key already reserved",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectExec().WithArgs(req.UserID, req.IdempotencyKey, req.RequestHash, req.AttemptID, staleBefore).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: false,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectExec().WithArgs(req.UserID, req.IdempotencyKey, req.RequestHash, req.AttemptID, staleBefore).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			want: true,
+		},
+	}
+
+	Convey("Test Create", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.Create(context.Background(), req, staleBefore)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_Get(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	columns := []string{"id", "user_id", "idempotency_key", "request_hash", "status_code", "content_type", "response_body", "created_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *idempotency.IdempotencyKeyModel
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGet).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "key not found",
+			mock: func() {
+				mock.ExpectPrepare(queryGet).ExpectQuery().WithArgs(int64(1), "key").WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGet).ExpectQuery().WithArgs(int64(1), "key").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "key", "hash", 201, "application/json", []byte(`{"result":{}}`), createdAt))
+			},
+			want: &idempotency.IdempotencyKeyModel{
+				ID:             1,
+				UserID:         1,
+				IdempotencyKey: "key",
+				RequestHash:    "hash",
+				StatusCode:     sql.NullInt64{Int64: 201, Valid: true},
+				ContentType:    sql.NullString{String: "application/json", Valid: true},
+				ResponseBody:   []byte(`{"result":{}}`),
+				CreatedAt:      createdAt,
+			},
+		},
+	}
+
+	Convey("Test Get", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.Get(context.Background(), 1, "key")
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_Complete(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	req := idempotency.IdempotencyKeyModel{
+		UserID:         1,
+		IdempotencyKey: "key",
+		AttemptID:      "attempt",
+		StatusCode:     sql.NullInt64{Int64: 201, Valid: true},
+		ContentType:    sql.NullString{String: "application/json", Valid: true},
+		ResponseBody:   []byte(`{"result":{}}`),
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryComplete).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryComplete).ExpectExec().
+					WithArgs(req.StatusCode, req.ContentType, req.ResponseBody, req.UserID, req.IdempotencyKey, req.AttemptID).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "key taken over by a retry",
+			mock: func() {
+				mock.ExpectPrepare(queryComplete).ExpectExec().
+					WithArgs(req.StatusCode, req.ContentType, req.ResponseBody, req.UserID, req.IdempotencyKey, req.AttemptID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryComplete).ExpectExec().
+					WithArgs(req.StatusCode, req.ContentType, req.ResponseBody, req.UserID, req.IdempotencyKey, req.AttemptID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	Convey("Test Complete", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.Complete(context.Background(), req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_Delete(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1), "key", "attempt").WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "key taken over by a retry",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1), "key", "attempt").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryDelete).ExpectExec().WithArgs(int64(1), "key", "attempt").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	Convey("Test Delete", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.Delete(context.Background(), 1, "key", "attempt")
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_DeleteExpired(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	before := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDeleteExpired).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryDeleteExpired).ExpectExec().WithArgs(before).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryDeleteExpired).ExpectExec().WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want: 3,
+		},
+	}
+
+	Convey("Test Delete Expired", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.DeleteExpired(context.Background(), before)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package inventory
+
+var (
+	queryGetStock = `
+		SELECT
+			id AS book_id, stock
+		FROM
+			books
+		WHERE
+			id = ?
+		AND
+			%s
+	`
+
+	// The books are locked in the order of their ids before their stock is changed, so concurrent
+	// orders of the same books cannot deadlock whatever the order of their lines
+	queryDecrementStock = `
+		WITH r AS (
+			SELECT UNNEST(?::INT8[]) AS id, UNNEST(?::INT8[]) AS quantity
+		), locked AS (
+			SELECT id FROM books WHERE id IN (SELECT id FROM r) AND %s ORDER BY id FOR UPDATE
+		)
+		UPDATE
+			books b
+		SET
+			stock = b.stock - r.quantity
+		FROM
+			r, locked
+		WHERE
+			b.id = r.id
+		AND
+			b.id = locked.id
+		AND
+			b.stock >= r.quantity
+		RETURNING
+			b.id
+	`
+
+	queryIncrementStock = `
+		WITH r AS (
+			SELECT UNNEST(?::INT8[]) AS id, UNNEST(?::INT8[]) AS quantity
+		), locked AS (
+			SELECT id FROM books WHERE id IN (SELECT id FROM r) ORDER BY id FOR UPDATE
+		)
+		UPDATE
+			books b
+		SET
+			stock = b.stock + r.quantity
+		FROM
+			r, locked
+		WHERE
+			b.id = r.id
+		AND
+			b.id = locked.id
+	`
+
+	queryAdjustStock = `
+		UPDATE
+			books
+		SET
+			stock = stock + ?
+		WHERE
+			id = ?
+		AND
+			%s
+		RETURNING
+			stock
+	`
+
+	queryCreateAdjustment = `
+		INSERT INTO stock_adjustments
+			(book_id, user_id, quantity_change, stock, reason)
+		VALUES
+			(?, ?, ?, ?, ?)
+		RETURNING
+			id, created_at
+	`
+
+	queryGetAdjustments = `
+		SELECT
+			id, book_id, user_id, quantity_change, stock, reason, created_at
+		FROM
+			stock_adjustments
+		WHERE
+			book_id = ?
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT ?
+	`
+)
@@ -0,0 +1,151 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
+	"github.com/lib/pq"
+)
+
+type repository struct {
+	db db.Executor
+}
+
+func New(db db.Executor) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
+func (r *repository) GetStock(ctx context.Context, bookID int64) (*inventory.StockModel, error) {
+	var res inventory.StockModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetStock, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.GetStock] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.GetContext(ctx, &res, bookID); err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.GetStock] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// DecrementStock takes the quantities from the stock of the books and returns the ids of the books
+// it was taken from. Books that are deleted or do not have enough stock are left untouched. The
+// stock is checked by the update itself, so concurrent decrements cannot take more than what is left,
+// and the books are locked in the order of their ids, so they cannot deadlock.
+func (r *repository) DecrementStock(ctx context.Context, changes []inventory.StockChangeModel) ([]int64, error) {
+	var (
+		res           []int64
+		bookIDs, qtys []int64
+	)
+	for _, change := range changes {
+		bookIDs = append(bookIDs, change.BookID)
+		qtys = append(qtys, change.Qty)
+	}
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryDecrementStock, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.DecrementStock] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res, pq.Array(bookIDs), pq.Array(qtys)); err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.DecrementStock] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+// IncrementStock gives the quantities back to the stock of the books. Deleted books get their stock
+// back as well, so it stays correct if they are restored.
+func (r *repository) IncrementStock(ctx context.Context, changes []inventory.StockChangeModel) error {
+	var bookIDs, qtys []int64
+	for _, change := range changes {
+		bookIDs = append(bookIDs, change.BookID)
+		qtys = append(qtys, change.Qty)
+	}
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryIncrementStock))
+	if err != nil {
+		return fmt.Errorf("[InventoryRepo.IncrementStock] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if _, err := stmt.ExecContext(ctx, pq.Array(bookIDs), pq.Array(qtys)); err != nil {
+		return fmt.Errorf("[InventoryRepo.IncrementStock] failed to execute query: %w", db.Translate(err))
+	}
+
+	return nil
+}
+
+// AdjustStock adds qtyChange to the stock of the book and returns the new stock. It returns
+// sql.ErrNoRows when the book does not exist and fails when the stock would go below zero.
+func (r *repository) AdjustStock(ctx context.Context, bookID, qtyChange int64) (int64, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryAdjustStock, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return 0, fmt.Errorf("[InventoryRepo.AdjustStock] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	var stock int64
+	if err := stmt.GetContext(ctx, &stock, qtyChange, bookID); err != nil {
+		return 0, fmt.Errorf("[InventoryRepo.AdjustStock] failed to execute query: %w", db.Translate(err))
+	}
+
+	return stock, nil
+}
+
+func (r *repository) CreateAdjustment(ctx context.Context, req inventory.StockAdjustmentModel) (*inventory.StockAdjustmentModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreateAdjustment))
+	if err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.CreateAdjustment] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.BookID, req.UserID, req.QtyChange, req.Stock, req.Reason).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.CreateAdjustment] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+// GetAdjustments returns the latest stock adjustments of the book, newest first
+func (r *repository) GetAdjustments(ctx context.Context, bookID int64, limit int) ([]inventory.StockAdjustmentModel, error) {
+	var res []inventory.StockAdjustmentModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetAdjustments))
+	if err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.GetAdjustments] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res, bookID, limit); err != nil {
+		return nil, fmt.Errorf("[InventoryRepo.GetAdjustments] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,383 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newMock() (*repository, sqlmock.Sqlmock, *sql.DB) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	return New(sqlx.NewDb(db, "sqlmock")), mock, db
+}
+
+func Test_repository_GetStock(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *inventory.StockModel
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetStock, "is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetStock, "is_deleted = false")).ExpectQuery().WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetStock, "is_deleted = false")).ExpectQuery().WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"book_id", "stock"}).AddRow(1, 10))
+			},
+			want: &inventory.StockModel{BookID: 1, Stock: 10},
+		},
+	}
+
+	Convey("Test Get Stock", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetStock(context.Background(), 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_DecrementStock(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	changes := []inventory.StockChangeModel{
+		{BookID: 1, Qty: 2},
+		{BookID: 2, Qty: 5},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryDecrementStock, "is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryDecrementStock, "is_deleted = false")).ExpectQuery().
+					WithArgs(pq.Array([]int64{1, 2}), pq.Array([]int64{2, 5})).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "some books out of stock",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryDecrementStock, "is_deleted = false")).ExpectQuery().
+					WithArgs(pq.Array([]int64{1, 2}), pq.Array([]int64{2, 5})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			want: []int64{1},
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryDecrementStock, "is_deleted = false")).ExpectQuery().
+					WithArgs(pq.Array([]int64{1, 2}), pq.Array([]int64{2, 5})).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+			},
+			want: []int64{1, 2},
+		},
+	}
+
+	Convey("Test Decrement Stock", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.DecrementStock(context.Background(), changes)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_IncrementStock(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	changes := []inventory.StockChangeModel{
+		{BookID: 1, Qty: 2},
+		{BookID: 2, Qty: 5},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryIncrementStock).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryIncrementStock).ExpectExec().
+					WithArgs(pq.Array([]int64{1, 2}), pq.Array([]int64{2, 5})).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryIncrementStock).ExpectExec().
+					WithArgs(pq.Array([]int64{1, 2}), pq.Array([]int64{2, 5})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+	}
+
+	Convey("Test Increment Stock", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.IncrementStock(context.Background(), changes)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_AdjustStock(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryAdjustStock, "is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryAdjustStock, "is_deleted = false")).ExpectQuery().WithArgs(int64(-3), int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(fmt.Sprintf(queryAdjustStock, "is_deleted = false")).ExpectQuery().WithArgs(int64(-3), int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(7))
+			},
+			want: 7,
+		},
+	}
+
+	Convey("Test Adjust Stock", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.AdjustStock(context.Background(), 1, -3)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_CreateAdjustment(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	req := inventory.StockAdjustmentModel{
+		BookID:    1,
+		UserID:    2,
+		QtyChange: -3,
+		Stock:     7,
+		Reason:    "damaged",
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *inventory.StockAdjustmentModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateAdjustment).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateAdjustment).ExpectQuery().
+					WithArgs(req.BookID, req.UserID, req.QtyChange, req.Stock, req.Reason).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateAdjustment).ExpectQuery().
+					WithArgs(req.BookID, req.UserID, req.QtyChange, req.Stock, req.Reason).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+			},
+			want: &inventory.StockAdjustmentModel{
+				ID:        1,
+				BookID:    1,
+				UserID:    2,
+				QtyChange: -3,
+				Stock:     7,
+				Reason:    "damaged",
+				CreatedAt: createdAt,
+			},
+		},
+	}
+
+	Convey("Test Create Adjustment", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.CreateAdjustment(context.Background(), req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetAdjustments(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	columns := []string{"id", "book_id", "user_id", "quantity_change", "stock", "reason", "created_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []inventory.StockAdjustmentModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetAdjustments).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetAdjustments).ExpectQuery().WithArgs(int64(1), 20).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGetAdjustments).ExpectQuery().WithArgs(int64(1), 20).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 2, 10, 10, "restock", createdAt))
+			},
+			want: []inventory.StockAdjustmentModel{
+				{
+					ID:        1,
+					BookID:    1,
+					UserID:    2,
+					QtyChange: 10,
+					Stock:     10,
+					Reason:    "restock",
+					CreatedAt: createdAt,
+				},
+			},
+		},
+	}
+
+	Convey("Test Get Adjustments", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetAdjustments(context.Background(), 1, 20)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -7,7 +7,7 @@ var (
 		VALUES
 			(?, ?, ?)
 		RETURNING
-			id
+			id, status, created_at
 	`
 
 	queryCreateDetail = `
@@ -21,11 +21,14 @@ var (
 
 	queryGetAllOrder = `
 		SELECT
-			id, user_id, total_quantity, total_price
+			id, user_id, total_quantity, total_price, status, created_at, updated_at
 		FROM
 			orders
 		WHERE
-			user_id = ?
+			%s
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT ?
 	`
 
 	queryGetOrderDetail = `
@@ -41,5 +44,96 @@ var (
 			od.order_id = ?
 		AND
 			o.user_id = ?
+		AND
+			%s
+	`
+
+	queryGetOrderDetailsByOrderIDs = `
+		SELECT
+			od.id, od.order_id, od.book_id, od.quantity, od.price,
+			b.title AS book_title, b.author AS book_author
+		FROM
+			order_details od
+		JOIN
+			books b
+		ON
+			od.book_id = b.id
+		WHERE
+			od.order_id = ANY(?)
+		AND
+			%s
+		ORDER BY
+			od.order_id, od.id
+	`
+
+	queryGetOrderWithDetails = `
+		SELECT
+			o.id, o.user_id, o.total_quantity, o.total_price, o.status, o.created_at, o.updated_at,
+			od.id AS detail_id, od.book_id, od.quantity, od.price,
+			b.title AS book_title, b.author AS book_author
+		FROM
+			orders o
+		LEFT JOIN
+			order_details od
+		ON
+			od.order_id = o.id
+		AND
+			%s
+		LEFT JOIN
+			books b
+		ON
+			od.book_id = b.id
+		WHERE
+			o.id = ?
+		AND
+			o.user_id = ?
+		AND
+			%s
+		ORDER BY
+			od.id
+	`
+
+	queryGetByID = `
+		SELECT
+			id, user_id, total_quantity, total_price, status, created_at, updated_at
+		FROM
+			orders
+		WHERE
+			id = ?
+		AND
+			%s
+	`
+
+	queryUpdateStatus = `
+		UPDATE
+			orders
+		SET
+			status = ?, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			status = ?
+		RETURNING
+			updated_at
+	`
+
+	queryCreateStatusHistory = `
+		INSERT INTO order_status_histories
+			(order_id, from_status, to_status, actor_id, reason)
+		VALUES
+			(?, ?, ?, ?, ?)
+		RETURNING
+			id, created_at
+	`
+
+	queryGetStatusHistory = `
+		SELECT
+			id, order_id, from_status, to_status, actor_id, reason, created_at
+		FROM
+			order_status_histories
+		WHERE
+			order_id = ?
+		ORDER BY
+			created_at, id
 	`
 )
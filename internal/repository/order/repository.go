@@ -2,38 +2,46 @@ package order
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
-	"github.com/jmoiron/sqlx"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
+	"github.com/lib/pq"
 )
 
 type repository struct {
-	db *sqlx.DB
+	db db.Executor
 }
 
-func New(db *sqlx.DB) *repository {
+func New(db db.Executor) *repository {
 	return &repository{
 		db: db,
 	}
 }
 
-func (r *repository) CreateOrder(ctx context.Context, req order.OrderModel) (int64, error) {
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryCreate))
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
+func (r *repository) CreateOrder(ctx context.Context, req order.OrderModel) (*order.OrderModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreate))
 	if err != nil {
-		return 0, fmt.Errorf("[OrderRepo.CreateOrder] failed to prepare statement: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.CreateOrder] failed to prepare statement: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = stmt.Close()
 	}()
 
-	var id int64
-	if err := stmt.GetContext(ctx, &id, req.UserID, req.TotalQty, req.TotalPrice); err != nil {
-		return 0, fmt.Errorf("[OrderRepo.CreateOrder] failed to execute statement: %v", err)
+	err = stmt.QueryRowxContext(ctx, req.UserID, req.TotalQty, req.TotalPrice).Scan(&req.ID, &req.Status, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.CreateOrder] failed to execute statement: %w", db.Translate(err))
 	}
 
-	return id, nil
+	return &req, nil
 }
 
 func (r *repository) BulkCreateOrderDetail(ctx context.Context, reqs []order.OrderDetailModel) ([]order.OrderDetailModel, error) {
@@ -46,9 +54,9 @@ func (r *repository) BulkCreateOrderDetail(ctx context.Context, reqs []order.Ord
 		args = append(args, req.OrderID, req.BookID, req.Qty, req.Price)
 	}
 
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryCreateDetail, strings.TrimSuffix(values, ","))))
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryCreateDetail, strings.TrimSuffix(values, ","))))
 	if err != nil {
-		return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to prepare statement: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to prepare statement: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = stmt.Close()
@@ -56,45 +64,220 @@ func (r *repository) BulkCreateOrderDetail(ctx context.Context, reqs []order.Ord
 
 	rows, err := stmt.QueryxContext(ctx, args...)
 	if err != nil {
-		return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to execute statement: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to execute statement: %w", db.Translate(err))
 	}
+	defer func() {
+		_ = rows.Close()
+	}()
 
 	i := 0
 	for rows.Next() {
 		if err := rows.Scan(&reqs[i].ID); err != nil {
-			return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to scan row: %v", err)
+			return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to scan row: %w", db.Translate(err))
 		}
 		i++
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[OrderRepo.BulkCreateOrderDetail] failed to iterate rows: %w", db.Translate(err))
+	}
 
 	return reqs, nil
 }
 
-func (r *repository) GetAllOrder(ctx context.Context, userID int64) ([]order.OrderModel, error) {
+// GetAllOrder returns a page of the orders of the user matching the filter, newest first. Orders
+// created at the same time are ordered by id so they keep a stable order across pages.
+func (r *repository) GetAllOrder(ctx context.Context, userID int64, filter order.OrderFilter) ([]order.OrderModel, error) {
 	var res []order.OrderModel
 
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryGetAllOrder))
+	query, args := buildGetAllOrderQuery(ctx, userID, filter)
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(query))
 	if err != nil {
-		return nil, fmt.Errorf("[OrderRepo.GetAllOrder] failed to prepare statement: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.GetAllOrder] failed to prepare statement: %w", db.Translate(err))
 	}
+	defer func() {
+		_ = stmt.Close()
+	}()
 
-	if err := stmt.SelectContext(ctx, &res, userID); err != nil {
-		return nil, fmt.Errorf("[OrderRepo.GetAllOrder] failed to execute query: %v", err)
+	if err := stmt.SelectContext(ctx, &res, args...); err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetAllOrder] failed to execute query: %w", db.Translate(err))
 	}
 
 	return res, nil
 }
 
+func buildGetAllOrderQuery(ctx context.Context, userID int64, filter order.OrderFilter) (string, []interface{}) {
+	conditions := []string{"user_id = ?", softdelete.Scope(ctx, "is_deleted")}
+	args := []interface{}{userID}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.CreatedTo)
+	}
+	if filter.After != nil {
+		conditions = append(conditions, "(created_at, id) < (?, ?)")
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+	}
+
+	args = append(args, filter.Limit)
+	return fmt.Sprintf(queryGetAllOrder, strings.Join(conditions, " AND ")), args
+}
+
 func (r *repository) GetOrderDetail(ctx context.Context, userID, orderID int64) ([]order.OrderDetailModel, error) {
 	var res []order.OrderDetailModel
 
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryGetOrderDetail))
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetOrderDetail, softdelete.Scope(ctx, "o.is_deleted", "od.is_deleted"))))
 	if err != nil {
-		return nil, fmt.Errorf("[OrderRepo.GetOrderDetail] failed to prepare statement: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.GetOrderDetail] failed to prepare statement: %w", db.Translate(err))
 	}
+	defer func() {
+		_ = stmt.Close()
+	}()
 
 	if err := stmt.SelectContext(ctx, &res, orderID, userID); err != nil {
-		return nil, fmt.Errorf("[OrderRepo.GetOrderDetail] failed to execute query: %v", err)
+		return nil, fmt.Errorf("[OrderRepo.GetOrderDetail] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+// GetOrderDetailsByOrderIDs returns the lines of all the given orders in one query, with the title
+// and author of their books. Books that have been deleted since are still joined.
+func (r *repository) GetOrderDetailsByOrderIDs(ctx context.Context, orderIDs []int64) ([]order.OrderDetailModel, error) {
+	var res []order.OrderDetailModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetOrderDetailsByOrderIDs, softdelete.Scope(ctx, "od.is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetOrderDetailsByOrderIDs] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res, pq.Array(orderIDs)); err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetOrderDetailsByOrderIDs] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+// GetOrderWithDetails returns an order of the user with its lines, which are empty when the order has none.
+// It returns sql.ErrNoRows when the order does not exist or belongs to another user.
+func (r *repository) GetOrderWithDetails(ctx context.Context, userID, orderID int64) (*order.OrderModel, []order.OrderDetailModel, error) {
+	var rows []order.OrderWithDetailRowModel
+
+	query := fmt.Sprintf(queryGetOrderWithDetails, softdelete.Scope(ctx, "od.is_deleted"), softdelete.Scope(ctx, "o.is_deleted"))
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(query))
+	if err != nil {
+		return nil, nil, fmt.Errorf("[OrderRepo.GetOrderWithDetails] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &rows, orderID, userID); err != nil {
+		return nil, nil, fmt.Errorf("[OrderRepo.GetOrderWithDetails] failed to execute query: %w", db.Translate(err))
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("[OrderRepo.GetOrderWithDetails] order %d not found: %w", orderID, sql.ErrNoRows)
+	}
+
+	details := make([]order.OrderDetailModel, 0, len(rows))
+	for _, row := range rows {
+		if !row.DetailID.Valid {
+			continue
+		}
+		details = append(details, order.OrderDetailModel{
+			ID:         row.DetailID.Int64,
+			OrderID:    row.ID,
+			BookID:     row.BookID.Int64,
+			Qty:        row.Qty.Int64,
+			Price:      row.Price.Int64,
+			BookTitle:  row.BookTitle.String,
+			BookAuthor: row.BookAuthor.String,
+		})
+	}
+
+	o := rows[0].OrderModel
+	return &o, details, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, orderID int64) (*order.OrderModel, error) {
+	var res order.OrderModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetByID, softdelete.Scope(ctx, "is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetByID] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.GetContext(ctx, &res, orderID); err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetByID] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// UpdateStatus moves the order from one status to another and returns the time it was updated.
+// It returns sql.ErrNoRows when the order is no longer in the from status, so concurrent changes
+// cannot both apply.
+func (r *repository) UpdateStatus(ctx context.Context, orderID int64, from, to string) (time.Time, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryUpdateStatus))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("[OrderRepo.UpdateStatus] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	var updatedAt time.Time
+	if err := stmt.GetContext(ctx, &updatedAt, to, orderID, from); err != nil {
+		return time.Time{}, fmt.Errorf("[OrderRepo.UpdateStatus] failed to execute statement: %w", db.Translate(err))
+	}
+
+	return updatedAt, nil
+}
+
+func (r *repository) CreateStatusHistory(ctx context.Context, req order.OrderStatusHistoryModel) (*order.OrderStatusHistoryModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreateStatusHistory))
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.CreateStatusHistory] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.OrderID, req.FromStatus, req.ToStatus, req.ActorID, req.Reason).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.CreateStatusHistory] failed to execute statement: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+// GetStatusHistory returns the status changes of the order, oldest first
+func (r *repository) GetStatusHistory(ctx context.Context, orderID int64) ([]order.OrderStatusHistoryModel, error) {
+	var res []order.OrderStatusHistoryModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetStatusHistory))
+	if err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetStatusHistory] failed to prepare statement: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res, orderID); err != nil {
+		return nil, fmt.Errorf("[OrderRepo.GetStatusHistory] failed to execute query: %w", db.Translate(err))
 	}
 
 	return res, nil
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -23,6 +26,8 @@ func Test_repository_CreateOrder(t *testing.T) {
 	repo, mock, db := newMock()
 	defer db.Close()
 
+	createdAt := time.Now()
+
 	type args struct {
 		req order.OrderModel
 	}
@@ -30,7 +35,7 @@ func Test_repository_CreateOrder(t *testing.T) {
 		name    string
 		args    args
 		mock    func(args)
-		want    int64
+		want    *order.OrderModel
 		wantErr bool
 	}{
 		{
@@ -44,7 +49,7 @@ func Test_repository_CreateOrder(t *testing.T) {
 			mock: func(args args) {
 				mock.ExpectPrepare(queryCreate).WillReturnError(errors.New("error"))
 			},
-			want:    0,
+			want:    nil,
 			wantErr: true,
 		},
 		{
@@ -59,7 +64,7 @@ func Test_repository_CreateOrder(t *testing.T) {
 				mock.ExpectPrepare(queryCreate).ExpectQuery().
 					WithArgs(args.req.UserID, args.req.TotalQty, args.req.TotalPrice).WillReturnError(errors.New("error"))
 			},
-			want:    0,
+			want:    nil,
 			wantErr: true,
 		},
 		{
@@ -73,9 +78,16 @@ func Test_repository_CreateOrder(t *testing.T) {
 			mock: func(args args) {
 				mock.ExpectPrepare(queryCreate).ExpectQuery().
 					WithArgs(args.req.UserID, args.req.TotalQty, args.req.TotalPrice).
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, order.StatusPending, createdAt))
+			},
+			want: &order.OrderModel{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 10000,
+				Status:     order.StatusPending,
+				CreatedAt:  createdAt,
 			},
-			want:    1,
 			wantErr: false,
 		},
 	}
@@ -88,7 +100,7 @@ func Test_repository_CreateOrder(t *testing.T) {
 				if tt.wantErr {
 					So(err, ShouldNotBeNil)
 				}
-				So(got, ShouldEqual, tt.want)
+				So(got, ShouldResemble, tt.want)
 			})
 		}
 	})
@@ -166,6 +178,26 @@ func Test_repository_BulkCreateOrderDetail(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "error when iterating rows",
+			args: args{
+				reqs: []order.OrderDetailModel{
+					{
+						OrderID: 1,
+						BookID:  1,
+						Qty:     1,
+						Price:   10000,
+					},
+				},
+			},
+			mock: func(args args) {
+				mock.ExpectPrepare(fmt.Sprintf(queryCreateDetail, "(?, ?, ?, ?)")).ExpectQuery().
+					WithArgs(args.reqs[0].OrderID, args.reqs[0].BookID, args.reqs[0].Qty, args.reqs[0].Price).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).RowError(0, errors.New("error")))
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "success",
 			args: args{
@@ -214,8 +246,15 @@ func Test_repository_GetAllOrder(t *testing.T) {
 	repo, mock, db := newMock()
 	defer db.Close()
 
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "total_quantity", "total_price", "status", "created_at", "updated_at"}
+
 	type args struct {
 		userID int64
+		filter order.OrderFilter
 	}
 	tests := []struct {
 		name    string
@@ -226,29 +265,32 @@ func Test_repository_GetAllOrder(t *testing.T) {
 	}{
 		{
 			name: "error when preparing query",
-			args: args{userID: 1},
+			args: args{userID: 1, filter: order.OrderFilter{Limit: 20}},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetAllOrder).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetAllOrder, "user_id = ? AND is_deleted = false")).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
 			name: "error when executing query",
-			args: args{userID: 1},
+			args: args{userID: 1, filter: order.OrderFilter{Limit: 20}},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetAllOrder).ExpectQuery().WithArgs(args.userID).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetAllOrder, "user_id = ? AND is_deleted = false")).ExpectQuery().
+					WithArgs(args.userID, 20).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
 			name: "success",
-			args: args{userID: 1},
+			args: args{userID: 1, filter: order.OrderFilter{Limit: 20}},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetAllOrder).ExpectQuery().WithArgs(args.userID).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_quantity", "total_price"}).
-						AddRow(1, 1, 1, 10000))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetAllOrder, "user_id = ? AND is_deleted = false")).ExpectQuery().
+					WithArgs(args.userID, 20).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, 1, 10000, order.StatusPaid, createdAt, updatedAt).
+						AddRow(2, 1, 2, 20000, order.StatusPending, createdAt, nil))
 			},
 			want: []order.OrderModel{
 				{
@@ -256,6 +298,47 @@ func Test_repository_GetAllOrder(t *testing.T) {
 					UserID:     1,
 					TotalQty:   1,
 					TotalPrice: 10000,
+					Status:     order.StatusPaid,
+					CreatedAt:  createdAt,
+					UpdatedAt:  sql.NullTime{Time: updatedAt, Valid: true},
+				},
+				{
+					ID:         2,
+					UserID:     1,
+					TotalQty:   2,
+					TotalPrice: 20000,
+					Status:     order.StatusPending,
+					CreatedAt:  createdAt,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success with filters and cursor",
+			args: args{userID: 1, filter: order.OrderFilter{
+				Status:      order.StatusPaid,
+				CreatedFrom: &from,
+				CreatedTo:   &to,
+				After:       &order.OrderCursor{ID: 5, CreatedAt: createdAt},
+				Limit:       10,
+			}},
+			mock: func(args args) {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetAllOrder,
+					"user_id = ? AND is_deleted = false AND status = ? AND created_at >= ? AND created_at < ? AND (created_at, id) < (?, ?)")).
+					ExpectQuery().
+					WithArgs(args.userID, order.StatusPaid, from, to, createdAt, int64(5), 10).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, 1, 10000, order.StatusPaid, createdAt, updatedAt))
+			},
+			want: []order.OrderModel{
+				{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 10000,
+					Status:     order.StatusPaid,
+					CreatedAt:  createdAt,
+					UpdatedAt:  sql.NullTime{Time: updatedAt, Valid: true},
 				},
 			},
 			wantErr: false,
@@ -266,7 +349,7 @@ func Test_repository_GetAllOrder(t *testing.T) {
 		for _, tt := range tests {
 			Convey(tt.name, func() {
 				tt.mock(tt.args)
-				got, err := repo.GetAllOrder(context.Background(), tt.args.userID)
+				got, err := repo.GetAllOrder(context.Background(), tt.args.userID, tt.args.filter)
 				if tt.wantErr {
 					So(err, ShouldNotBeNil)
 				}
@@ -293,27 +376,27 @@ func Test_repository_GetOrderDetail(t *testing.T) {
 	}{
 		{
 			name: "error when preparing query",
-			args: args{orderID: 1},
+			args: args{orderID: 1, userID: 1},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetOrderDetail).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetOrderDetail, "o.is_deleted = false AND od.is_deleted = false")).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
 			name: "error when executing query",
-			args: args{orderID: 1},
+			args: args{orderID: 1, userID: 1},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetOrderDetail).ExpectQuery().WithArgs(args.userID, args.orderID).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetOrderDetail, "o.is_deleted = false AND od.is_deleted = false")).ExpectQuery().WithArgs(args.orderID, args.userID).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
 		},
 		{
 			name: "success",
-			args: args{orderID: 1},
+			args: args{orderID: 1, userID: 1},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetOrderDetail).ExpectQuery().WithArgs(args.userID, args.orderID).
+				mock.ExpectPrepare(fmt.Sprintf(queryGetOrderDetail, "o.is_deleted = false AND od.is_deleted = false")).ExpectQuery().WithArgs(args.orderID, args.userID).
 					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "book_id", "quantity", "price"}).
 						AddRow(1, 1, 1, 1, 10000))
 			},
@@ -343,3 +426,444 @@ func Test_repository_GetOrderDetail(t *testing.T) {
 		}
 	})
 }
+
+func Test_repository_GetOrderDetailsByOrderIDs(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	query := fmt.Sprintf(queryGetOrderDetailsByOrderIDs, "od.is_deleted = false")
+	orderIDs := []int64{1, 2}
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []order.OrderDetailModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(pq.Array(orderIDs)).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(pq.Array(orderIDs)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "book_id", "quantity", "price", "book_title", "book_author"}).
+						AddRow(1, 1, 1, 1, 10000, "title 1", "author 1").
+						AddRow(2, 2, 2, 3, 30000, "title 2", "author 2"))
+			},
+			want: []order.OrderDetailModel{
+				{ID: 1, OrderID: 1, BookID: 1, Qty: 1, Price: 10000, BookTitle: "title 1", BookAuthor: "author 1"},
+				{ID: 2, OrderID: 2, BookID: 2, Qty: 3, Price: 30000, BookTitle: "title 2", BookAuthor: "author 2"},
+			},
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Order Repository - Get Order Details By Order IDs", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetOrderDetailsByOrderIDs(context.Background(), orderIDs)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_GetOrderWithDetails(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	query := fmt.Sprintf(queryGetOrderWithDetails, "od.is_deleted = false", "o.is_deleted = false")
+	columns := []string{"id", "user_id", "total_quantity", "total_price", "status", "created_at", "updated_at",
+		"detail_id", "book_id", "quantity", "price", "book_title", "book_author"}
+	header := order.OrderModel{ID: 1, UserID: 1, TotalQty: 3, TotalPrice: 30000, Status: order.StatusPaid, CreatedAt: createdAt}
+	tests := []struct {
+		name        string
+		mock        func()
+		want        *order.OrderModel
+		wantDetails []order.OrderDetailModel
+		wantErr     error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1), int64(1)).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "not found",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1), int64(1)).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success without lines",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1), int64(1)).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, 1, 3, 30000, order.StatusPaid, createdAt, nil, nil, nil, nil, nil, nil, nil))
+			},
+			want:        &header,
+			wantDetails: []order.OrderDetailModel{},
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1), int64(1)).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, 1, 3, 30000, order.StatusPaid, createdAt, nil, 1, 1, 1, 10000, "title 1", "author 1").
+					AddRow(1, 1, 3, 30000, order.StatusPaid, createdAt, nil, 2, 2, 2, 20000, "title 2", "author 2"))
+			},
+			want: &header,
+			wantDetails: []order.OrderDetailModel{
+				{ID: 1, OrderID: 1, BookID: 1, Qty: 1, Price: 10000, BookTitle: "title 1", BookAuthor: "author 1"},
+				{ID: 2, OrderID: 1, BookID: 2, Qty: 2, Price: 20000, BookTitle: "title 2", BookAuthor: "author 2"},
+			},
+		},
+	}
+
+	Convey("Test Order Repository - Get Order With Details", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, details, err := repo.GetOrderWithDetails(context.Background(), 1, 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+				}
+				So(got, ShouldResemble, tt.want)
+				So(details, ShouldResemble, tt.wantDetails)
+			})
+		}
+	})
+}
+
+func Test_repository_GetByID(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	query := fmt.Sprintf(queryGetByID, "is_deleted = false")
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *order.OrderModel
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(query).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "order not found",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(query).ExpectQuery().WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_quantity", "total_price", "status", "created_at", "updated_at"}).
+						AddRow(1, 2, 1, 10000, order.StatusPaid, createdAt, nil))
+			},
+			want: &order.OrderModel{
+				ID:         1,
+				UserID:     2,
+				TotalQty:   1,
+				TotalPrice: 10000,
+				Status:     order.StatusPaid,
+				CreatedAt:  createdAt,
+			},
+		},
+	}
+
+	Convey("Test Order Repository - Get By ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetByID(context.Background(), 1)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_UpdateStatus(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	updatedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectQuery().WithArgs(order.StatusPaid, int64(1), order.StatusPending).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "status changed concurrently",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectQuery().WithArgs(order.StatusPaid, int64(1), order.StatusPending).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectQuery().WithArgs(order.StatusPaid, int64(1), order.StatusPending).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+			},
+			want: updatedAt,
+		},
+	}
+
+	Convey("Test Order Repository - Update Status", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.UpdateStatus(context.Background(), 1, order.StatusPending, order.StatusPaid)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_CreateStatusHistory(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	req := order.OrderStatusHistoryModel{
+		OrderID:    1,
+		FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+		ToStatus:   order.StatusCancelled,
+		ActorID:    sql.NullInt64{Int64: 2, Valid: true},
+		Reason:     sql.NullString{String: "changed my mind", Valid: true},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *order.OrderStatusHistoryModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateStatusHistory).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateStatusHistory).ExpectQuery().
+					WithArgs(req.OrderID, req.FromStatus, req.ToStatus, req.ActorID, req.Reason).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryCreateStatusHistory).ExpectQuery().
+					WithArgs(req.OrderID, req.FromStatus, req.ToStatus, req.ActorID, req.Reason).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+			},
+			want: &order.OrderStatusHistoryModel{
+				ID:         1,
+				OrderID:    1,
+				FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+				ToStatus:   order.StatusCancelled,
+				ActorID:    sql.NullInt64{Int64: 2, Valid: true},
+				Reason:     sql.NullString{String: "changed my mind", Valid: true},
+				CreatedAt:  createdAt,
+			},
+		},
+	}
+
+	Convey("Test Order Repository - Create Status History", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.CreateStatusHistory(context.Background(), req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetStatusHistory(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	createdAt := time.Now()
+	columns := []string{"id", "order_id", "from_status", "to_status", "actor_id", "reason", "created_at"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []order.OrderStatusHistoryModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetStatusHistory).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetStatusHistory).ExpectQuery().WithArgs(int64(1)).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGetStatusHistory).ExpectQuery().WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, nil, order.StatusPending, 2, nil, createdAt).
+						AddRow(2, 1, order.StatusPending, order.StatusPaid, nil, nil, createdAt))
+			},
+			want: []order.OrderStatusHistoryModel{
+				{
+					ID:        1,
+					OrderID:   1,
+					ToStatus:  order.StatusPending,
+					ActorID:   sql.NullInt64{Int64: 2, Valid: true},
+					CreatedAt: createdAt,
+				},
+				{
+					ID:         2,
+					OrderID:    1,
+					FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+					ToStatus:   order.StatusPaid,
+					CreatedAt:  createdAt,
+				},
+			},
+		},
+	}
+
+	Convey("Test Order Repository - Get Status History", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetStatusHistory(context.Background(), 1)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_WithinTx(t *testing.T) {
+	repo, mock, mockDB := newMock()
+	defer mockDB.Close()
+
+	transactor := db.NewTransactor(sqlx.NewDb(mockDB, "sqlmock"))
+
+	Convey("Test Order Repository - Within Transaction", t, func() {
+		Convey("should run the queries in the transaction of the context", func() {
+			mock.ExpectBegin()
+			mock.ExpectPrepare(queryCreate).ExpectQuery().
+				WithArgs(int64(1), int64(1), int64(10000)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, order.StatusPending, time.Now()))
+			mock.ExpectPrepare(fmt.Sprintf(queryCreateDetail, "(?, ?, ?, ?)")).ExpectQuery().
+				WithArgs(int64(1), int64(1), int64(1), int64(10000)).
+				WillReturnError(errors.New("error"))
+			mock.ExpectRollback()
+
+			err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+				o, err := repo.CreateOrder(ctx, order.OrderModel{UserID: 1, TotalQty: 1, TotalPrice: 10000})
+				if err != nil {
+					return err
+				}
+
+				_, err = repo.BulkCreateOrderDetail(ctx, []order.OrderDetailModel{
+					{OrderID: o.ID, BookID: 1, Qty: 1, Price: 10000},
+				})
+				return err
+			})
+
+			So(err, ShouldNotBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package payment
+
+var (
+	queryCreate = `
+		INSERT INTO payments
+			(order_id, provider, charge_id, amount, currency, status)
+		VALUES
+			(?, ?, NULLIF(?, ''), ?, ?, ?)
+		RETURNING
+			id, created_at, updated_at
+	`
+
+	queryGetByChargeID = `
+		SELECT
+			id, order_id, provider, COALESCE(charge_id, '') AS charge_id, amount, currency, status, created_at, updated_at
+		FROM
+			payments
+		WHERE
+			provider = ?
+		AND
+			charge_id = ?
+	`
+
+	queryGetByOrderID = `
+		SELECT
+			id, order_id, provider, COALESCE(charge_id, '') AS charge_id, amount, currency, status, created_at, updated_at
+		FROM
+			payments
+		WHERE
+			order_id = ?
+		AND
+			status = ?
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	queryUpdateChargeID = `
+		UPDATE
+			payments
+		SET
+			charge_id = ?, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			charge_id IS NULL
+	`
+
+	queryUpdateStatus = `
+		UPDATE
+			payments
+		SET
+			status = ?, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			status = ?
+	`
+)
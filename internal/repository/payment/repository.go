@@ -0,0 +1,135 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+)
+
+type repository struct {
+	db db.Executor
+}
+
+func New(db db.Executor) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
+// Create records the payment. Its charge id can be left empty when the payment is reserved before
+// its charge is made, and set afterwards with UpdateChargeID.
+func (r *repository) Create(ctx context.Context, req payment.PaymentModel) (*payment.PaymentModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreate))
+	if err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.Create] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.OrderID, req.Provider, req.ChargeID, req.Amount, req.Currency, req.Status).
+		Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.Create] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+func (r *repository) GetByChargeID(ctx context.Context, provider, chargeID string) (*payment.PaymentModel, error) {
+	var res payment.PaymentModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetByChargeID))
+	if err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.GetByChargeID] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.GetContext(ctx, &res, provider, chargeID); err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.GetByChargeID] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// GetByOrderID returns the latest payment of the order with the given status
+func (r *repository) GetByOrderID(ctx context.Context, orderID int64, status string) (*payment.PaymentModel, error) {
+	var res payment.PaymentModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetByOrderID))
+	if err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.GetByOrderID] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.GetContext(ctx, &res, orderID, status); err != nil {
+		return nil, fmt.Errorf("[PaymentRepo.GetByOrderID] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// UpdateChargeID sets the charge made for a payment reserved without one. It returns sql.ErrNoRows
+// when the payment already has a charge.
+func (r *repository) UpdateChargeID(ctx context.Context, id int64, chargeID string) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryUpdateChargeID))
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateChargeID] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, chargeID, id)
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateChargeID] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateChargeID] failed to get affected rows: %w", db.Translate(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("[PaymentRepo.UpdateChargeID] payment %d already has a charge: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// UpdateStatus moves the payment from one status to another. It returns sql.ErrNoRows when the
+// payment is no longer in the from status, so a notification delivered twice is only applied once.
+func (r *repository) UpdateStatus(ctx context.Context, id int64, from, to string) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryUpdateStatus))
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateStatus] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, to, id, from)
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateStatus] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[PaymentRepo.UpdateStatus] failed to get affected rows: %w", db.Translate(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("[PaymentRepo.UpdateStatus] payment %d is not %s: %w", id, from, sql.ErrNoRows)
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/jmoiron/sqlx"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var paymentColumns = []string{"id", "order_id", "provider", "charge_id", "amount", "currency", "status", "created_at", "updated_at"}
+
+func newMock() (*repository, sqlmock.Sqlmock, *sql.DB) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	return New(sqlx.NewDb(db, "sqlmock")), mock, db
+}
+
+func Test_repository_Create(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	now := time.Now()
+	req := payment.PaymentModel{
+		OrderID:  1,
+		Provider: "fake",
+		ChargeID: "ch_fake_1",
+		Amount:   10000,
+		Currency: "IDR",
+		Status:   payment.StatusPending,
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *payment.PaymentModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectQuery().
+					WithArgs(int64(1), "fake", "ch_fake_1", int64(10000), "IDR", payment.StatusPending).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryCreate).ExpectQuery().
+					WithArgs(int64(1), "fake", "ch_fake_1", int64(10000), "IDR", payment.StatusPending).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, now, now))
+			},
+			want: &payment.PaymentModel{
+				ID:        1,
+				OrderID:   1,
+				Provider:  "fake",
+				ChargeID:  "ch_fake_1",
+				Amount:    10000,
+				Currency:  "IDR",
+				Status:    payment.StatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	Convey("Test Create Payment", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.Create(context.Background(), req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetByChargeID(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *payment.PaymentModel
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByChargeID).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "payment not found",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByChargeID).ExpectQuery().WithArgs("fake", "ch_fake_1").WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByChargeID).ExpectQuery().WithArgs("fake", "ch_fake_1").
+					WillReturnRows(sqlmock.NewRows(paymentColumns).
+						AddRow(1, 1, "fake", "ch_fake_1", 10000, "IDR", payment.StatusPending, now, now))
+			},
+			want: &payment.PaymentModel{
+				ID:        1,
+				OrderID:   1,
+				Provider:  "fake",
+				ChargeID:  "ch_fake_1",
+				Amount:    10000,
+				Currency:  "IDR",
+				Status:    payment.StatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	Convey("Test Get Payment By Charge ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetByChargeID(context.Background(), "fake", "ch_fake_1")
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetByOrderID(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *payment.PaymentModel
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByOrderID).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "payment not found",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByOrderID).ExpectQuery().WithArgs(int64(1), payment.StatusSucceeded).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGetByOrderID).ExpectQuery().WithArgs(int64(1), payment.StatusSucceeded).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).
+						AddRow(1, 1, "fake", "ch_fake_1", 10000, "IDR", payment.StatusSucceeded, now, now))
+			},
+			want: &payment.PaymentModel{
+				ID:        1,
+				OrderID:   1,
+				Provider:  "fake",
+				ChargeID:  "ch_fake_1",
+				Amount:    10000,
+				Currency:  "IDR",
+				Status:    payment.StatusSucceeded,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+
+	Convey("Test Get Payment By Order ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetByOrderID(context.Background(), 1, payment.StatusSucceeded)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_UpdateChargeID(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateChargeID).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateChargeID).ExpectExec().
+					WithArgs("ch_fake_1", int64(1)).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "payment already has a charge",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateChargeID).ExpectExec().
+					WithArgs("ch_fake_1", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateChargeID).ExpectExec().
+					WithArgs("ch_fake_1", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	Convey("Test Update Payment Charge ID", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.UpdateChargeID(context.Background(), 1, "ch_fake_1")
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_UpdateStatus(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectExec().
+					WithArgs(payment.StatusSucceeded, int64(1), payment.StatusPending).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "payment is no longer pending",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectExec().
+					WithArgs(payment.StatusSucceeded, int64(1), payment.StatusPending).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryUpdateStatus).ExpectExec().
+					WithArgs(payment.StatusSucceeded, int64(1), payment.StatusPending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	Convey("Test Update Payment Status", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := repo.UpdateStatus(context.Background(), 1, payment.StatusPending, payment.StatusSucceeded)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
@@ -17,5 +17,134 @@ var (
 			users
 		WHERE 
 			email = ?
+		AND
+			%s
+	`
+
+	queryCreateRefreshToken = `
+		INSERT INTO refresh_tokens
+			(user_id, family_id, token_id, expires_at)
+		VALUES
+			(?, ?, ?, ?)
+	`
+
+	queryGetRefreshToken = `
+		SELECT
+			rt.id, rt.user_id, rt.family_id, rt.token_id, rt.expires_at, rt.is_used, rt.is_revoked
+		FROM
+			refresh_tokens rt
+		JOIN
+			users u
+		ON
+			rt.user_id = u.id
+		WHERE
+			rt.token_id = ?
+		AND
+			%s
+	`
+
+	queryUseRefreshToken = `
+		UPDATE
+			refresh_tokens
+		SET
+			is_used = true, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			token_id = ?
+		AND
+			is_used = false
+		AND
+			is_revoked = false
+	`
+
+	queryRevokeRefreshTokenFamily = `
+		UPDATE
+			refresh_tokens
+		SET
+			is_revoked = true, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			family_id = ?
+	`
+
+	queryRevokeUserRefreshTokens = `
+		UPDATE
+			refresh_tokens
+		SET
+			is_revoked = true, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			user_id = ?
+		AND
+			is_revoked = false
+	`
+
+	queryCreateRevokedToken = `
+		INSERT INTO revoked_tokens
+			(user_id, token_id, expires_at)
+		VALUES
+			(?, ?, ?)
+		ON CONFLICT
+			(token_id)
+		DO UPDATE SET
+			updated_at = TIMEZONE('UTC', NOW())
+		RETURNING
+			id, created_at
+	`
+
+	queryGetRevokedTokens = `
+		SELECT
+			id, user_id, token_id, expires_at, created_at
+		FROM
+			revoked_tokens
+		WHERE
+			expires_at > TIMEZONE('UTC', NOW())
+	`
+
+	queryGetPermissions = `
+		SELECT
+			r.name AS role, p.name AS permission
+		FROM
+			user_roles ur
+		JOIN
+			roles r
+		ON
+			ur.role_id = r.id
+		LEFT JOIN
+			role_permissions rp
+		ON
+			rp.role_id = r.id
+		LEFT JOIN
+			permissions p
+		ON
+			rp.permission_id = p.id
+		AND
+			p.is_deleted = false
+		WHERE
+			ur.user_id = ?
+		AND
+			r.is_deleted = false
+	`
+
+	queryAssignRole = `
+		INSERT INTO user_roles
+			(user_id, role_id)
+		SELECT
+			?, id
+		FROM
+			roles
+		WHERE
+			name = ?
+		ON CONFLICT
+			(user_id, role_id)
+		DO NOTHING
+	`
+
+	queryRestore = `
+		UPDATE
+			users
+		SET
+			is_deleted = false, updated_at = TIMEZONE('UTC', NOW())
+		WHERE
+			id = ?
+		AND
+			is_deleted = true
 	`
 )
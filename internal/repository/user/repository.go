@@ -2,26 +2,32 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
-	"github.com/jmoiron/sqlx"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/softdelete"
 )
 
 type repository struct {
-	db *sqlx.DB
+	db db.Executor
 }
 
-func New(db *sqlx.DB) *repository {
+func New(db db.Executor) *repository {
 	return &repository{
 		db: db,
 	}
 }
 
+func (r *repository) conn(ctx context.Context) db.Executor {
+	return db.Conn(ctx, r.db)
+}
+
 func (r *repository) Create(ctx context.Context, req user.UserModel) (*user.UserModel, error) {
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryCreate))
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreate))
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo.Create] failed to prepare query: %v", err)
+		return nil, fmt.Errorf("[UserRepo.Create] failed to prepare query: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = stmt.Close()
@@ -29,7 +35,7 @@ func (r *repository) Create(ctx context.Context, req user.UserModel) (*user.User
 
 	rows, err := stmt.QueryContext(ctx, req.Email, req.Password, req.Name)
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo.Create] failed to execute query: %v", err)
+		return nil, fmt.Errorf("[UserRepo.Create] failed to execute query: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = rows.Close()
@@ -37,7 +43,7 @@ func (r *repository) Create(ctx context.Context, req user.UserModel) (*user.User
 
 	for rows.Next() {
 		if err := rows.Scan(&req.ID); err != nil {
-			return nil, fmt.Errorf("[UserRepo.Create] failed to scan row: %v", err)
+			return nil, fmt.Errorf("[UserRepo.Create] failed to scan row: %w", db.Translate(err))
 		}
 	}
 
@@ -47,9 +53,9 @@ func (r *repository) Create(ctx context.Context, req user.UserModel) (*user.User
 func (r *repository) GetByEmail(ctx context.Context, email string) (*user.UserModel, error) {
 	var res user.UserModel
 
-	stmt, err := r.db.PreparexContext(ctx, r.db.Rebind(queryGetByEmail))
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetByEmail, softdelete.Scope(ctx, "is_deleted"))))
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo.GetUserByEmail] failed to prepare query: %v", err)
+		return nil, fmt.Errorf("[UserRepo.GetUserByEmail] failed to prepare query: %w", db.Translate(err))
 	}
 	defer func() {
 		_ = stmt.Close()
@@ -57,8 +63,199 @@ func (r *repository) GetByEmail(ctx context.Context, email string) (*user.UserMo
 
 	err = stmt.GetContext(ctx, &res, email)
 	if err != nil {
-		return nil, fmt.Errorf("[UserRepo.GetUserByEmail] failed to execute query: %w", err)
+		return nil, fmt.Errorf("[UserRepo.GetUserByEmail] failed to execute query: %w", db.Translate(err))
 	}
 
 	return &res, nil
 }
+
+func (r *repository) CreateRefreshToken(ctx context.Context, req user.RefreshTokenModel) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreateRefreshToken))
+	if err != nil {
+		return fmt.Errorf("[UserRepo.CreateRefreshToken] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	_, err = stmt.ExecContext(ctx, req.UserID, req.FamilyID, req.TokenID, req.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[UserRepo.CreateRefreshToken] failed to execute query: %w", db.Translate(err))
+	}
+
+	return nil
+}
+
+func (r *repository) GetRefreshToken(ctx context.Context, tokenID string) (*user.RefreshTokenModel, error) {
+	var res user.RefreshTokenModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(fmt.Sprintf(queryGetRefreshToken, softdelete.Scope(ctx, "u.is_deleted"))))
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetRefreshToken] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.GetContext(ctx, &res, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetRefreshToken] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &res, nil
+}
+
+// UseRefreshToken marks the refresh token as used. It returns false when the token
+// was already used or revoked, so concurrent refreshes can only consume it once.
+func (r *repository) UseRefreshToken(ctx context.Context, tokenID string) (bool, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryUseRefreshToken))
+	if err != nil {
+		return false, fmt.Errorf("[UserRepo.UseRefreshToken] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("[UserRepo.UseRefreshToken] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[UserRepo.UseRefreshToken] failed to get affected rows: %w", db.Translate(err))
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryRevokeRefreshTokenFamily))
+	if err != nil {
+		return fmt.Errorf("[UserRepo.RevokeRefreshTokenFamily] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	_, err = stmt.ExecContext(ctx, familyID)
+	if err != nil {
+		return fmt.Errorf("[UserRepo.RevokeRefreshTokenFamily] failed to execute query: %w", db.Translate(err))
+	}
+
+	return nil
+}
+
+func (r *repository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryRevokeUserRefreshTokens))
+	if err != nil {
+		return fmt.Errorf("[UserRepo.RevokeUserRefreshTokens] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	_, err = stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[UserRepo.RevokeUserRefreshTokens] failed to execute query: %w", db.Translate(err))
+	}
+
+	return nil
+}
+
+func (r *repository) CreateRevokedToken(ctx context.Context, req user.RevokedTokenModel) (*user.RevokedTokenModel, error) {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryCreateRevokedToken))
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.CreateRevokedToken] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	err = stmt.QueryRowxContext(ctx, req.UserID, req.TokenID, req.ExpiresAt).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.CreateRevokedToken] failed to execute query: %w", db.Translate(err))
+	}
+
+	return &req, nil
+}
+
+func (r *repository) GetRevokedTokens(ctx context.Context) ([]user.RevokedTokenModel, error) {
+	var res []user.RevokedTokenModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetRevokedTokens))
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetRevokedTokens] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res); err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetRevokedTokens] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+func (r *repository) GetPermissions(ctx context.Context, userID int64) ([]user.UserPermissionModel, error) {
+	var res []user.UserPermissionModel
+
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryGetPermissions))
+	if err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetPermissions] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	if err := stmt.SelectContext(ctx, &res, userID); err != nil {
+		return nil, fmt.Errorf("[UserRepo.GetPermissions] failed to execute query: %w", db.Translate(err))
+	}
+
+	return res, nil
+}
+
+func (r *repository) AssignRole(ctx context.Context, userID int64, role string) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryAssignRole))
+	if err != nil {
+		return fmt.Errorf("[UserRepo.AssignRole] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	_, err = stmt.ExecContext(ctx, userID, role)
+	if err != nil {
+		return fmt.Errorf("[UserRepo.AssignRole] failed to execute query: %w", db.Translate(err))
+	}
+
+	return nil
+}
+
+// Restore undoes the soft deletion of a user. It returns sql.ErrNoRows when the user
+// does not exist or is not deleted.
+func (r *repository) Restore(ctx context.Context, userID int64) error {
+	stmt, err := r.conn(ctx).PreparexContext(ctx, r.db.Rebind(queryRestore))
+	if err != nil {
+		return fmt.Errorf("[UserRepo.Restore] failed to prepare query: %w", db.Translate(err))
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	result, err := stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[UserRepo.Restore] failed to execute query: %w", db.Translate(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[UserRepo.Restore] failed to get affected rows: %w", db.Translate(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("[UserRepo.Restore] user %d not found: %w", userID, sql.ErrNoRows)
+	}
+
+	return nil
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	pkgdb "github.com/erizkiatama/gotu-assignment/internal/pkg/db"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -102,6 +106,17 @@ func Test_repository_Create(t *testing.T) {
 				So(got, ShouldEqual, tt.want)
 			})
 		}
+
+		Convey("email already exists", func() {
+			req := user.UserModel{Email: "test@testing.com", Password: "password", Name: "test"}
+			mock.ExpectPrepare(queryCreate).ExpectQuery().WithArgs(req.Email, req.Password, req.Name).
+				WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_unique_idx"})
+
+			got, err := repo.Create(context.Background(), req)
+			So(got, ShouldBeNil)
+			So(errors.Is(err, pkgdb.ErrUniqueViolation), ShouldBeTrue)
+			So(pkgdb.Constraint(err), ShouldEqual, "users_email_unique_idx")
+		})
 	})
 }
 
@@ -123,7 +138,7 @@ func Test_repository_GetByEmail(t *testing.T) {
 			name: "error when preparing query",
 			args: args{email: "test@testing.com"},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetByEmail).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByEmail, "is_deleted = false")).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -132,7 +147,7 @@ func Test_repository_GetByEmail(t *testing.T) {
 			name: "error when executing query",
 			args: args{email: "test@testing.com"},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetByEmail).ExpectQuery().WithArgs(args.email).WillReturnError(errors.New("error"))
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByEmail, "is_deleted = false")).ExpectQuery().WithArgs(args.email).WillReturnError(errors.New("error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -141,7 +156,7 @@ func Test_repository_GetByEmail(t *testing.T) {
 			name: "success",
 			args: args{email: "test@testing.com"},
 			mock: func(args args) {
-				mock.ExpectPrepare(queryGetByEmail).ExpectQuery().WithArgs(args.email).WillReturnRows(
+				mock.ExpectPrepare(fmt.Sprintf(queryGetByEmail, "is_deleted = false")).ExpectQuery().WithArgs(args.email).WillReturnRows(
 					sqlmock.NewRows([]string{"id", "email", "password", "name"}).AddRow(1, "test@testing.com", "password", "test"),
 				)
 			},
@@ -168,3 +183,653 @@ func Test_repository_GetByEmail(t *testing.T) {
 		}
 	})
 }
+
+func Test_repository_CreateRefreshToken(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		req user.RefreshTokenModel
+	}
+	req := user.RefreshTokenModel{
+		UserID:    1,
+		FamilyID:  "family-id",
+		TokenID:   "token-id",
+		ExpiresAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRefreshToken).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRefreshToken).ExpectExec().
+					WithArgs(args.req.UserID, args.req.FamilyID, args.req.TokenID, args.req.ExpiresAt).
+					WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRefreshToken).ExpectExec().
+					WithArgs(args.req.UserID, args.req.FamilyID, args.req.TokenID, args.req.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Create Refresh Token", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := repo.CreateRefreshToken(context.Background(), tt.args.req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_GetRefreshToken(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		tokenID string
+	}
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		want    *user.RefreshTokenModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetRefreshToken, "u.is_deleted = false")).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetRefreshToken, "u.is_deleted = false")).ExpectQuery().WithArgs(args.tokenID).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(fmt.Sprintf(queryGetRefreshToken, "u.is_deleted = false")).ExpectQuery().WithArgs(args.tokenID).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_id", "expires_at", "is_used", "is_revoked"}).
+						AddRow(1, 1, "family-id", "token-id", expiresAt, false, false),
+				)
+			},
+			want: &user.RefreshTokenModel{
+				ID:        1,
+				UserID:    1,
+				FamilyID:  "family-id",
+				TokenID:   "token-id",
+				ExpiresAt: expiresAt,
+			},
+		},
+	}
+
+	Convey("Test Get Refresh Token", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := repo.GetRefreshToken(context.Background(), tt.args.tokenID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_UseRefreshToken(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		tokenID string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryUseRefreshToken).WillReturnError(errors.New("error"))
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryUseRefreshToken).ExpectExec().WithArgs(args.tokenID).WillReturnError(errors.New("error"))
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "error when getting affected rows",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryUseRefreshToken).ExpectExec().WithArgs(args.tokenID).
+					WillReturnResult(sqlmock.NewErrorResult(errors.New("error")))
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "token already used",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryUseRefreshToken).ExpectExec().WithArgs(args.tokenID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "success",
+			args: args{tokenID: "token-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryUseRefreshToken).ExpectExec().WithArgs(args.tokenID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Use Refresh Token", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := repo.UseRefreshToken(context.Background(), tt.args.tokenID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+
+				So(got, ShouldEqual, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_RevokeRefreshTokenFamily(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		familyID string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{familyID: "family-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeRefreshTokenFamily).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{familyID: "family-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeRefreshTokenFamily).ExpectExec().WithArgs(args.familyID).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{familyID: "family-id"},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeRefreshTokenFamily).ExpectExec().WithArgs(args.familyID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Revoke Refresh Token Family", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := repo.RevokeRefreshTokenFamily(context.Background(), tt.args.familyID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_RevokeUserRefreshTokens(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeUserRefreshTokens).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeUserRefreshTokens).ExpectExec().WithArgs(args.userID).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRevokeUserRefreshTokens).ExpectExec().WithArgs(args.userID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Revoke User Refresh Tokens", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := repo.RevokeUserRefreshTokens(context.Background(), tt.args.userID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_CreateRevokedToken(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		req user.RevokedTokenModel
+	}
+	expiresAt := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := user.RevokedTokenModel{
+		UserID:    1,
+		TokenID:   sql.NullString{String: "token-id", Valid: true},
+		ExpiresAt: expiresAt,
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		want    *user.RevokedTokenModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRevokedToken).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRevokedToken).ExpectQuery().
+					WithArgs(args.req.UserID, args.req.TokenID, args.req.ExpiresAt).
+					WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{req: req},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryCreateRevokedToken).ExpectQuery().
+					WithArgs(args.req.UserID, args.req.TokenID, args.req.ExpiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+			},
+			want: &user.RevokedTokenModel{
+				ID:        1,
+				UserID:    1,
+				TokenID:   sql.NullString{String: "token-id", Valid: true},
+				ExpiresAt: expiresAt,
+				CreatedAt: createdAt,
+			},
+		},
+	}
+
+	Convey("Test Create Revoked Token", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := repo.CreateRevokedToken(context.Background(), tt.args.req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_GetRevokedTokens(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	expiresAt := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []user.RevokedTokenModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetRevokedTokens).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			mock: func() {
+				mock.ExpectPrepare(queryGetRevokedTokens).ExpectQuery().WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "success",
+			mock: func() {
+				mock.ExpectPrepare(queryGetRevokedTokens).ExpectQuery().WillReturnRows(
+					sqlmock.NewRows([]string{"id", "user_id", "token_id", "expires_at", "created_at"}).
+						AddRow(1, 1, "token-id", expiresAt, createdAt).
+						AddRow(2, 2, nil, expiresAt, createdAt),
+				)
+			},
+			want: []user.RevokedTokenModel{
+				{
+					ID:        1,
+					UserID:    1,
+					TokenID:   sql.NullString{String: "token-id", Valid: true},
+					ExpiresAt: expiresAt,
+					CreatedAt: createdAt,
+				},
+				{
+					ID:        2,
+					UserID:    2,
+					ExpiresAt: expiresAt,
+					CreatedAt: createdAt,
+				},
+			},
+		},
+	}
+
+	Convey("Test Get Revoked Tokens", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := repo.GetRevokedTokens(context.Background())
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_GetPermissions(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		want    []user.UserPermissionModel
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryGetPermissions).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryGetPermissions).ExpectQuery().WithArgs(args.userID).WillReturnError(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryGetPermissions).ExpectQuery().WithArgs(args.userID).WillReturnRows(
+					sqlmock.NewRows([]string{"role", "permission"}).
+						AddRow("admin", "book:write").
+						AddRow("support", nil),
+				)
+			},
+			want: []user.UserPermissionModel{
+				{Role: "admin", Permission: sql.NullString{String: "book:write", Valid: true}},
+				{Role: "support"},
+			},
+		},
+	}
+
+	Convey("Test Get Permissions", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := repo.GetPermissions(context.Background(), tt.args.userID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				}
+
+				So(got, ShouldResemble, tt.want)
+			})
+		}
+	})
+}
+
+func Test_repository_AssignRole(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		userID int64
+		role   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "error when preparing query",
+			args: args{userID: 1, role: user.RoleAdmin},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryAssignRole).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error when executing query",
+			args: args{userID: 1, role: user.RoleAdmin},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryAssignRole).ExpectExec().WithArgs(args.userID, args.role).WillReturnError(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{userID: 1, role: user.RoleAdmin},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryAssignRole).ExpectExec().WithArgs(args.userID, args.role).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+	}
+
+	Convey("Test Assign Role", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := repo.AssignRole(context.Background(), tt.args.userID, tt.args.role)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_repository_Restore(t *testing.T) {
+	repo, mock, db := newMock()
+	defer db.Close()
+
+	type args struct {
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr error
+	}{
+		{
+			name: "error when preparing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRestore).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "error when executing query",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRestore).ExpectExec().WithArgs(args.userID).WillReturnError(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+		{
+			name: "user not found",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRestore).ExpectExec().WithArgs(args.userID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "success",
+			args: args{userID: 1},
+			mock: func(args args) {
+				mock.ExpectPrepare(queryRestore).ExpectExec().WithArgs(args.userID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	Convey("Test Restore", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := repo.Restore(context.Background(), tt.args.userID)
+				if tt.wantErr != nil {
+					So(err, ShouldNotBeNil)
+					if errors.Is(tt.wantErr, sql.ErrNoRows) {
+						So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+					}
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
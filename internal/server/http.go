@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,23 +12,51 @@ import (
 	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/api/book"
+	"github.com/erizkiatama/gotu-assignment/internal/api/inventory"
 	"github.com/erizkiatama/gotu-assignment/internal/api/order"
+	"github.com/erizkiatama/gotu-assignment/internal/api/payment"
 	"github.com/erizkiatama/gotu-assignment/internal/api/user"
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/middleware"
+	userModel "github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/denylist"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/logger"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
-	router       *gin.Engine
-	UserHandler  *user.Handler
-	BookHandler  *book.Handler
-	OrderHandler *order.Handler
+	router           *gin.Engine
+	UserHandler      *user.Handler
+	BookHandler      *book.Handler
+	OrderHandler     *order.Handler
+	InventoryHandler *inventory.Handler
+	PaymentHandler   *payment.Handler
+	TokenDenylist    *denylist.Cache
+	Idempotency      gin.HandlerFunc
+	// Logger logs the requests, the default logger is used when it is nil
+	Logger *slog.Logger
 }
 
 func (s *Server) registerRoutes() {
-	s.router = gin.Default()
+	base := s.Logger
+	if base == nil {
+		base = slog.Default()
+	}
+
+	validation.Register()
+	s.router = gin.New()
+	// The recovery runs inside the request id and logger, so a panicking request is logged with its id
+	s.router.Use(
+		middleware.RequestID(),
+		middleware.Logger(base),
+		gin.CustomRecoveryWithWriter(io.Discard, recovery),
+	)
 
 	v1 := s.router.Group("/api/v1")
+	authorize := middleware.AuthorizeToken(s.TokenDenylist)
 
 	// Register health handler
 	s.router.GET("/health", func(c *gin.Context) {
@@ -35,20 +65,55 @@ func (s *Server) registerRoutes() {
 		})
 	})
 
+	// Register public keys to verify the issued tokens
+	s.router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(200, jwt.JWKS())
+	})
+
 	// Register user handler
 	userGroup := v1.Group("/user")
 	userGroup.POST("/register", s.UserHandler.Register)
 	userGroup.POST("/login", s.UserHandler.Login)
+	userGroup.POST("/refresh", s.UserHandler.Refresh)
+	userGroup.POST("/logout", authorize, s.UserHandler.Logout)
+	userGroup.POST("/logout-all", authorize, s.UserHandler.LogoutAll)
+	userGroup.POST("/:user_id/restore", authorize, middleware.RequirePermission(userModel.PermissionUserWrite), s.UserHandler.Restore)
 
 	// Register book handler
 	bookGroup := v1.Group("/book")
 	bookGroup.GET("/", s.BookHandler.List)
+	bookGroup.GET("/search", s.BookHandler.Search)
+	bookGroup.GET("/:book_id", s.BookHandler.Detail)
+
+	canWriteBook := middleware.RequirePermission(userModel.PermissionBookWrite)
+	bookGroup.POST("/", authorize, canWriteBook, s.Idempotency, s.BookHandler.Create)
+	bookGroup.PUT("/:book_id", authorize, canWriteBook, middleware.IncludeDeleted(), s.BookHandler.Update)
+	bookGroup.PATCH("/:book_id", authorize, canWriteBook, middleware.IncludeDeleted(), s.BookHandler.Patch)
+	bookGroup.DELETE("/:book_id", authorize, canWriteBook, s.BookHandler.Delete)
+	bookGroup.POST("/:book_id/restore", authorize, canWriteBook, s.BookHandler.Restore)
+
+	// Register admin handler
+	adminGroup := v1.Group("/admin", authorize)
+	adminGroup.GET("/book", canWriteBook, middleware.IncludeDeleted(), s.BookHandler.List)
+	adminGroup.GET("/book/:book_id/stock", canWriteBook, s.InventoryHandler.GetStock)
+	adminGroup.POST("/book/:book_id/stock", canWriteBook, s.Idempotency, s.InventoryHandler.AdjustStock)
+
+	canWriteOrder := middleware.RequirePermission(userModel.PermissionOrderWrite)
+	adminGroup.POST("/order/:order_id/status", canWriteOrder, s.OrderHandler.UpdateStatus)
+	adminGroup.POST("/order/:order_id/refund", canWriteOrder, s.Idempotency, s.PaymentHandler.RefundPayment)
 
 	// Register order handler
 	orderGroup := v1.Group("/order")
-	orderGroup.POST("/", middleware.AuthorizeToken(), s.OrderHandler.CreateOrder)
-	orderGroup.GET("/", middleware.AuthorizeToken(), s.OrderHandler.ListOrder)
-	orderGroup.GET("/:order_id", middleware.AuthorizeToken(), s.OrderHandler.DetailOrder)
+	orderGroup.POST("/", authorize, s.Idempotency, s.OrderHandler.CreateOrder)
+	orderGroup.GET("/", authorize, s.OrderHandler.ListOrder)
+	orderGroup.GET("/:order_id", authorize, s.OrderHandler.DetailOrder)
+	orderGroup.POST("/:order_id/cancel", authorize, s.Idempotency, s.OrderHandler.CancelOrder)
+	orderGroup.POST("/:order_id/payment", authorize, s.Idempotency, s.PaymentHandler.CreatePayment)
+
+	// Register payment handler, webhooks are authenticated by their signature
+	paymentGroup := v1.Group("/payment")
+	paymentGroup.POST("/webhook", s.PaymentHandler.Webhook)
 }
 
 func (s *Server) Run(port string, timeout int64) error {
@@ -62,11 +127,10 @@ func (s *Server) Run(port string, timeout int64) error {
 	go gracefulShutdown(server, timeout)
 
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("error starting server: ", err)
-		return err
+		return fmt.Errorf("error starting server: %w", err)
 	}
 
-	log.Println("server stopped")
+	slog.Info("server stopped")
 	return nil
 }
 
@@ -75,12 +139,20 @@ func gracefulShutdown(server *http.Server, timeout int64) {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
-	log.Println("shutting down server...")
+	slog.Info("shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Millisecond)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("server forced to shutdown: ", err)
+		slog.Error("server forced to shutdown", "error", err)
+		os.Exit(1)
 	}
 }
+
+// recovery logs a panic of a handler with the attributes of the request and responds with an internal server error
+func recovery(c *gin.Context, err any) {
+	logger.FromContext(c.Request.Context()).Error("panic recovered", "error", err)
+	helpers.ErrorResponse(c, http.StatusInternalServerError, constant.CodeInternalServer, constant.ErrorInternalServer, nil)
+	c.Abort()
+}
@@ -2,16 +2,29 @@ package book
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/pagination"
 )
 
 //go:generate mockgen -source=service.go -package=book -destination=service_mock_test.go
 type bookReposistory interface {
-	GetAll(ctx context.Context) (book.BookModels, error)
+	List(ctx context.Context, filter book.BookFilter) (book.BookModels, error)
+	GetByID(ctx context.Context, id int64) (*book.BookModel, error)
+	Create(ctx context.Context, req book.BookModel) (*book.BookModel, error)
+	Update(ctx context.Context, req book.BookModel) (*book.BookModel, error)
+	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*book.BookModel, error)
+	Search(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error)
+	SearchSimilar(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error)
 }
 
 type service struct {
@@ -24,26 +37,359 @@ func New(bookRepo bookReposistory) *service {
 	}
 }
 
-func (s *service) List(ctx context.Context) (book.BookResponses, error) {
-	books, err := s.bookRepo.GetAll(ctx)
+// List returns a page of books. The next page is requested with the cursor of the returned pagination,
+// which is only valid together with the same sort and order.
+func (s *service) List(ctx context.Context, req book.ListBookRequest) (book.BookResponses, *response.Pagination, error) {
+	filter, err := toBookFilter(req)
 	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch one more book than requested to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	books, err := s.bookRepo.List(ctx, filter)
+	if err != nil {
+		return nil, nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeListBooksFailed,
+			Msg:       constant.ErrorListBooksFailed,
+			Err:       err,
+		}
+	}
+
+	pg := &response.Pagination{
+		Limit:   limit,
+		HasMore: len(books) > limit,
+	}
+	if pg.HasMore {
+		books = books[:limit]
+
+		last := books[len(books)-1]
+		pg.NextCursor, err = pagination.EncodeCursor(book.BookCursor{
+			ID:        last.ID,
+			Sort:      filter.Sort,
+			Desc:      filter.Desc,
+			Price:     last.Price,
+			Title:     last.Title,
+			CreatedAt: last.CreatedAt,
+		})
+		if err != nil {
+			return nil, nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeListBooksFailed,
+				Msg:       constant.ErrorListBooksFailed,
+				Err:       err,
+			}
+		}
+	}
+
+	res := make(book.BookResponses, len(books))
+	for i, b := range books {
+		res[i] = toBookResponse(b)
+	}
+
+	return res, pg, nil
+}
+
+// Detail returns the book with the given id together with the time it was last modified
+func (s *service) Detail(ctx context.Context, id int64) (*book.BookResponse, time.Time, error) {
+	b, err := s.bookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, time.Time{}, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return nil, time.Time{}, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetBookFailed,
+			Msg:       constant.ErrorGetBookFailed,
+			Err:       err,
+		}
+	}
+
+	lastModified := b.CreatedAt
+	if b.UpdatedAt.Valid {
+		lastModified = b.UpdatedAt.Time
+	}
+
+	res := toBookResponse(*b)
+	return &res, lastModified, nil
+}
+
+// Search looks the books up by title, author and description. When the full-text search finds nothing,
+// e.g. because of a typo, it falls back to the books with a title or author similar to the query.
+func (s *service) Search(ctx context.Context, req book.SearchBookRequest) (book.BookResponses, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorListBooksFailed,
-			Err:  err,
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeEmptySearchQuery,
+			Msg:       constant.ErrorEmptySearchQuery,
+			Err:       errors.New("[BookSvc.Search] search query is empty"),
+		}
+	}
+	limit := pagination.Limit(req.Limit)
+
+	books, err := s.bookRepo.Search(ctx, query, limit)
+	if err == nil && len(books) == 0 {
+		books, err = s.bookRepo.SearchSimilar(ctx, query, limit)
+	}
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeSearchBooksFailed,
+			Msg:       constant.ErrorSearchBooksFailed,
+			Err:       err,
 		}
 	}
 
 	res := make(book.BookResponses, len(books))
 	for i, b := range books {
-		res[i] = book.BookResponse{
-			ID:          b.ID,
-			Title:       b.Title,
-			Author:      b.Author,
-			Description: b.Description.String,
-			Price:       b.Price,
+		res[i] = toBookResponse(b.BookModel)
+		if b.TitleSnippet.Valid || b.DescriptionSnippet.Valid {
+			res[i].Highlight = &book.BookHighlight{
+				Title:       b.TitleSnippet.String,
+				Description: b.DescriptionSnippet.String,
+			}
 		}
 	}
 
 	return res, nil
 }
+
+func (s *service) Create(ctx context.Context, req book.BookRequest) (*book.BookResponse, error) {
+	model := book.BookModel{
+		Title:       req.Title,
+		Author:      req.Author,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Price:       req.Price,
+	}
+	if err := validateBook(model); err != nil {
+		return nil, err
+	}
+
+	created, err := s.bookRepo.Create(ctx, model)
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreateBookFailed,
+			Msg:       constant.ErrorCreateBookFailed,
+			Err:       err,
+		}
+	}
+
+	res := toBookResponse(*created)
+	return &res, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, req book.BookRequest) (*book.BookResponse, error) {
+	model := book.BookModel{
+		ID:          id,
+		Title:       req.Title,
+		Author:      req.Author,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Price:       req.Price,
+	}
+	if err := validateBook(model); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, model)
+}
+
+// Patch only changes the fields present in the request, keeping the others as they are
+func (s *service) Patch(ctx context.Context, id int64, req book.PatchBookRequest) (*book.BookResponse, error) {
+	existing, err := s.bookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetBookFailed,
+			Msg:       constant.ErrorGetBookFailed,
+			Err:       err,
+		}
+	}
+
+	model := *existing
+	if req.Title != nil {
+		model.Title = *req.Title
+	}
+	if req.Author != nil {
+		model.Author = *req.Author
+	}
+	if req.Description != nil {
+		model.Description = sql.NullString{String: *req.Description, Valid: *req.Description != ""}
+	}
+	if req.Price != nil {
+		model.Price = *req.Price
+	}
+	if err := validateBook(model); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, model)
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	err := s.bookRepo.Delete(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeDeleteBookFailed,
+			Msg:       constant.ErrorDeleteBookFailed,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// Restore undoes the soft deletion of a book
+func (s *service) Restore(ctx context.Context, id int64) (*book.BookResponse, error) {
+	restored, err := s.bookRepo.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRestoreBookFailed,
+			Msg:       constant.ErrorRestoreBookFailed,
+			Err:       err,
+		}
+	}
+
+	res := toBookResponse(*restored)
+	return &res, nil
+}
+
+func (s *service) update(ctx context.Context, model book.BookModel) (*book.BookResponse, error) {
+	updated, err := s.bookRepo.Update(ctx, model)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateBookFailed,
+			Msg:       constant.ErrorUpdateBookFailed,
+			Err:       err,
+		}
+	}
+
+	res := toBookResponse(*updated)
+	return &res, nil
+}
+
+func toBookFilter(req book.ListBookRequest) (book.BookFilter, error) {
+	filter := book.BookFilter{
+		Author:   req.Author,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Sort:     req.Sort,
+		Limit:    pagination.Limit(req.Limit),
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = book.SortCreatedAt
+	}
+	if filter.Sort != book.SortCreatedAt && filter.Sort != book.SortPrice && filter.Sort != book.SortTitle {
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidBookSort,
+			Msg:       constant.ErrorInvalidBookSort,
+			Err:       fmt.Errorf("[BookSvc.List] unknown sort option %q", req.Sort),
+		}
+	}
+
+	switch req.Order {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidBookSort,
+			Msg:       constant.ErrorInvalidBookSort,
+			Err:       fmt.Errorf("[BookSvc.List] unknown order %q", req.Order),
+		}
+	}
+
+	if req.Cursor != "" {
+		var cursor book.BookCursor
+		if err := pagination.DecodeCursor(req.Cursor, &cursor); err != nil {
+			return filter, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeInvalidCursor,
+				Msg:       constant.ErrorInvalidCursor,
+				Err:       fmt.Errorf("[BookSvc.List] %v", err),
+			}
+		}
+		if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return filter, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeInvalidCursor,
+				Msg:       constant.ErrorInvalidCursor,
+				Err:       errors.New("[BookSvc.List] cursor was created for another sort or order"),
+			}
+		}
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+func validateBook(b book.BookModel) error {
+	if b.Title == "" || b.Author == "" || b.Price < 0 {
+		return &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidBook,
+			Msg:       constant.ErrorInvalidBook,
+			Err:       fmt.Errorf("[BookSvc.validateBook] invalid book: title=%q author=%q price=%d", b.Title, b.Author, b.Price),
+		}
+	}
+
+	return nil
+}
+
+func toBookResponse(b book.BookModel) book.BookResponse {
+	return book.BookResponse{
+		ID:          b.ID,
+		Title:       b.Title,
+		Author:      b.Author,
+		Description: b.Description.String,
+		Price:       b.Price,
+		IsDeleted:   b.IsDeleted,
+	}
+}
@@ -40,17 +40,121 @@ func (m *MockbookReposistory) EXPECT() *MockbookReposistoryMockRecorder {
 	return m.recorder
 }
 
-// GetAll mocks base method.
-func (m *MockbookReposistory) GetAll(ctx context.Context) (book.BookModels, error) {
+// Create mocks base method.
+func (m *MockbookReposistory) Create(ctx context.Context, req book.BookModel) (*book.BookModel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*book.BookModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockbookReposistoryMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockbookReposistory)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockbookReposistory) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockbookReposistoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockbookReposistory)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockbookReposistory) GetByID(ctx context.Context, id int64) (*book.BookModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*book.BookModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockbookReposistoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockbookReposistory)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockbookReposistory) List(ctx context.Context, filter book.BookFilter) (book.BookModels, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
 	ret0, _ := ret[0].(book.BookModels)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockbookReposistoryMockRecorder) GetAll(ctx any) *gomock.Call {
+// List indicates an expected call of List.
+func (mr *MockbookReposistoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockbookReposistory)(nil).List), ctx, filter)
+}
+
+// Restore mocks base method.
+func (m *MockbookReposistory) Restore(ctx context.Context, id int64) (*book.BookModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*book.BookModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockbookReposistoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockbookReposistory)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockbookReposistory) Search(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]book.BookSearchModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockbookReposistoryMockRecorder) Search(ctx, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockbookReposistory)(nil).Search), ctx, query, limit)
+}
+
+// SearchSimilar mocks base method.
+func (m *MockbookReposistory) SearchSimilar(ctx context.Context, query string, limit int) ([]book.BookSearchModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSimilar", ctx, query, limit)
+	ret0, _ := ret[0].([]book.BookSearchModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSimilar indicates an expected call of SearchSimilar.
+func (mr *MockbookReposistoryMockRecorder) SearchSimilar(ctx, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSimilar", reflect.TypeOf((*MockbookReposistory)(nil).SearchSimilar), ctx, query, limit)
+}
+
+// Update mocks base method.
+func (m *MockbookReposistory) Update(ctx context.Context, req book.BookModel) (*book.BookModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, req)
+	ret0, _ := ret[0].(*book.BookModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockbookReposistoryMockRecorder) Update(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockbookReposistory)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockbookReposistory)(nil).Update), ctx, req)
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/book"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/pagination"
 	. "github.com/smartystreets/goconvey/convey"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -24,16 +30,27 @@ func Test_service_List(t *testing.T) {
 
 	ctx := context.Background()
 
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minPrice := int64(100000)
+	nextCursor, _ := pagination.EncodeCursor(book.BookCursor{ID: 2, Sort: book.SortPrice, Price: 250000, Title: "Book 2", CreatedAt: createdAt})
+	descCursor, _ := pagination.EncodeCursor(book.BookCursor{ID: 2, Sort: book.SortPrice, Desc: true})
+
 	tests := []struct {
-		name       string
-		mock       func()
-		wantResult book.BookResponses
-		wantErr    bool
+		name           string
+		req            book.ListBookRequest
+		mock           func()
+		wantResult     book.BookResponses
+		wantPagination *response.Pagination
+		wantCode       int
 	}{
 		{
 			name: "success",
+			req:  book.ListBookRequest{},
 			mock: func() {
-				bookRepo.EXPECT().GetAll(ctx).Return([]book.BookModel{
+				bookRepo.EXPECT().List(ctx, book.BookFilter{
+					Sort:  book.SortCreatedAt,
+					Limit: pagination.DefaultLimit + 1,
+				}).Return([]book.BookModel{
 					{
 						ID:          1,
 						Title:       "Book 1",
@@ -66,15 +83,77 @@ func Test_service_List(t *testing.T) {
 					Price:       250000,
 				},
 			},
-			wantErr: false,
+			wantPagination: &response.Pagination{Limit: pagination.DefaultLimit},
+		},
+		{
+			name: "success with next page",
+			req:  book.ListBookRequest{Limit: 2, Sort: book.SortPrice, Author: "author", MinPrice: &minPrice},
+			mock: func() {
+				bookRepo.EXPECT().List(ctx, book.BookFilter{
+					Author:   "author",
+					MinPrice: &minPrice,
+					Sort:     book.SortPrice,
+					Limit:    3,
+				}).Return([]book.BookModel{
+					{ID: 1, Title: "Book 1", Price: 150000, CreatedAt: createdAt},
+					{ID: 2, Title: "Book 2", Price: 250000, CreatedAt: createdAt},
+					{ID: 3, Title: "Book 3", Price: 350000, CreatedAt: createdAt},
+				}, nil)
+			},
+			wantResult: book.BookResponses{
+				{ID: 1, Title: "Book 1", Price: 150000},
+				{ID: 2, Title: "Book 2", Price: 250000},
+			},
+			wantPagination: &response.Pagination{Limit: 2, HasMore: true, NextCursor: nextCursor},
+		},
+		{
+			name: "success with cursor",
+			req:  book.ListBookRequest{Limit: 2, Sort: book.SortPrice, Order: "desc", Cursor: descCursor},
+			mock: func() {
+				bookRepo.EXPECT().List(ctx, book.BookFilter{
+					Sort:  book.SortPrice,
+					Desc:  true,
+					After: &book.BookCursor{ID: 2, Sort: book.SortPrice, Desc: true},
+					Limit: 3,
+				}).Return([]book.BookModel{
+					{ID: 1, Title: "Book 1", Price: 150000},
+				}, nil)
+			},
+			wantResult: book.BookResponses{
+				{ID: 1, Title: "Book 1", Price: 150000},
+			},
+			wantPagination: &response.Pagination{Limit: 2},
+		},
+		{
+			name:     "invalid sort",
+			req:      book.ListBookRequest{Sort: "author"},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid order",
+			req:      book.ListBookRequest{Order: "up"},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid cursor",
+			req:      book.ListBookRequest{Cursor: "invalid"},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "cursor of another sort",
+			req:      book.ListBookRequest{Sort: book.SortTitle, Cursor: nextCursor},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
 		},
 		{
 			name: "error",
 			mock: func() {
-				bookRepo.EXPECT().GetAll(ctx).Return(nil, errors.New("database error"))
+				bookRepo.EXPECT().List(ctx, gomock.Any()).Return(nil, errors.New("database error"))
 			},
-			wantResult: nil,
-			wantErr:    true,
+			wantCode: http.StatusInternalServerError,
 		},
 	}
 
@@ -82,12 +161,572 @@ func Test_service_List(t *testing.T) {
 		for _, tt := range tests {
 			Convey(tt.name, func() {
 				tt.mock()
-				gotResult, gotErr := svc.List(ctx)
-				if tt.wantErr {
-					So(gotErr, ShouldNotBeNil)
+				gotResult, gotPagination, gotErr := svc.List(ctx, tt.req)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+					So(gotPagination, ShouldResemble, tt.wantPagination)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		req        book.BookRequest
+		mock       func()
+		wantResult *book.BookResponse
+		wantCode   int
+	}{
+		{
+			name:     "invalid book",
+			req:      book.BookRequest{Title: "Book 1", Price: 150000},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "error from repository",
+			req:  book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			mock: func() {
+				bookRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "success",
+			req:  book.BookRequest{Title: "Book 1", Author: "Author 1", Description: "Description 1", Price: 150000},
+			mock: func() {
+				bookRepo.EXPECT().Create(ctx, book.BookModel{
+					Title:       "Book 1",
+					Author:      "Author 1",
+					Description: sql.NullString{String: "Description 1", Valid: true},
+					Price:       150000,
+				}).Return(&book.BookModel{
+					ID:          1,
+					Title:       "Book 1",
+					Author:      "Author 1",
+					Description: sql.NullString{String: "Description 1", Valid: true},
+					Price:       150000,
+				}, nil)
+			},
+			wantResult: &book.BookResponse{
+				ID:          1,
+				Title:       "Book 1",
+				Author:      "Author 1",
+				Description: "Description 1",
+				Price:       150000,
+			},
+		},
+	}
+
+	Convey("Test Book Service - Create", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotErr := svc.Create(ctx, tt.req)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Update(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		req        book.BookRequest
+		mock       func()
+		wantResult *book.BookResponse
+		wantCode   int
+	}{
+		{
+			name:     "invalid book",
+			req:      book.BookRequest{Title: "Book 1", Author: "Author 1", Price: -1},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "book not found",
+			req:  book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			mock: func() {
+				bookRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil, fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "error from repository",
+			req:  book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			mock: func() {
+				bookRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "success",
+			req:  book.BookRequest{Title: "Book 1", Author: "Author 1", Price: 150000},
+			mock: func() {
+				bookRepo.EXPECT().Update(ctx, book.BookModel{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}).Return(&book.BookModel{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}, nil)
+			},
+			wantResult: &book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  150000,
+			},
+		},
+	}
+
+	Convey("Test Book Service - Update", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotErr := svc.Update(ctx, 1, tt.req)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Patch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	existing := &book.BookModel{
+		ID:          1,
+		Title:       "Book 1",
+		Author:      "Author 1",
+		Description: sql.NullString{String: "Description 1", Valid: true},
+		Price:       150000,
+	}
+	price := int64(175000)
+	emptyTitle := ""
+
+	tests := []struct {
+		name       string
+		req        book.PatchBookRequest
+		mock       func()
+		wantResult *book.BookResponse
+		wantCode   int
+	}{
+		{
+			name: "book not found",
+			req:  book.PatchBookRequest{Price: &price},
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "error when getting book",
+			req:  book.PatchBookRequest{Price: &price},
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "invalid book after patch",
+			req:  book.PatchBookRequest{Title: &emptyTitle},
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(existing, nil)
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "success",
+			req:  book.PatchBookRequest{Price: &price},
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(existing, nil)
+				bookRepo.EXPECT().Update(ctx, book.BookModel{
+					ID:          1,
+					Title:       "Book 1",
+					Author:      "Author 1",
+					Description: sql.NullString{String: "Description 1", Valid: true},
+					Price:       price,
+				}).Return(&book.BookModel{
+					ID:          1,
+					Title:       "Book 1",
+					Author:      "Author 1",
+					Description: sql.NullString{String: "Description 1", Valid: true},
+					Price:       price,
+				}, nil)
+			},
+			wantResult: &book.BookResponse{
+				ID:          1,
+				Title:       "Book 1",
+				Author:      "Author 1",
+				Description: "Description 1",
+				Price:       price,
+			},
+		},
+	}
+
+	Convey("Test Book Service - Patch", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotErr := svc.Patch(ctx, 1, tt.req)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Delete(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr *response.ServiceError
+	}{
+		{
+			name: "book not found",
+			mock: func() {
+				bookRepo.EXPECT().Delete(ctx, int64(1)).Return(fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantErr: &response.ServiceError{Code: http.StatusNotFound, Msg: constant.ErrorBookNotFound},
+		},
+		{
+			name: "error from repository",
+			mock: func() {
+				bookRepo.EXPECT().Delete(ctx, int64(1)).Return(errors.New("database error"))
+			},
+			wantErr: &response.ServiceError{Code: http.StatusInternalServerError, Msg: constant.ErrorDeleteBookFailed},
+		},
+		{
+			name: "success",
+			mock: func() {
+				bookRepo.EXPECT().Delete(ctx, int64(1)).Return(nil)
+			},
+		},
+	}
+
+	Convey("Test Book Service - Delete", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotErr := svc.Delete(ctx, 1)
+				if tt.wantErr != nil {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantErr.Code)
+					So(svcErr.Msg, ShouldEqual, tt.wantErr.Msg)
+				} else {
+					So(gotErr, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Restore(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		mock       func()
+		wantResult *book.BookResponse
+		wantCode   int
+	}{
+		{
+			name: "book not found",
+			mock: func() {
+				bookRepo.EXPECT().Restore(ctx, int64(1)).Return(nil, fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "error from repository",
+			mock: func() {
+				bookRepo.EXPECT().Restore(ctx, int64(1)).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "success",
+			mock: func() {
+				bookRepo.EXPECT().Restore(ctx, int64(1)).Return(&book.BookModel{
+					ID:     1,
+					Title:  "Book 1",
+					Author: "Author 1",
+					Price:  150000,
+				}, nil)
+			},
+			wantResult: &book.BookResponse{
+				ID:     1,
+				Title:  "Book 1",
+				Author: "Author 1",
+				Price:  150000,
+			},
+		},
+	}
+
+	Convey("Test Book Service - Restore", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotErr := svc.Restore(ctx, 1)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Search(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	gatsby := book.BookModel{ID: 1, Title: "The Great Gatsby", Author: "F. Scott Fitzgerald", Price: 100000}
+
+	tests := []struct {
+		name       string
+		req        book.SearchBookRequest
+		mock       func()
+		wantResult book.BookResponses
+		wantCode   int
+	}{
+		{
+			name:     "empty query",
+			req:      book.SearchBookRequest{Query: "  "},
+			mock:     func() {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "found by full-text search",
+			req:  book.SearchBookRequest{Query: " gatsby ", Limit: 5},
+			mock: func() {
+				bookRepo.EXPECT().Search(ctx, "gatsby", 5).Return([]book.BookSearchModel{
+					{
+						BookModel:    gatsby,
+						Rank:         0.6,
+						TitleSnippet: sql.NullString{String: "The Great <mark>Gatsby</mark>", Valid: true},
+					},
+				}, nil)
+			},
+			wantResult: book.BookResponses{
+				{
+					ID:        1,
+					Title:     "The Great Gatsby",
+					Author:    "F. Scott Fitzgerald",
+					Price:     100000,
+					Highlight: &book.BookHighlight{Title: "The Great <mark>Gatsby</mark>"},
+				},
+			},
+		},
+		{
+			name: "found by similarity",
+			req:  book.SearchBookRequest{Query: "gatsbi"},
+			mock: func() {
+				bookRepo.EXPECT().Search(ctx, "gatsbi", pagination.DefaultLimit).Return(nil, nil)
+				bookRepo.EXPECT().SearchSimilar(ctx, "gatsbi", pagination.DefaultLimit).Return([]book.BookSearchModel{
+					{BookModel: gatsby, Rank: 0.5},
+				}, nil)
+			},
+			wantResult: book.BookResponses{
+				{
+					ID:     1,
+					Title:  "The Great Gatsby",
+					Author: "F. Scott Fitzgerald",
+					Price:  100000,
+				},
+			},
+		},
+		{
+			name: "error from full-text search",
+			req:  book.SearchBookRequest{Query: "gatsby"},
+			mock: func() {
+				bookRepo.EXPECT().Search(ctx, "gatsby", pagination.DefaultLimit).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "error from similarity search",
+			req:  book.SearchBookRequest{Query: "gatsbi"},
+			mock: func() {
+				bookRepo.EXPECT().Search(ctx, "gatsbi", pagination.DefaultLimit).Return(nil, nil)
+				bookRepo.EXPECT().SearchSimilar(ctx, "gatsbi", pagination.DefaultLimit).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Book Service - Search", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotErr := svc.Search(ctx, tt.req)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(gotErr, ShouldBeNil)
+					So(gotResult, ShouldResemble, tt.wantResult)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Detail(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(bookRepo)
+
+	ctx := context.Background()
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		mock             func()
+		wantResult       *book.BookResponse
+		wantLastModified time.Time
+		wantCode         int
+	}{
+		{
+			name: "book not found",
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "error from repository",
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, errors.New("database error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "never updated book",
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(&book.BookModel{
+					ID:        1,
+					Title:     "Book 1",
+					Author:    "Author 1",
+					Price:     150000,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			wantResult:       &book.BookResponse{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000},
+			wantLastModified: createdAt,
+		},
+		{
+			name: "updated book",
+			mock: func() {
+				bookRepo.EXPECT().GetByID(ctx, int64(1)).Return(&book.BookModel{
+					ID:        1,
+					Title:     "Book 1",
+					Author:    "Author 1",
+					Price:     150000,
+					CreatedAt: createdAt,
+					UpdatedAt: sql.NullTime{Time: updatedAt, Valid: true},
+				}, nil)
+			},
+			wantResult:       &book.BookResponse{ID: 1, Title: "Book 1", Author: "Author 1", Price: 150000},
+			wantLastModified: updatedAt,
+		},
+	}
+
+	Convey("Test Book Service - Detail", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				gotResult, gotLastModified, gotErr := svc.Detail(ctx, 1)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(gotErr, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
 				} else {
 					So(gotErr, ShouldBeNil)
-					So(gotResult, ShouldEqual, tt.wantResult)
+					So(gotResult, ShouldResemble, tt.wantResult)
+					So(gotLastModified, ShouldEqual, tt.wantLastModified)
 				}
 			})
 		}
@@ -0,0 +1,154 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/pagination"
+)
+
+// stockConstraint is the check constraint keeping the stock of a book from going below zero
+const stockConstraint = "chk_books_stock"
+
+//go:generate mockgen -source=service.go -package=inventory -destination=service_mock_test.go
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type inventoryReposistory interface {
+	GetStock(ctx context.Context, bookID int64) (*inventory.StockModel, error)
+	AdjustStock(ctx context.Context, bookID, qtyChange int64) (int64, error)
+	CreateAdjustment(ctx context.Context, req inventory.StockAdjustmentModel) (*inventory.StockAdjustmentModel, error)
+	GetAdjustments(ctx context.Context, bookID int64, limit int) ([]inventory.StockAdjustmentModel, error)
+}
+
+type service struct {
+	transactor    transactor
+	inventoryRepo inventoryReposistory
+}
+
+func New(transactor transactor, inventoryRepo inventoryReposistory) *service {
+	return &service{
+		transactor:    transactor,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+// GetStock returns the stock of the book with its latest adjustments
+func (s *service) GetStock(ctx context.Context, bookID int64, req inventory.GetStockRequest) (*inventory.StockResponse, error) {
+	stock, err := s.inventoryRepo.GetStock(ctx, bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetStockFailed,
+			Msg:       constant.ErrorGetStockFailed,
+			Err:       err,
+		}
+	}
+
+	adjustments, err := s.inventoryRepo.GetAdjustments(ctx, bookID, pagination.Limit(req.Limit))
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetStockFailed,
+			Msg:       constant.ErrorGetStockFailed,
+			Err:       err,
+		}
+	}
+
+	res := &inventory.StockResponse{
+		BookID:      stock.BookID,
+		Stock:       stock.Stock,
+		Adjustments: make([]inventory.StockAdjustmentResponse, 0, len(adjustments)),
+	}
+	for _, adjustment := range adjustments {
+		res.Adjustments = append(res.Adjustments, toStockAdjustmentResponse(adjustment))
+	}
+
+	return res, nil
+}
+
+// AdjustStock changes the stock of the book and records the adjustment made by the user in the audit
+func (s *service) AdjustStock(ctx context.Context, userID, bookID int64, req inventory.AdjustStockRequest) (*inventory.StockAdjustmentResponse, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.QtyChange == 0 || req.Reason == "" {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidStockAdjustment,
+			Msg:       constant.ErrorInvalidStockAdjustment,
+			Err:       fmt.Errorf("[InventorySvc.AdjustStock] invalid adjustment of book %d", bookID),
+		}
+	}
+
+	var adjustment *inventory.StockAdjustmentModel
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		stock, err := s.inventoryRepo.AdjustStock(ctx, bookID, req.QtyChange)
+		if err != nil {
+			return err
+		}
+
+		adjustment, err = s.inventoryRepo.CreateAdjustment(ctx, inventory.StockAdjustmentModel{
+			BookID:    bookID,
+			UserID:    userID,
+			QtyChange: req.QtyChange,
+			Stock:     stock,
+			Reason:    req.Reason,
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeBookNotFound,
+				Msg:       constant.ErrorBookNotFound,
+				Err:       err,
+			}
+		case errors.Is(err, db.ErrCheckViolation) && db.Constraint(err) == stockConstraint:
+			return nil, &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeInsufficientStock,
+				Msg:       constant.ErrorInsufficientStock,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeAdjustStockFailed,
+			Msg:       constant.ErrorAdjustStockFailed,
+			Err:       err,
+		}
+	}
+
+	res := toStockAdjustmentResponse(*adjustment)
+	return &res, nil
+}
+
+func toStockAdjustmentResponse(adjustment inventory.StockAdjustmentModel) inventory.StockAdjustmentResponse {
+	return inventory.StockAdjustmentResponse{
+		ID:        adjustment.ID,
+		BookID:    adjustment.BookID,
+		UserID:    adjustment.UserID,
+		QtyChange: adjustment.QtyChange,
+		Stock:     adjustment.Stock,
+		Reason:    adjustment.Reason,
+		CreatedAt: adjustment.CreatedAt,
+	}
+}
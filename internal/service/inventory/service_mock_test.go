@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -package=inventory -destination=service_mock_test.go
+//
+
+// Package inventory is a generated GoMock package.
+package inventory
+
+import (
+	context "context"
+	reflect "reflect"
+
+	inventory "github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mocktransactor is a mock of transactor interface.
+type Mocktransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransactorMockRecorder
+}
+
+// MocktransactorMockRecorder is the mock recorder for Mocktransactor.
+type MocktransactorMockRecorder struct {
+	mock *Mocktransactor
+}
+
+// NewMocktransactor creates a new mock instance.
+func NewMocktransactor(ctrl *gomock.Controller) *Mocktransactor {
+	mock := &Mocktransactor{ctrl: ctrl}
+	mock.recorder = &MocktransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocktransactor) EXPECT() *MocktransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *Mocktransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MocktransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*Mocktransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockinventoryReposistory is a mock of inventoryReposistory interface.
+type MockinventoryReposistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockinventoryReposistoryMockRecorder
+}
+
+// MockinventoryReposistoryMockRecorder is the mock recorder for MockinventoryReposistory.
+type MockinventoryReposistoryMockRecorder struct {
+	mock *MockinventoryReposistory
+}
+
+// NewMockinventoryReposistory creates a new mock instance.
+func NewMockinventoryReposistory(ctrl *gomock.Controller) *MockinventoryReposistory {
+	mock := &MockinventoryReposistory{ctrl: ctrl}
+	mock.recorder = &MockinventoryReposistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinventoryReposistory) EXPECT() *MockinventoryReposistoryMockRecorder {
+	return m.recorder
+}
+
+// AdjustStock mocks base method.
+func (m *MockinventoryReposistory) AdjustStock(ctx context.Context, bookID, qtyChange int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", ctx, bookID, qtyChange)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockinventoryReposistoryMockRecorder) AdjustStock(ctx, bookID, qtyChange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockinventoryReposistory)(nil).AdjustStock), ctx, bookID, qtyChange)
+}
+
+// CreateAdjustment mocks base method.
+func (m *MockinventoryReposistory) CreateAdjustment(ctx context.Context, req inventory.StockAdjustmentModel) (*inventory.StockAdjustmentModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdjustment", ctx, req)
+	ret0, _ := ret[0].(*inventory.StockAdjustmentModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdjustment indicates an expected call of CreateAdjustment.
+func (mr *MockinventoryReposistoryMockRecorder) CreateAdjustment(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdjustment", reflect.TypeOf((*MockinventoryReposistory)(nil).CreateAdjustment), ctx, req)
+}
+
+// GetAdjustments mocks base method.
+func (m *MockinventoryReposistory) GetAdjustments(ctx context.Context, bookID int64, limit int) ([]inventory.StockAdjustmentModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", ctx, bookID, limit)
+	ret0, _ := ret[0].([]inventory.StockAdjustmentModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockinventoryReposistoryMockRecorder) GetAdjustments(ctx, bookID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockinventoryReposistory)(nil).GetAdjustments), ctx, bookID, limit)
+}
+
+// GetStock mocks base method.
+func (m *MockinventoryReposistory) GetStock(ctx context.Context, bookID int64) (*inventory.StockModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, bookID)
+	ret0, _ := ret[0].(*inventory.StockModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockinventoryReposistoryMockRecorder) GetStock(ctx, bookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockinventoryReposistory)(nil).GetStock), ctx, bookID)
+}
@@ -0,0 +1,213 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/lib/pq"
+	. "github.com/smartystreets/goconvey/convey"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func newMock(mockTransactor *Mocktransactor, mockInventoryRepo *MockinventoryReposistory) *service {
+	return New(mockTransactor, mockInventoryRepo)
+}
+
+// runInTx runs the unit of work as the transactor would, without a database
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func Test_service_GetStock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, inventoryRepo)
+
+	createdAt := time.Now()
+
+	tests := []struct {
+		name     string
+		req      inventory.GetStockRequest
+		mock     func()
+		want     *inventory.StockResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			req:  inventory.GetStockRequest{Limit: 5},
+			mock: func() {
+				inventoryRepo.EXPECT().GetStock(gomock.Any(), int64(1)).Return(&inventory.StockModel{BookID: 1, Stock: 10}, nil)
+				inventoryRepo.EXPECT().GetAdjustments(gomock.Any(), int64(1), 5).Return([]inventory.StockAdjustmentModel{
+					{ID: 1, BookID: 1, UserID: 2, QtyChange: 10, Stock: 10, Reason: "restock", CreatedAt: createdAt},
+				}, nil)
+			},
+			want: &inventory.StockResponse{
+				BookID: 1,
+				Stock:  10,
+				Adjustments: []inventory.StockAdjustmentResponse{
+					{ID: 1, BookID: 1, UserID: 2, QtyChange: 10, Stock: 10, Reason: "restock", CreatedAt: createdAt},
+				},
+			},
+		},
+		{
+			name: "book not found",
+			mock: func() {
+				inventoryRepo.EXPECT().GetStock(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "failed to get stock",
+			mock: func() {
+				inventoryRepo.EXPECT().GetStock(gomock.Any(), int64(1)).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failed to get adjustments",
+			mock: func() {
+				inventoryRepo.EXPECT().GetStock(gomock.Any(), int64(1)).Return(&inventory.StockModel{BookID: 1, Stock: 10}, nil)
+				inventoryRepo.EXPECT().GetAdjustments(gomock.Any(), int64(1), 20).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Inventory Service - GetStock", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.GetStock(context.Background(), 1, tt.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_AdjustStock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, inventoryRepo)
+
+	createdAt := time.Now()
+
+	tests := []struct {
+		name     string
+		req      inventory.AdjustStockRequest
+		mock     func()
+		want     *inventory.StockAdjustmentResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			req:  inventory.AdjustStockRequest{QtyChange: -3, Reason: " damaged "},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), int64(1), int64(-3)).Return(int64(7), nil)
+				inventoryRepo.EXPECT().CreateAdjustment(gomock.Any(), inventory.StockAdjustmentModel{
+					BookID:    1,
+					UserID:    2,
+					QtyChange: -3,
+					Stock:     7,
+					Reason:    "damaged",
+				}).Return(&inventory.StockAdjustmentModel{
+					ID: 1, BookID: 1, UserID: 2, QtyChange: -3, Stock: 7, Reason: "damaged", CreatedAt: createdAt,
+				}, nil)
+			},
+			want: &inventory.StockAdjustmentResponse{
+				ID: 1, BookID: 1, UserID: 2, QtyChange: -3, Stock: 7, Reason: "damaged", CreatedAt: createdAt,
+			},
+		},
+		{
+			name:     "zero quantity change",
+			req:      inventory.AdjustStockRequest{Reason: "recount"},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "empty reason",
+			req:      inventory.AdjustStockRequest{QtyChange: 1, Reason: " "},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "book not found",
+			req:  inventory.AdjustStockRequest{QtyChange: 1, Reason: "restock"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), int64(1), int64(1)).Return(int64(0), fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "stock below zero",
+			req:  inventory.AdjustStockRequest{QtyChange: -100, Reason: "damaged"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), int64(1), int64(-100)).
+					Return(int64(0), fmt.Errorf("error: %w", db.Translate(&pq.Error{Code: "23514", Constraint: stockConstraint})))
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "failed to create adjustment",
+			req:  inventory.AdjustStockRequest{QtyChange: 1, Reason: "restock"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), int64(1), int64(1)).Return(int64(11), nil)
+				inventoryRepo.EXPECT().CreateAdjustment(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Inventory Service - AdjustStock", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.AdjustStock(context.Background(), 2, 1, tt.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -2,156 +2,707 @@ package order
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/book"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/pagination"
 )
 
 //go:generate mockgen -source=service.go -package=order -destination=service_mock_test.go
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type bookReposistory interface {
+	GetByIDs(ctx context.Context, ids []int64) (book.BookModels, error)
+}
+
+type inventoryReposistory interface {
+	DecrementStock(ctx context.Context, changes []inventory.StockChangeModel) ([]int64, error)
+	IncrementStock(ctx context.Context, changes []inventory.StockChangeModel) error
+}
+
 type orderReposistory interface {
-	CreateOrder(ctx context.Context, req order.OrderModel) (int64, error)
+	CreateOrder(ctx context.Context, req order.OrderModel) (*order.OrderModel, error)
 	BulkCreateOrderDetail(ctx context.Context, req []order.OrderDetailModel) ([]order.OrderDetailModel, error)
-	GetAllOrder(ctx context.Context, userID int64) ([]order.OrderModel, error)
+	GetAllOrder(ctx context.Context, userID int64, filter order.OrderFilter) ([]order.OrderModel, error)
 	GetOrderDetail(ctx context.Context, userID, orderID int64) ([]order.OrderDetailModel, error)
+	GetOrderWithDetails(ctx context.Context, userID, orderID int64) (*order.OrderModel, []order.OrderDetailModel, error)
+	GetOrderDetailsByOrderIDs(ctx context.Context, orderIDs []int64) ([]order.OrderDetailModel, error)
+	GetByID(ctx context.Context, orderID int64) (*order.OrderModel, error)
+	UpdateStatus(ctx context.Context, orderID int64, from, to string) (time.Time, error)
+	CreateStatusHistory(ctx context.Context, req order.OrderStatusHistoryModel) (*order.OrderStatusHistoryModel, error)
+	GetStatusHistory(ctx context.Context, orderID int64) ([]order.OrderStatusHistoryModel, error)
 }
 
 type service struct {
-	orderRepo orderReposistory
+	transactor    transactor
+	orderRepo     orderReposistory
+	bookRepo      bookReposistory
+	inventoryRepo inventoryReposistory
 }
 
-func New(orderRepo orderReposistory) *service {
+func New(transactor transactor, orderRepo orderReposistory, bookRepo bookReposistory, inventoryRepo inventoryReposistory) *service {
 	return &service{
-		orderRepo: orderRepo,
+		transactor:    transactor,
+		orderRepo:     orderRepo,
+		bookRepo:      bookRepo,
+		inventoryRepo: inventoryRepo,
 	}
 }
 
 func (s *service) CreateOrder(ctx context.Context, userID int64, req order.CreateOrderRequest) (*order.OrderResponse, error) {
 	var (
 		totalQty, totalPrice int64
-		details              []order.OrderDetailModel
 		detailResp           []order.OrderDetailResponse
 	)
 
-	for _, detail := range req.Details {
+	details, err := s.priceDetails(ctx, req.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, detail := range details {
 		totalQty += detail.Qty
 		totalPrice += detail.Price
-		details = append(details, order.OrderDetailModel{
-			BookID: detail.BookID,
-			Qty:    detail.Qty,
-			Price:  detail.Price,
-		})
 	}
 
-	orderID, err := s.orderRepo.CreateOrder(ctx, order.OrderModel{
-		UserID:     userID,
-		TotalQty:   totalQty,
-		TotalPrice: totalPrice,
+	var (
+		o       *order.OrderModel
+		history *order.OrderStatusHistoryModel
+	)
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.reserveStock(ctx, details); err != nil {
+			return err
+		}
+
+		var err error
+		o, err = s.orderRepo.CreateOrder(ctx, order.OrderModel{
+			UserID:     userID,
+			TotalQty:   totalQty,
+			TotalPrice: totalPrice,
+		})
+		if err != nil {
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCreateOrderFailed,
+				Msg:       constant.ErrorCreateOrderFailed,
+				Err:       err,
+			}
+		}
+
+		for i, detail := range details {
+			detail.OrderID = o.ID
+			details[i] = detail
+		}
+
+		details, err = s.orderRepo.BulkCreateOrderDetail(ctx, details)
+		if err != nil {
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCreateOrderDetailFailed,
+				Msg:       constant.ErrorCreateOrderDetailFailed,
+				Err:       err,
+			}
+		}
+
+		history, err = s.orderRepo.CreateStatusHistory(ctx, order.OrderStatusHistoryModel{
+			OrderID:  o.ID,
+			ToStatus: order.StatusPending,
+			ActorID:  sql.NullInt64{Int64: userID, Valid: true},
+		})
+		if err != nil {
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCreateOrderFailed,
+				Msg:       constant.ErrorCreateOrderFailed,
+				Err:       err,
+			}
+		}
+
+		return nil
 	})
 	if err != nil {
+		var svcErr *response.ServiceError
+		if errors.As(err, &svcErr) {
+			return nil, svcErr
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreateOrderFailed,
+			Msg:       constant.ErrorCreateOrderFailed,
+			Err:       err,
+		}
+	}
+
+	for _, detail := range details {
+		detailResp = append(detailResp, toOrderDetailResponse(detail))
+	}
+
+	res := toOrderResponse(*o)
+	res.Details = detailResp
+	res.History = toStatusHistoryResponses([]order.OrderStatusHistoryModel{*history})
+	return &res, nil
+}
+
+// priceDetails prices every line of the order with the current price of its book, which has to exist
+// and not be deleted. A line price quoted by the client must match the current price.
+func (s *service) priceDetails(ctx context.Context, reqs []order.CreateOrderDetailRequest) ([]order.OrderDetailModel, error) {
+	if len(reqs) == 0 {
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorCreateOrderFailed,
-			Err:  err,
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderDetails,
+			Msg:       constant.ErrorInvalidOrderDetails,
+			Err:       errors.New("[OrderSvc.CreateOrder] order has no details"),
 		}
 	}
 
-	for i, detail := range details {
-		detail.OrderID = orderID
-		details[i] = detail
+	var bookIDs []int64
+	seen := make(map[int64]bool, len(reqs))
+	for _, req := range reqs {
+		if req.Qty <= 0 {
+			return nil, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeInvalidOrderDetails,
+				Msg:       constant.ErrorInvalidOrderDetails,
+				Err:       fmt.Errorf("[OrderSvc.CreateOrder] invalid quantity %d of book %d", req.Qty, req.BookID),
+			}
+		}
+		if !seen[req.BookID] {
+			seen[req.BookID] = true
+			bookIDs = append(bookIDs, req.BookID)
+		}
 	}
 
-	details, err = s.orderRepo.BulkCreateOrderDetail(ctx, details)
+	books, err := s.bookRepo.GetByIDs(ctx, bookIDs)
 	if err != nil {
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorCreateOrderDetailFailed,
-			Err:  err,
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreateOrderFailed,
+			Msg:       constant.ErrorCreateOrderFailed,
+			Err:       err,
+		}
+	}
+
+	prices := make(map[int64]int64, len(books))
+	for _, b := range books {
+		prices[b.ID] = b.Price
+	}
+
+	var missing []int64
+	for _, id := range bookIDs {
+		if _, ok := prices[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeOrderBooksNotFound,
+			Msg:       constant.ErrorOrderBooksNotFound,
+			Err:       fmt.Errorf("[OrderSvc.CreateOrder] books %v not found", missing),
+			Details:   order.BookIDsErrorDetails{BookIDs: missing},
 		}
 	}
 
+	var (
+		details = make([]order.OrderDetailModel, len(reqs))
+		changed []order.PriceChangedErrorDetails
+	)
+	for i, req := range reqs {
+		price := prices[req.BookID] * req.Qty
+		if req.Price != 0 && req.Price != price {
+			changed = append(changed, order.PriceChangedErrorDetails{
+				BookID:      req.BookID,
+				QuotedPrice: req.Price,
+				Price:       price,
+			})
+		}
+
+		details[i] = order.OrderDetailModel{
+			BookID: req.BookID,
+			Qty:    req.Qty,
+			Price:  price,
+		}
+	}
+	if len(changed) > 0 {
+		return nil, &response.ServiceError{
+			Code:      http.StatusConflict,
+			ErrorCode: constant.CodeOrderPriceChanged,
+			Msg:       constant.ErrorOrderPriceChanged,
+			Err:       fmt.Errorf("[OrderSvc.CreateOrder] prices of %d lines have changed", len(changed)),
+			Details:   changed,
+		}
+	}
+
+	return details, nil
+}
+
+// reserveStock takes the ordered quantities from the stock of the books, failing when any of them does
+// not have enough stock left. It must run in the order transaction, so the stock is given back when
+// the order cannot be created.
+func (s *service) reserveStock(ctx context.Context, details []order.OrderDetailModel) error {
+	changes := stockChanges(details)
+	reserved, err := s.inventoryRepo.DecrementStock(ctx, changes)
+	if err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreateOrderFailed,
+			Msg:       constant.ErrorCreateOrderFailed,
+			Err:       err,
+		}
+	}
+	if len(reserved) == len(changes) {
+		return nil
+	}
+
+	isReserved := make(map[int64]bool, len(reserved))
+	for _, id := range reserved {
+		isReserved[id] = true
+	}
+
+	var outOfStock []int64
+	for _, change := range changes {
+		if !isReserved[change.BookID] {
+			outOfStock = append(outOfStock, change.BookID)
+		}
+	}
+
+	return &response.ServiceError{
+		Code:      http.StatusConflict,
+		ErrorCode: constant.CodeOrderOutOfStock,
+		Msg:       constant.ErrorOrderOutOfStock,
+		Err:       fmt.Errorf("[OrderSvc.CreateOrder] books %v are out of stock", outOfStock),
+		Details:   order.BookIDsErrorDetails{BookIDs: outOfStock},
+	}
+}
+
+// stockChanges sums the quantities of the order lines per book
+func stockChanges(details []order.OrderDetailModel) []inventory.StockChangeModel {
+	var changes []inventory.StockChangeModel
+	index := make(map[int64]int, len(details))
 	for _, detail := range details {
-		detailResp = append(detailResp, order.OrderDetailResponse{
-			ID:     detail.ID,
+		if i, ok := index[detail.BookID]; ok {
+			changes[i].Qty += detail.Qty
+			continue
+		}
+		index[detail.BookID] = len(changes)
+		changes = append(changes, inventory.StockChangeModel{
 			BookID: detail.BookID,
 			Qty:    detail.Qty,
-			Price:  detail.Price,
 		})
 	}
-
-	return &order.OrderResponse{
-		ID:         orderID,
-		UserID:     userID,
-		TotalQty:   totalQty,
-		TotalPrice: totalPrice,
-		Details:    detailResp,
-	}, nil
+	return changes
 }
 
-func (s *service) ListOrder(ctx context.Context, userID int64) ([]order.OrderResponse, error) {
-	orders, err := s.orderRepo.GetAllOrder(ctx, userID)
+// ListOrder returns a page of the orders of the user, newest first. The next page is requested with
+// the cursor of the returned pagination. With the details include, the lines of all the orders in the
+// page are loaded at once.
+func (s *service) ListOrder(ctx context.Context, userID int64, req order.ListOrderRequest) ([]order.OrderResponse, *response.Pagination, error) {
+	if req.Include != "" && req.Include != order.IncludeDetails {
+		return nil, nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderInclude,
+			Msg:       constant.ErrorInvalidOrderInclude,
+			Err:       fmt.Errorf("[OrderSvc.ListOrder] unknown include %q", req.Include),
+		}
+	}
+
+	filter, err := toOrderFilter(req)
 	if err != nil {
-		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorGetAllOrderFailed,
-			Err:  err,
+		return nil, nil, err
+	}
+
+	// Fetch one more order than requested to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	orders, err := s.orderRepo.GetAllOrder(ctx, userID, filter)
+	if err != nil {
+		return nil, nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetAllOrderFailed,
+			Msg:       constant.ErrorGetAllOrderFailed,
+			Err:       err,
+		}
+	}
+
+	pg := &response.Pagination{
+		Limit:   limit,
+		HasMore: len(orders) > limit,
+	}
+	if pg.HasMore {
+		orders = orders[:limit]
+
+		last := orders[len(orders)-1]
+		pg.NextCursor, err = pagination.EncodeCursor(order.OrderCursor{
+			ID:        last.ID,
+			CreatedAt: last.CreatedAt,
+		})
+		if err != nil {
+			return nil, nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeGetAllOrderFailed,
+				Msg:       constant.ErrorGetAllOrderFailed,
+				Err:       err,
+			}
 		}
 	}
 
 	res := make([]order.OrderResponse, len(orders))
 	for i, o := range orders {
-		res[i] = order.OrderResponse{
-			ID:         o.ID,
-			UserID:     o.UserID,
-			TotalQty:   o.TotalQty,
-			TotalPrice: o.TotalPrice,
+		res[i] = toOrderResponse(o)
+	}
+
+	if req.Include == order.IncludeDetails && len(orders) > 0 {
+		if err := s.embedDetails(ctx, res); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	return res, nil
+	return res, pg, nil
 }
 
+// embedDetails loads the lines of all the orders with a single query and attaches them to their order
+func (s *service) embedDetails(ctx context.Context, orders []order.OrderResponse) error {
+	orderIDs := make([]int64, len(orders))
+	for i, o := range orders {
+		orderIDs[i] = o.ID
+	}
+
+	details, err := s.orderRepo.GetOrderDetailsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetAllOrderFailed,
+			Msg:       constant.ErrorGetAllOrderFailed,
+			Err:       err,
+		}
+	}
+
+	byOrderID := make(map[int64][]order.OrderDetailResponse, len(orders))
+	for _, d := range details {
+		byOrderID[d.OrderID] = append(byOrderID[d.OrderID], toOrderDetailResponse(d))
+	}
+	for i := range orders {
+		orders[i].Details = byOrderID[orders[i].ID]
+		if orders[i].Details == nil {
+			orders[i].Details = []order.OrderDetailResponse{}
+		}
+	}
+
+	return nil
+}
+
+// DetailOrder returns an order of the user with its lines and status history. The totals and timestamps
+// are the ones stored with the order.
 func (s *service) DetailOrder(ctx context.Context, userID, orderID int64) (*order.OrderResponse, error) {
-	details, err := s.orderRepo.GetOrderDetail(ctx, userID, orderID)
+	o, details, err := s.orderRepo.GetOrderWithDetails(ctx, userID, orderID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeOrderNotFound,
+				Msg:       constant.ErrorOrderNotFound,
+				Err:       err,
+			}
+		}
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorGetOrderDetailFailed,
-			Err:  err,
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetOrderDetailFailed,
+			Msg:       constant.ErrorGetOrderDetailFailed,
+			Err:       err,
 		}
 	}
 
-	if len(details) == 0 {
+	histories, err := s.orderRepo.GetStatusHistory(ctx, orderID)
+	if err != nil {
 		return nil, &response.ServiceError{
-			Code: http.StatusNotFound,
-			Msg:  constant.ErrorOrderNotFound,
-			Err:  fmt.Errorf("[OrderSvc.DetailOrder] order with id %d not found", orderID),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetOrderDetailFailed,
+			Msg:       constant.ErrorGetOrderDetailFailed,
+			Err:       err,
 		}
 	}
 
-	var (
-		res        = make([]order.OrderDetailResponse, len(details))
-		totalPrice int64
-		totalQty   int64
-	)
+	res := toOrderResponse(*o)
+	res.Details = make([]order.OrderDetailResponse, len(details))
 	for i, d := range details {
-		totalQty += d.Qty
-		totalPrice += d.Price
-		res[i] = order.OrderDetailResponse{
-			ID:     d.ID,
-			BookID: d.BookID,
-			Qty:    d.Qty,
-			Price:  d.Price,
-		}
-	}
-
-	return &order.OrderResponse{
-		ID:         orderID,
-		UserID:     userID,
-		TotalQty:   totalQty,
-		TotalPrice: totalPrice,
-		Details:    res,
-	}, nil
+		res.Details[i] = toOrderDetailResponse(d)
+	}
+	res.History = toStatusHistoryResponses(histories)
+	return &res, nil
+}
+
+// UpdateStatus moves the order to the requested status on behalf of an admin. Paid and refunded
+// are rejected, they are set by the payment of the order through UpdatePaymentStatus.
+func (s *service) UpdateStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	if isPaymentStatus(req.Status) {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeOrderStatusSetByPayment,
+			Msg:       constant.ErrorOrderStatusSetByPayment,
+			Err:       fmt.Errorf("[OrderSvc.UpdateStatus] order %d cannot be moved to %s by hand", orderID, req.Status),
+		}
+	}
+
+	return s.updateStatus(ctx, actorID, orderID, req)
+}
+
+// UpdatePaymentStatus moves the order to paid or refunded once its payment is settled or refunded.
+// It is only called by the payment service, an actorID of zero records the change as made by the system.
+func (s *service) UpdatePaymentStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	if !isPaymentStatus(req.Status) {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderStatus,
+			Msg:       constant.ErrorInvalidOrderStatus,
+			Err:       fmt.Errorf("[OrderSvc.UpdatePaymentStatus] %s is not set by payments", req.Status),
+		}
+	}
+
+	return s.updateStatus(ctx, actorID, orderID, req)
+}
+
+// updateStatus moves the order to the requested status in a transaction
+func (s *service) updateStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	var o *order.OrderModel
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		o, err = s.orderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &response.ServiceError{
+					Code:      http.StatusNotFound,
+					ErrorCode: constant.CodeOrderNotFound,
+					Msg:       constant.ErrorOrderNotFound,
+					Err:       err,
+				}
+			}
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeUpdateOrderStatusFailed,
+				Msg:       constant.ErrorUpdateOrderStatusFailed,
+				Err:       err,
+			}
+		}
+
+		_, err = s.transition(ctx, o, req.Status, actorID, strings.TrimSpace(req.Reason))
+		return err
+	})
+	if err != nil {
+		var svcErr *response.ServiceError
+		if errors.As(err, &svcErr) {
+			return nil, svcErr
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateOrderStatusFailed,
+			Msg:       constant.ErrorUpdateOrderStatusFailed,
+			Err:       err,
+		}
+	}
+
+	return s.statusResponse(ctx, *o)
+}
+
+// CancelOrder cancels the order on behalf of its owner and gives its books back to the stock.
+// Only pending orders can be cancelled, paid ones have to be refunded instead.
+func (s *service) CancelOrder(ctx context.Context, userID, orderID int64, req order.CancelOrderRequest) (*order.OrderResponse, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeCancelReasonRequired,
+			Msg:       constant.ErrorCancelReasonRequired,
+			Err:       fmt.Errorf("[OrderSvc.CancelOrder] order %d cancelled without a reason", orderID),
+		}
+	}
+
+	var o *order.OrderModel
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		o, err = s.orderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return &response.ServiceError{
+					Code:      http.StatusNotFound,
+					ErrorCode: constant.CodeOrderNotFound,
+					Msg:       constant.ErrorOrderNotFound,
+					Err:       err,
+				}
+			}
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCancelOrderFailed,
+				Msg:       constant.ErrorCancelOrderFailed,
+				Err:       err,
+			}
+		}
+
+		if o.UserID != userID {
+			return &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeOrderNotFound,
+				Msg:       constant.ErrorOrderNotFound,
+				Err:       fmt.Errorf("[OrderSvc.CancelOrder] order %d does not belong to user %d", orderID, userID),
+			}
+		}
+
+		if !canTransition(o.Status, order.StatusCancelled) {
+			return &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeOrderNotCancellable,
+				Msg:       constant.ErrorOrderNotCancellable,
+				Err:       fmt.Errorf("[OrderSvc.CancelOrder] order %d is %s", orderID, o.Status),
+			}
+		}
+
+		_, err = s.transition(ctx, o, order.StatusCancelled, userID, req.Reason)
+		return err
+	})
+	if err != nil {
+		var svcErr *response.ServiceError
+		if errors.As(err, &svcErr) {
+			return nil, svcErr
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCancelOrderFailed,
+			Msg:       constant.ErrorCancelOrderFailed,
+			Err:       err,
+		}
+	}
+
+	return s.statusResponse(ctx, *o)
+}
+
+// statusResponse returns the order header with its status history, after its status has changed
+func (s *service) statusResponse(ctx context.Context, o order.OrderModel) (*order.OrderResponse, error) {
+	histories, err := s.orderRepo.GetStatusHistory(ctx, o.ID)
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetOrderDetailFailed,
+			Msg:       constant.ErrorGetOrderDetailFailed,
+			Err:       err,
+		}
+	}
+
+	res := toOrderResponse(o)
+	res.History = toStatusHistoryResponses(histories)
+	return &res, nil
+}
+
+// toOrderFilter validates the list request and turns it into the filter of the repository
+func toOrderFilter(req order.ListOrderRequest) (order.OrderFilter, error) {
+	filter := order.OrderFilter{
+		Status: req.Status,
+		Limit:  pagination.Limit(req.Limit),
+	}
+
+	if filter.Status != "" && !isValidStatus(filter.Status) {
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderStatus,
+			Msg:       constant.ErrorInvalidOrderStatus,
+			Err:       fmt.Errorf("[OrderSvc.ListOrder] unknown status %q", req.Status),
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTime(req.From); err != nil {
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderDateRange,
+			Msg:       constant.ErrorInvalidOrderDateRange,
+			Err:       fmt.Errorf("[OrderSvc.ListOrder] invalid from: %v", err),
+		}
+	}
+	if filter.CreatedTo, err = parseTime(req.To); err != nil {
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderDateRange,
+			Msg:       constant.ErrorInvalidOrderDateRange,
+			Err:       fmt.Errorf("[OrderSvc.ListOrder] invalid to: %v", err),
+		}
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderDateRange,
+			Msg:       constant.ErrorInvalidOrderDateRange,
+			Err:       fmt.Errorf("[OrderSvc.ListOrder] from %s is not before to %s", req.From, req.To),
+		}
+	}
+
+	if req.Cursor != "" {
+		var cursor order.OrderCursor
+		if err := pagination.DecodeCursor(req.Cursor, &cursor); err != nil {
+			return filter, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeInvalidCursor,
+				Msg:       constant.ErrorInvalidCursor,
+				Err:       fmt.Errorf("[OrderSvc.ListOrder] %v", err),
+			}
+		}
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+// parseTime parses a date (YYYY-MM-DD), taken as midnight UTC, or an RFC3339 time. It returns nil
+// when value is empty.
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
+
+func toOrderResponse(o order.OrderModel) order.OrderResponse {
+	res := order.OrderResponse{
+		ID:         o.ID,
+		UserID:     o.UserID,
+		TotalQty:   o.TotalQty,
+		TotalPrice: o.TotalPrice,
+		Status:     o.Status,
+		CreatedAt:  o.CreatedAt,
+	}
+	if o.UpdatedAt.Valid {
+		updatedAt := o.UpdatedAt.Time
+		res.UpdatedAt = &updatedAt
+	}
+	return res
+}
+
+func toOrderDetailResponse(d order.OrderDetailModel) order.OrderDetailResponse {
+	return order.OrderDetailResponse{
+		ID:         d.ID,
+		BookID:     d.BookID,
+		BookTitle:  d.BookTitle,
+		BookAuthor: d.BookAuthor,
+		Qty:        d.Qty,
+		Price:      d.Price,
+	}
 }
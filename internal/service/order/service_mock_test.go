@@ -12,11 +12,141 @@ package order
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	book "github.com/erizkiatama/gotu-assignment/internal/model/book"
+	inventory "github.com/erizkiatama/gotu-assignment/internal/model/inventory"
 	order "github.com/erizkiatama/gotu-assignment/internal/model/order"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// Mocktransactor is a mock of transactor interface.
+type Mocktransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransactorMockRecorder
+}
+
+// MocktransactorMockRecorder is the mock recorder for Mocktransactor.
+type MocktransactorMockRecorder struct {
+	mock *Mocktransactor
+}
+
+// NewMocktransactor creates a new mock instance.
+func NewMocktransactor(ctrl *gomock.Controller) *Mocktransactor {
+	mock := &Mocktransactor{ctrl: ctrl}
+	mock.recorder = &MocktransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocktransactor) EXPECT() *MocktransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *Mocktransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MocktransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*Mocktransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockbookReposistory is a mock of bookReposistory interface.
+type MockbookReposistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockbookReposistoryMockRecorder
+}
+
+// MockbookReposistoryMockRecorder is the mock recorder for MockbookReposistory.
+type MockbookReposistoryMockRecorder struct {
+	mock *MockbookReposistory
+}
+
+// NewMockbookReposistory creates a new mock instance.
+func NewMockbookReposistory(ctrl *gomock.Controller) *MockbookReposistory {
+	mock := &MockbookReposistory{ctrl: ctrl}
+	mock.recorder = &MockbookReposistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockbookReposistory) EXPECT() *MockbookReposistoryMockRecorder {
+	return m.recorder
+}
+
+// GetByIDs mocks base method.
+func (m *MockbookReposistory) GetByIDs(ctx context.Context, ids []int64) (book.BookModels, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].(book.BookModels)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockbookReposistoryMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockbookReposistory)(nil).GetByIDs), ctx, ids)
+}
+
+// MockinventoryReposistory is a mock of inventoryReposistory interface.
+type MockinventoryReposistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockinventoryReposistoryMockRecorder
+}
+
+// MockinventoryReposistoryMockRecorder is the mock recorder for MockinventoryReposistory.
+type MockinventoryReposistoryMockRecorder struct {
+	mock *MockinventoryReposistory
+}
+
+// NewMockinventoryReposistory creates a new mock instance.
+func NewMockinventoryReposistory(ctrl *gomock.Controller) *MockinventoryReposistory {
+	mock := &MockinventoryReposistory{ctrl: ctrl}
+	mock.recorder = &MockinventoryReposistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockinventoryReposistory) EXPECT() *MockinventoryReposistoryMockRecorder {
+	return m.recorder
+}
+
+// DecrementStock mocks base method.
+func (m *MockinventoryReposistory) DecrementStock(ctx context.Context, changes []inventory.StockChangeModel) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementStock", ctx, changes)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementStock indicates an expected call of DecrementStock.
+func (mr *MockinventoryReposistoryMockRecorder) DecrementStock(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementStock", reflect.TypeOf((*MockinventoryReposistory)(nil).DecrementStock), ctx, changes)
+}
+
+// IncrementStock mocks base method.
+func (m *MockinventoryReposistory) IncrementStock(ctx context.Context, changes []inventory.StockChangeModel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementStock", ctx, changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementStock indicates an expected call of IncrementStock.
+func (mr *MockinventoryReposistoryMockRecorder) IncrementStock(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementStock", reflect.TypeOf((*MockinventoryReposistory)(nil).IncrementStock), ctx, changes)
+}
+
 // MockorderReposistory is a mock of orderReposistory interface.
 type MockorderReposistory struct {
 	ctrl     *gomock.Controller
@@ -56,10 +186,10 @@ func (mr *MockorderReposistoryMockRecorder) BulkCreateOrderDetail(ctx, req any)
 }
 
 // CreateOrder mocks base method.
-func (m *MockorderReposistory) CreateOrder(ctx context.Context, req order.OrderModel) (int64, error) {
+func (m *MockorderReposistory) CreateOrder(ctx context.Context, req order.OrderModel) (*order.OrderModel, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateOrder", ctx, req)
-	ret0, _ := ret[0].(int64)
+	ret0, _ := ret[0].(*order.OrderModel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -70,19 +200,49 @@ func (mr *MockorderReposistoryMockRecorder) CreateOrder(ctx, req any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockorderReposistory)(nil).CreateOrder), ctx, req)
 }
 
+// CreateStatusHistory mocks base method.
+func (m *MockorderReposistory) CreateStatusHistory(ctx context.Context, req order.OrderStatusHistoryModel) (*order.OrderStatusHistoryModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatusHistory", ctx, req)
+	ret0, _ := ret[0].(*order.OrderStatusHistoryModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStatusHistory indicates an expected call of CreateStatusHistory.
+func (mr *MockorderReposistoryMockRecorder) CreateStatusHistory(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatusHistory", reflect.TypeOf((*MockorderReposistory)(nil).CreateStatusHistory), ctx, req)
+}
+
 // GetAllOrder mocks base method.
-func (m *MockorderReposistory) GetAllOrder(ctx context.Context, userID int64) ([]order.OrderModel, error) {
+func (m *MockorderReposistory) GetAllOrder(ctx context.Context, userID int64, filter order.OrderFilter) ([]order.OrderModel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllOrder", ctx, userID)
+	ret := m.ctrl.Call(m, "GetAllOrder", ctx, userID, filter)
 	ret0, _ := ret[0].([]order.OrderModel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllOrder indicates an expected call of GetAllOrder.
-func (mr *MockorderReposistoryMockRecorder) GetAllOrder(ctx, userID any) *gomock.Call {
+func (mr *MockorderReposistoryMockRecorder) GetAllOrder(ctx, userID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOrder", reflect.TypeOf((*MockorderReposistory)(nil).GetAllOrder), ctx, userID, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockorderReposistory) GetByID(ctx context.Context, orderID int64) (*order.OrderModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, orderID)
+	ret0, _ := ret[0].(*order.OrderModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockorderReposistoryMockRecorder) GetByID(ctx, orderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllOrder", reflect.TypeOf((*MockorderReposistory)(nil).GetAllOrder), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockorderReposistory)(nil).GetByID), ctx, orderID)
 }
 
 // GetOrderDetail mocks base method.
@@ -99,3 +259,64 @@ func (mr *MockorderReposistoryMockRecorder) GetOrderDetail(ctx, userID, orderID
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderDetail", reflect.TypeOf((*MockorderReposistory)(nil).GetOrderDetail), ctx, userID, orderID)
 }
+
+// GetOrderDetailsByOrderIDs mocks base method.
+func (m *MockorderReposistory) GetOrderDetailsByOrderIDs(ctx context.Context, orderIDs []int64) ([]order.OrderDetailModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderDetailsByOrderIDs", ctx, orderIDs)
+	ret0, _ := ret[0].([]order.OrderDetailModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderDetailsByOrderIDs indicates an expected call of GetOrderDetailsByOrderIDs.
+func (mr *MockorderReposistoryMockRecorder) GetOrderDetailsByOrderIDs(ctx, orderIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderDetailsByOrderIDs", reflect.TypeOf((*MockorderReposistory)(nil).GetOrderDetailsByOrderIDs), ctx, orderIDs)
+}
+
+// GetOrderWithDetails mocks base method.
+func (m *MockorderReposistory) GetOrderWithDetails(ctx context.Context, userID, orderID int64) (*order.OrderModel, []order.OrderDetailModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderWithDetails", ctx, userID, orderID)
+	ret0, _ := ret[0].(*order.OrderModel)
+	ret1, _ := ret[1].([]order.OrderDetailModel)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrderWithDetails indicates an expected call of GetOrderWithDetails.
+func (mr *MockorderReposistoryMockRecorder) GetOrderWithDetails(ctx, userID, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderWithDetails", reflect.TypeOf((*MockorderReposistory)(nil).GetOrderWithDetails), ctx, userID, orderID)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockorderReposistory) GetStatusHistory(ctx context.Context, orderID int64) ([]order.OrderStatusHistoryModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]order.OrderStatusHistoryModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockorderReposistoryMockRecorder) GetStatusHistory(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockorderReposistory)(nil).GetStatusHistory), ctx, orderID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockorderReposistory) UpdateStatus(ctx context.Context, orderID int64, from, to string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, orderID, from, to)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockorderReposistoryMockRecorder) UpdateStatus(ctx, orderID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockorderReposistory)(nil).UpdateStatus), ctx, orderID, from, to)
+}
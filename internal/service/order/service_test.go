@@ -2,36 +2,61 @@ package order
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	gomock "go.uber.org/mock/gomock"
 
+	"github.com/erizkiatama/gotu-assignment/internal/model/book"
+	"github.com/erizkiatama/gotu-assignment/internal/model/inventory"
 	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/pagination"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
-func newMock(mockOrderRepo *MockorderReposistory) *service {
-	return New(mockOrderRepo)
+func newMock(mockTransactor *Mocktransactor, mockOrderRepo *MockorderReposistory, mockBookRepo *MockbookReposistory, mockInventoryRepo *MockinventoryReposistory) *service {
+	return New(mockTransactor, mockOrderRepo, mockBookRepo, mockInventoryRepo)
+}
+
+// runInTx runs the unit of work as the transactor would, without a database
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 func Test_service_CreateOrder(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
 	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
 	defer mockCtrl.Finish()
 
-	svc := newMock(orderRepo)
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	userID := int64(1)
+	createdAt := time.Now()
+	books := book.BookModels{
+		{ID: 1, Price: 10000},
+		{ID: 2, Price: 25000},
+	}
 
 	type args struct {
 		userID int64
 		req    order.CreateOrderRequest
 	}
 	tests := []struct {
-		name    string
-		args    args
-		mock    func(args)
-		want    *order.OrderResponse
-		wantErr bool
+		name        string
+		args        args
+		mock        func(args)
+		want        *order.OrderResponse
+		wantErr     bool
+		wantCode    int
+		wantDetails interface{}
 	}{
 		{
 			name: "success",
@@ -41,41 +66,187 @@ func Test_service_CreateOrder(t *testing.T) {
 					Details: []order.CreateOrderDetailRequest{
 						{
 							BookID: 1,
+							Qty:    2,
+						},
+						{
+							BookID: 2,
 							Qty:    1,
-							Price:  10000,
+							Price:  25000,
 						},
 					},
 				},
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(int64(1), nil)
-				orderRepo.EXPECT().BulkCreateOrderDetail(gomock.Any(), gomock.Any()).
-					Return([]order.OrderDetailModel{
-						{
-							ID:      1,
-							OrderID: 1,
-							BookID:  1,
-							Qty:     1,
-							Price:   10000,
-						},
-					}, nil)
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), []inventory.StockChangeModel{
+					{BookID: 1, Qty: 2},
+					{BookID: 2, Qty: 1},
+				}).Return([]int64{1, 2}, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), order.OrderModel{
+					UserID:     1,
+					TotalQty:   3,
+					TotalPrice: 45000,
+				}).Return(&order.OrderModel{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   3,
+					TotalPrice: 45000,
+					Status:     order.StatusPending,
+					CreatedAt:  createdAt,
+				}, nil)
+				orderRepo.EXPECT().BulkCreateOrderDetail(gomock.Any(), []order.OrderDetailModel{
+					{OrderID: 1, BookID: 1, Qty: 2, Price: 20000},
+					{OrderID: 1, BookID: 2, Qty: 1, Price: 25000},
+				}).Return([]order.OrderDetailModel{
+					{ID: 1, OrderID: 1, BookID: 1, Qty: 2, Price: 20000},
+					{ID: 2, OrderID: 1, BookID: 2, Qty: 1, Price: 25000},
+				}, nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), order.OrderStatusHistoryModel{
+					OrderID:  1,
+					ToStatus: order.StatusPending,
+					ActorID:  sql.NullInt64{Int64: 1, Valid: true},
+				}).Return(&order.OrderStatusHistoryModel{
+					ID:        1,
+					OrderID:   1,
+					ToStatus:  order.StatusPending,
+					ActorID:   sql.NullInt64{Int64: 1, Valid: true},
+					CreatedAt: createdAt,
+				}, nil)
 			},
 			want: &order.OrderResponse{
 				ID:         1,
 				UserID:     1,
-				TotalQty:   1,
-				TotalPrice: 10000,
+				TotalQty:   3,
+				TotalPrice: 45000,
+				Status:     order.StatusPending,
+				CreatedAt:  createdAt,
 				Details: []order.OrderDetailResponse{
 					{
 						ID:     1,
 						BookID: 1,
+						Qty:    2,
+						Price:  20000,
+					},
+					{
+						ID:     2,
+						BookID: 2,
 						Qty:    1,
-						Price:  10000,
+						Price:  25000,
+					},
+				},
+				History: []order.OrderStatusHistoryResponse{
+					{
+						Status:    order.StatusPending,
+						ActorID:   &userID,
+						CreatedAt: createdAt,
 					},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "empty order",
+			args: args{
+				userID: 1,
+				req:    order.CreateOrderRequest{},
+			},
+			mock:     func(arg args) {},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid quantity",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    0,
+						},
+					},
+				},
+			},
+			mock:     func(arg args) {},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed to get books",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(nil, errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "unknown or deleted books",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+						{
+							BookID: 3,
+							Qty:    1,
+						},
+						{
+							BookID: 3,
+							Qty:    2,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1, 3}).Return(books[:1], nil)
+			},
+			want:        nil,
+			wantErr:     true,
+			wantCode:    http.StatusBadRequest,
+			wantDetails: order.BookIDsErrorDetails{BookIDs: []int64{3}},
+		},
+		{
+			name: "price changed",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    2,
+							Price:  18000,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+			wantDetails: []order.PriceChangedErrorDetails{
+				{BookID: 1, QuotedPrice: 18000, Price: 20000},
+			},
+		},
 		{
 			name: "failed to create order",
 			args: args{
@@ -91,10 +262,91 @@ func Test_service_CreateOrder(t *testing.T) {
 				},
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any()).Return([]int64{1}, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "out of stock",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+						{
+							BookID: 2,
+							Qty:    1,
+						},
+						{
+							BookID: 2,
+							Qty:    4,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1, 2}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), []inventory.StockChangeModel{
+					{BookID: 1, Qty: 1},
+					{BookID: 2, Qty: 5},
+				}).Return([]int64{1}, nil)
 			},
-			want:    nil,
-			wantErr: true,
+			want:        nil,
+			wantErr:     true,
+			wantCode:    http.StatusConflict,
+			wantDetails: order.BookIDsErrorDetails{BookIDs: []int64{2}},
+		},
+		{
+			name: "failed to decrement stock",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failed to begin transaction",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
 		},
 		{
 			name: "failed to create order detail",
@@ -111,11 +363,41 @@ func Test_service_CreateOrder(t *testing.T) {
 				},
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any()).Return([]int64{1}, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(&order.OrderModel{ID: 1, Status: order.StatusPending}, nil)
 				orderRepo.EXPECT().BulkCreateOrderDetail(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failed to record status history",
+			args: args{
+				userID: 1,
+				req: order.CreateOrderRequest{
+					Details: []order.CreateOrderDetailRequest{
+						{
+							BookID: 1,
+							Qty:    1,
+						},
+					},
+				},
+			},
+			mock: func(arg args) {
+				bookRepo.EXPECT().GetByIDs(gomock.Any(), []int64{1}).Return(books, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				inventoryRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any()).Return([]int64{1}, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Return(&order.OrderModel{ID: 1, Status: order.StatusPending}, nil)
+				orderRepo.EXPECT().BulkCreateOrderDetail(gomock.Any(), gomock.Any()).
+					Return([]order.OrderDetailModel{{ID: 1, OrderID: 1, BookID: 1, Qty: 1, Price: 10000}}, nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
 		},
 	}
 
@@ -126,7 +408,12 @@ func Test_service_CreateOrder(t *testing.T) {
 				tt.mock(tt.args)
 				got, err := svc.CreateOrder(context.Background(), tt.args.userID, tt.args.req)
 				if tt.wantErr {
-					So(err, ShouldNotBeNil)
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+					if tt.wantDetails != nil {
+						So(svcErr.Details, ShouldResemble, tt.wantDetails)
+					}
 				} else {
 					So(err, ShouldBeNil)
 					So(got, ShouldResemble, tt.want)
@@ -138,20 +425,32 @@ func Test_service_CreateOrder(t *testing.T) {
 
 func Test_service_ListOrder(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
 	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
 	defer mockCtrl.Finish()
 
-	svc := newMock(orderRepo)
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	cursor, _ := pagination.EncodeCursor(order.OrderCursor{ID: 2, CreatedAt: createdAt})
 
 	type args struct {
 		userID int64
+		req    order.ListOrderRequest
 	}
 	tests := []struct {
-		name    string
-		args    args
-		mock    func(args)
-		want    []order.OrderResponse
-		wantErr bool
+		name           string
+		args           args
+		mock           func(args)
+		want           []order.OrderResponse
+		wantPagination *response.Pagination
+		wantErr        bool
+		wantCode       int
 	}{
 		{
 			name: "success",
@@ -159,12 +458,15 @@ func Test_service_ListOrder(t *testing.T) {
 				userID: 1,
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().GetAllOrder(gomock.Any(), gomock.Any()).Return([]order.OrderModel{
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), order.OrderFilter{Limit: 21}).Return([]order.OrderModel{
 					{
 						ID:         1,
 						UserID:     1,
 						TotalQty:   1,
 						TotalPrice: 10000,
+						Status:     order.StatusPaid,
+						CreatedAt:  createdAt,
+						UpdatedAt:  sql.NullTime{Time: updatedAt, Valid: true},
 					},
 				}, nil)
 			},
@@ -174,10 +476,149 @@ func Test_service_ListOrder(t *testing.T) {
 					UserID:     1,
 					TotalQty:   1,
 					TotalPrice: 10000,
+					Status:     order.StatusPaid,
+					CreatedAt:  createdAt,
+					UpdatedAt:  &updatedAt,
 					Details:    nil,
 				},
 			},
-			wantErr: false,
+			wantPagination: &response.Pagination{Limit: 20},
+			wantErr:        false,
+		},
+		{
+			name: "success with filters and next page",
+			args: args{
+				userID: 1,
+				req: order.ListOrderRequest{
+					Limit:  1,
+					Status: order.StatusPaid,
+					From:   "2024-01-01",
+					To:     "2024-02-01T17:00:00+07:00",
+					Cursor: cursor,
+				},
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), order.OrderFilter{
+					Status:      order.StatusPaid,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+					After:       &order.OrderCursor{ID: 2, CreatedAt: createdAt},
+					Limit:       2,
+				}).Return([]order.OrderModel{
+					{ID: 1, UserID: 1, Status: order.StatusPaid, CreatedAt: createdAt},
+					{ID: 3, UserID: 1, Status: order.StatusPaid, CreatedAt: from},
+				}, nil)
+			},
+			want: []order.OrderResponse{
+				{ID: 1, UserID: 1, Status: order.StatusPaid, CreatedAt: createdAt},
+			},
+			wantPagination: &response.Pagination{Limit: 1, HasMore: true, NextCursor: mustEncodeCursor(order.OrderCursor{ID: 1, CreatedAt: createdAt})},
+			wantErr:        false,
+		},
+		{
+			name: "success with details",
+			args: args{
+				userID: 1,
+				req:    order.ListOrderRequest{Include: order.IncludeDetails},
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), order.OrderFilter{Limit: 21}).Return([]order.OrderModel{
+					{ID: 2, UserID: 1, TotalQty: 3, TotalPrice: 30000, Status: order.StatusPending, CreatedAt: createdAt},
+					{ID: 1, UserID: 1, TotalQty: 1, TotalPrice: 10000, Status: order.StatusPaid, CreatedAt: from},
+				}, nil)
+				orderRepo.EXPECT().GetOrderDetailsByOrderIDs(gomock.Any(), []int64{2, 1}).Return([]order.OrderDetailModel{
+					{ID: 2, OrderID: 2, BookID: 1, Qty: 1, Price: 10000, BookTitle: "title 1", BookAuthor: "author 1"},
+					{ID: 3, OrderID: 2, BookID: 2, Qty: 2, Price: 20000, BookTitle: "title 2", BookAuthor: "author 2"},
+				}, nil)
+			},
+			want: []order.OrderResponse{
+				{
+					ID:         2,
+					UserID:     1,
+					TotalQty:   3,
+					TotalPrice: 30000,
+					Status:     order.StatusPending,
+					CreatedAt:  createdAt,
+					Details: []order.OrderDetailResponse{
+						{ID: 2, BookID: 1, BookTitle: "title 1", BookAuthor: "author 1", Qty: 1, Price: 10000},
+						{ID: 3, BookID: 2, BookTitle: "title 2", BookAuthor: "author 2", Qty: 2, Price: 20000},
+					},
+				},
+				{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 10000,
+					Status:     order.StatusPaid,
+					CreatedAt:  from,
+					Details:    []order.OrderDetailResponse{},
+				},
+			},
+			wantPagination: &response.Pagination{Limit: 20},
+			wantErr:        false,
+		},
+		{
+			name: "success with details and no orders",
+			args: args{
+				userID: 1,
+				req:    order.ListOrderRequest{Include: order.IncludeDetails},
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), order.OrderFilter{Limit: 21}).Return(nil, nil)
+			},
+			want:           []order.OrderResponse{},
+			wantPagination: &response.Pagination{Limit: 20},
+			wantErr:        false,
+		},
+		{
+			name:     "unknown include",
+			args:     args{userID: 1, req: order.ListOrderRequest{Include: "books"}},
+			mock:     func(arg args) {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed to get details",
+			args: args{
+				userID: 1,
+				req:    order.ListOrderRequest{Include: order.IncludeDetails},
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), order.OrderFilter{Limit: 21}).Return([]order.OrderModel{
+					{ID: 1, UserID: 1, Status: order.StatusPaid, CreatedAt: createdAt},
+				}, nil)
+				orderRepo.EXPECT().GetOrderDetailsByOrderIDs(gomock.Any(), []int64{1}).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "unknown status",
+			args:     args{userID: 1, req: order.ListOrderRequest{Status: "shipped"}},
+			mock:     func(arg args) {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid date",
+			args:     args{userID: 1, req: order.ListOrderRequest{From: "01-01-2024"}},
+			mock:     func(arg args) {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "from is not before to",
+			args:     args{userID: 1, req: order.ListOrderRequest{From: "2024-02-01", To: "2024-01-01"}},
+			mock:     func(arg args) {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid cursor",
+			args:     args{userID: 1, req: order.ListOrderRequest{Cursor: "invalid"}},
+			mock:     func(arg args) {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
 		},
 		{
 			name: "failed to list order",
@@ -185,10 +626,11 @@ func Test_service_ListOrder(t *testing.T) {
 				userID: 1,
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().GetAllOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				orderRepo.EXPECT().GetAllOrder(gomock.Any(), int64(1), gomock.Any()).Return(nil, errors.New("error"))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
 		},
 	}
 
@@ -197,35 +639,53 @@ func Test_service_ListOrder(t *testing.T) {
 			tt := tt
 			Convey(tt.name, func() {
 				tt.mock(tt.args)
-				got, err := svc.ListOrder(context.Background(), tt.args.userID)
+				got, pg, err := svc.ListOrder(context.Background(), tt.args.userID, tt.args.req)
 				if tt.wantErr {
-					So(err, ShouldNotBeNil)
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
 				} else {
 					So(err, ShouldBeNil)
 					So(got, ShouldResemble, tt.want)
+					So(pg, ShouldResemble, tt.wantPagination)
 				}
 			})
 		}
 	})
 }
 
+func mustEncodeCursor(v interface{}) string {
+	cursor, err := pagination.EncodeCursor(v)
+	if err != nil {
+		panic(err)
+	}
+	return cursor
+}
+
 func Test_service_DetailOrder(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
 	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
 	defer mockCtrl.Finish()
 
-	svc := newMock(orderRepo)
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	createdAt := time.Now()
+	updatedAt := createdAt.Add(time.Hour)
 
 	type args struct {
 		userID  int64
 		orderID int64
 	}
 	tests := []struct {
-		name    string
-		args    args
-		mock    func(args)
-		want    *order.OrderResponse
-		wantErr bool
+		name     string
+		args     args
+		mock     func(args)
+		want     *order.OrderResponse
+		wantErr  bool
+		wantCode int
 	}{
 		{
 			name: "success",
@@ -234,13 +694,33 @@ func Test_service_DetailOrder(t *testing.T) {
 				orderID: 1,
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().GetOrderDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return([]order.OrderDetailModel{
+				orderRepo.EXPECT().GetOrderWithDetails(gomock.Any(), int64(1), int64(1)).Return(&order.OrderModel{
+					ID:         1,
+					UserID:     1,
+					TotalQty:   1,
+					TotalPrice: 10000,
+					Status:     order.StatusPaid,
+					CreatedAt:  createdAt,
+					UpdatedAt:  sql.NullTime{Time: updatedAt, Valid: true},
+				}, []order.OrderDetailModel{
 					{
-						ID:      1,
-						OrderID: 1,
-						BookID:  1,
-						Qty:     1,
-						Price:   10000,
+						ID:         1,
+						OrderID:    1,
+						BookID:     1,
+						Qty:        1,
+						Price:      10000,
+						BookTitle:  "title",
+						BookAuthor: "author",
+					},
+				}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return([]order.OrderStatusHistoryModel{
+					{ID: 1, OrderID: 1, ToStatus: order.StatusPending, CreatedAt: createdAt},
+					{
+						ID:         2,
+						OrderID:    1,
+						FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+						ToStatus:   order.StatusPaid,
+						CreatedAt:  createdAt,
 					},
 				}, nil)
 			},
@@ -249,14 +729,52 @@ func Test_service_DetailOrder(t *testing.T) {
 				UserID:     1,
 				TotalQty:   1,
 				TotalPrice: 10000,
+				Status:     order.StatusPaid,
+				CreatedAt:  createdAt,
+				UpdatedAt:  &updatedAt,
 				Details: []order.OrderDetailResponse{
 					{
-						ID:     1,
-						BookID: 1,
-						Qty:    1,
-						Price:  10000,
+						ID:         1,
+						BookID:     1,
+						BookTitle:  "title",
+						BookAuthor: "author",
+						Qty:        1,
+						Price:      10000,
 					},
 				},
+				History: []order.OrderStatusHistoryResponse{
+					{Status: order.StatusPending, CreatedAt: createdAt},
+					{FromStatus: order.StatusPending, Status: order.StatusPaid, CreatedAt: createdAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success without lines",
+			args: args{
+				userID:  1,
+				orderID: 1,
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetOrderWithDetails(gomock.Any(), int64(1), int64(1)).Return(&order.OrderModel{
+					ID:        1,
+					UserID:    1,
+					Status:    order.StatusPending,
+					CreatedAt: createdAt,
+				}, []order.OrderDetailModel{}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return([]order.OrderStatusHistoryModel{
+					{ID: 1, OrderID: 1, ToStatus: order.StatusPending, CreatedAt: createdAt},
+				}, nil)
+			},
+			want: &order.OrderResponse{
+				ID:        1,
+				UserID:    1,
+				Status:    order.StatusPending,
+				CreatedAt: createdAt,
+				Details:   []order.OrderDetailResponse{},
+				History: []order.OrderStatusHistoryResponse{
+					{Status: order.StatusPending, CreatedAt: createdAt},
+				},
 			},
 			wantErr: false,
 		},
@@ -267,10 +785,26 @@ func Test_service_DetailOrder(t *testing.T) {
 				orderID: 1,
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().GetOrderDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				orderRepo.EXPECT().GetOrderWithDetails(gomock.Any(), int64(1), int64(1)).Return(nil, nil, errors.New("error"))
+			},
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failed to get status history",
+			args: args{
+				userID:  1,
+				orderID: 1,
+			},
+			mock: func(arg args) {
+				orderRepo.EXPECT().GetOrderWithDetails(gomock.Any(), int64(1), int64(1)).Return(&order.OrderModel{ID: 1, UserID: 1, Status: order.StatusPending},
+					[]order.OrderDetailModel{{ID: 1, OrderID: 1, BookID: 1, Qty: 1, Price: 10000}}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return(nil, errors.New("error"))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
 		},
 		{
 			name: "no order found",
@@ -279,10 +813,11 @@ func Test_service_DetailOrder(t *testing.T) {
 				orderID: 1,
 			},
 			mock: func(arg args) {
-				orderRepo.EXPECT().GetOrderDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				orderRepo.EXPECT().GetOrderWithDetails(gomock.Any(), int64(1), int64(1)).Return(nil, nil, fmt.Errorf("error: %w", sql.ErrNoRows))
 			},
-			want:    nil,
-			wantErr: true,
+			want:     nil,
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
 		},
 	}
 
@@ -293,7 +828,443 @@ func Test_service_DetailOrder(t *testing.T) {
 				tt.mock(tt.args)
 				got, err := svc.DetailOrder(context.Background(), tt.args.userID, tt.args.orderID)
 				if tt.wantErr {
-					So(err, ShouldNotBeNil)
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_UpdateStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	adminID := int64(9)
+	createdAt := time.Now()
+	updatedAt := createdAt.Add(time.Hour)
+	paid := func() *order.OrderModel {
+		return &order.OrderModel{ID: 1, UserID: 1, TotalQty: 1, TotalPrice: 10000, Status: order.StatusPaid}
+	}
+
+	tests := []struct {
+		name     string
+		req      order.UpdateOrderStatusRequest
+		mock     func()
+		want     *order.OrderResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusFulfilled, Reason: " shipped "},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{
+					ID: 1, UserID: 1, TotalQty: 1, TotalPrice: 10000, Status: order.StatusPaid,
+				}, nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPaid, order.StatusFulfilled).Return(updatedAt, nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), order.OrderStatusHistoryModel{
+					OrderID:    1,
+					FromStatus: sql.NullString{String: order.StatusPaid, Valid: true},
+					ToStatus:   order.StatusFulfilled,
+					ActorID:    sql.NullInt64{Int64: adminID, Valid: true},
+					Reason:     sql.NullString{String: "shipped", Valid: true},
+				}).Return(&order.OrderStatusHistoryModel{ID: 3}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return([]order.OrderStatusHistoryModel{
+					{
+						ID:         3,
+						OrderID:    1,
+						FromStatus: sql.NullString{String: order.StatusPaid, Valid: true},
+						ToStatus:   order.StatusFulfilled,
+						ActorID:    sql.NullInt64{Int64: adminID, Valid: true},
+						Reason:     sql.NullString{String: "shipped", Valid: true},
+						CreatedAt:  createdAt,
+					},
+				}, nil)
+			},
+			want: &order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 10000,
+				Status:     order.StatusFulfilled,
+				UpdatedAt:  &updatedAt,
+				History: []order.OrderStatusHistoryResponse{
+					{
+						FromStatus: order.StatusPaid,
+						Status:     order.StatusFulfilled,
+						ActorID:    &adminID,
+						Reason:     "shipped",
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+		},
+		{
+			name: "order not found",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusFulfilled},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "unknown status",
+			req:  order.UpdateOrderStatusRequest{Status: "shipped"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(paid(), nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "illegal transition",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusCancelled},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(paid(), nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "status changed concurrently",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusFulfilled},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(paid(), nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPaid, order.StatusFulfilled).
+					Return(time.Time{}, fmt.Errorf("not paid: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "paid is set by the payment",
+			req:      order.UpdateOrderStatusRequest{Status: order.StatusPaid},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "refunded is set by the payment",
+			req:      order.UpdateOrderStatusRequest{Status: order.StatusRefunded},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed to record status history",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusFulfilled},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(paid(), nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPaid, order.StatusFulfilled).Return(updatedAt, nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Order Service - UpdateStatus", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.UpdateStatus(context.Background(), adminID, 1, tt.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_UpdatePaymentStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	createdAt := time.Now()
+	updatedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		req      order.UpdateOrderStatusRequest
+		mock     func()
+		want     *order.OrderResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			req:  order.UpdateOrderStatusRequest{Status: order.StatusPaid, Reason: "paid with fake charge ch_fake_1"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{
+					ID: 1, UserID: 1, TotalQty: 1, TotalPrice: 10000, Status: order.StatusPending,
+				}, nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPending, order.StatusPaid).Return(updatedAt, nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), order.OrderStatusHistoryModel{
+					OrderID:    1,
+					FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+					ToStatus:   order.StatusPaid,
+					Reason:     sql.NullString{String: "paid with fake charge ch_fake_1", Valid: true},
+				}).Return(&order.OrderStatusHistoryModel{ID: 2}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return([]order.OrderStatusHistoryModel{
+					{
+						ID:         2,
+						OrderID:    1,
+						FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+						ToStatus:   order.StatusPaid,
+						Reason:     sql.NullString{String: "paid with fake charge ch_fake_1", Valid: true},
+						CreatedAt:  createdAt,
+					},
+				}, nil)
+			},
+			want: &order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   1,
+				TotalPrice: 10000,
+				Status:     order.StatusPaid,
+				UpdatedAt:  &updatedAt,
+				History: []order.OrderStatusHistoryResponse{
+					{
+						FromStatus: order.StatusPending,
+						Status:     order.StatusPaid,
+						Reason:     "paid with fake charge ch_fake_1",
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+		},
+		{
+			name:     "status not set by payments",
+			req:      order.UpdateOrderStatusRequest{Status: order.StatusFulfilled},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	Convey("Test Order Service - UpdatePaymentStatus", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.UpdatePaymentStatus(context.Background(), 0, 1, tt.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_canTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{order.StatusPending, order.StatusPaid, true},
+		{order.StatusPending, order.StatusCancelled, true},
+		{order.StatusPending, order.StatusFulfilled, false},
+		{order.StatusPaid, order.StatusFulfilled, true},
+		{order.StatusPaid, order.StatusRefunded, true},
+		{order.StatusPaid, order.StatusCancelled, false},
+		{order.StatusFulfilled, order.StatusCompleted, true},
+		{order.StatusCompleted, order.StatusRefunded, true},
+		{order.StatusCompleted, order.StatusPending, false},
+		{order.StatusCancelled, order.StatusPaid, false},
+		{order.StatusRefunded, order.StatusPaid, false},
+		{order.StatusPaid, order.StatusPaid, false},
+	}
+
+	Convey("Test Order Status - canTransition", t, func() {
+		for _, tt := range tests {
+			So(canTransition(tt.from, tt.to), ShouldEqual, tt.want)
+		}
+	})
+}
+
+func Test_service_CancelOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	bookRepo := NewMockbookReposistory(mockCtrl)
+	inventoryRepo := NewMockinventoryReposistory(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, orderRepo, bookRepo, inventoryRepo)
+
+	userID := int64(1)
+	createdAt := time.Now()
+	updatedAt := createdAt.Add(time.Hour)
+	pending := func() *order.OrderModel {
+		return &order.OrderModel{ID: 1, UserID: 1, TotalQty: 3, TotalPrice: 30000, Status: order.StatusPending}
+	}
+	details := []order.OrderDetailModel{
+		{ID: 1, OrderID: 1, BookID: 1, Qty: 1, Price: 10000},
+		{ID: 2, OrderID: 1, BookID: 2, Qty: 1, Price: 10000},
+		{ID: 3, OrderID: 1, BookID: 1, Qty: 1, Price: 10000},
+	}
+
+	tests := []struct {
+		name     string
+		req      order.CancelOrderRequest
+		mock     func()
+		want     *order.OrderResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			req:  order.CancelOrderRequest{Reason: " changed my mind "},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{
+					ID: 1, UserID: 1, TotalQty: 3, TotalPrice: 30000, Status: order.StatusPending,
+				}, nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPending, order.StatusCancelled).Return(updatedAt, nil)
+				orderRepo.EXPECT().GetOrderDetail(gomock.Any(), userID, int64(1)).Return(details, nil)
+				inventoryRepo.EXPECT().IncrementStock(gomock.Any(), []inventory.StockChangeModel{
+					{BookID: 1, Qty: 2},
+					{BookID: 2, Qty: 1},
+				}).Return(nil)
+				orderRepo.EXPECT().CreateStatusHistory(gomock.Any(), order.OrderStatusHistoryModel{
+					OrderID:    1,
+					FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+					ToStatus:   order.StatusCancelled,
+					ActorID:    sql.NullInt64{Int64: userID, Valid: true},
+					Reason:     sql.NullString{String: "changed my mind", Valid: true},
+				}).Return(&order.OrderStatusHistoryModel{ID: 2}, nil)
+				orderRepo.EXPECT().GetStatusHistory(gomock.Any(), int64(1)).Return([]order.OrderStatusHistoryModel{
+					{
+						ID:         2,
+						OrderID:    1,
+						FromStatus: sql.NullString{String: order.StatusPending, Valid: true},
+						ToStatus:   order.StatusCancelled,
+						ActorID:    sql.NullInt64{Int64: userID, Valid: true},
+						Reason:     sql.NullString{String: "changed my mind", Valid: true},
+						CreatedAt:  createdAt,
+					},
+				}, nil)
+			},
+			want: &order.OrderResponse{
+				ID:         1,
+				UserID:     1,
+				TotalQty:   3,
+				TotalPrice: 30000,
+				Status:     order.StatusCancelled,
+				UpdatedAt:  &updatedAt,
+				History: []order.OrderStatusHistoryResponse{
+					{
+						FromStatus: order.StatusPending,
+						Status:     order.StatusCancelled,
+						ActorID:    &userID,
+						Reason:     "changed my mind",
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+		},
+		{
+			name:     "reason is empty",
+			req:      order.CancelOrderRequest{Reason: " "},
+			mock:     func() {},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "order not found",
+			req:  order.CancelOrderRequest{Reason: "changed my mind"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "order of another user",
+			req:  order.CancelOrderRequest{Reason: "changed my mind"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{
+					ID: 1, UserID: 2, Status: order.StatusPending,
+				}, nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "order is not pending",
+			req:  order.CancelOrderRequest{Reason: "changed my mind"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{
+					ID: 1, UserID: 1, Status: order.StatusPaid,
+				}, nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "failed to restore stock",
+			req:  order.CancelOrderRequest{Reason: "changed my mind"},
+			mock: func() {
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending(), nil)
+				orderRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), order.StatusPending, order.StatusCancelled).Return(updatedAt, nil)
+				orderRepo.EXPECT().GetOrderDetail(gomock.Any(), userID, int64(1)).Return(details, nil)
+				inventoryRepo.EXPECT().IncrementStock(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Order Service - CancelOrder", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.CancelOrder(context.Background(), userID, 1, tt.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
 				} else {
 					So(err, ShouldBeNil)
 					So(got, ShouldResemble, tt.want)
@@ -0,0 +1,159 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+)
+
+// transitions is the order state machine, it lists the statuses an order can move to from each
+// status. An order is placed as pending and moves on through paid and fulfilled until completed,
+// it can be cancelled before it is paid and refunded once it is.
+var transitions = map[string][]string{
+	order.StatusPending:   {order.StatusPaid, order.StatusCancelled},
+	order.StatusPaid:      {order.StatusFulfilled, order.StatusRefunded},
+	order.StatusFulfilled: {order.StatusCompleted, order.StatusRefunded},
+	order.StatusCompleted: {order.StatusRefunded},
+	order.StatusCancelled: {},
+	order.StatusRefunded:  {},
+}
+
+// isPaymentStatus tells whether only the payment of an order can move it to the status, so the
+// order always matches the charge made for it
+func isPaymentStatus(status string) bool {
+	return status == order.StatusPaid || status == order.StatusRefunded
+}
+
+func isValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+func canTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transition moves the order to the given status, updating o, and records the change in the status
+// history. The stock reserved by the order is given back when it is cancelled. An actorID of zero
+// records the change as made by the system. It must run in a transaction.
+func (s *service) transition(ctx context.Context, o *order.OrderModel, to string, actorID int64, reason string) (*order.OrderStatusHistoryModel, error) {
+	if !isValidStatus(to) {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidOrderStatus,
+			Msg:       constant.ErrorInvalidOrderStatus,
+			Err:       fmt.Errorf("[OrderSvc.transition] unknown status %q", to),
+		}
+	}
+
+	if !canTransition(o.Status, to) {
+		return nil, &response.ServiceError{
+			Code:      http.StatusConflict,
+			ErrorCode: constant.CodeInvalidOrderStatusTransition,
+			Msg:       constant.ErrorInvalidOrderStatusTransition,
+			Err:       fmt.Errorf("[OrderSvc.transition] order %d cannot move from %s to %s", o.ID, o.Status, to),
+		}
+	}
+
+	updatedAt, err := s.orderRepo.UpdateStatus(ctx, o.ID, o.Status, to)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeOrderStatusChanged,
+				Msg:       constant.ErrorOrderStatusChanged,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateOrderStatusFailed,
+			Msg:       constant.ErrorUpdateOrderStatusFailed,
+			Err:       err,
+		}
+	}
+
+	if to == order.StatusCancelled {
+		if err := s.restoreStock(ctx, *o); err != nil {
+			return nil, err
+		}
+	}
+
+	from := o.Status
+	o.Status = to
+	o.UpdatedAt = sql.NullTime{Time: updatedAt, Valid: true}
+
+	history, err := s.orderRepo.CreateStatusHistory(ctx, order.OrderStatusHistoryModel{
+		OrderID:    o.ID,
+		FromStatus: sql.NullString{String: from, Valid: true},
+		ToStatus:   to,
+		ActorID:    sql.NullInt64{Int64: actorID, Valid: actorID != 0},
+		Reason:     sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateOrderStatusFailed,
+			Msg:       constant.ErrorUpdateOrderStatusFailed,
+			Err:       err,
+		}
+	}
+
+	return history, nil
+}
+
+// restoreStock gives the quantities reserved by the order back to the stock of its books
+func (s *service) restoreStock(ctx context.Context, o order.OrderModel) error {
+	details, err := s.orderRepo.GetOrderDetail(ctx, o.UserID, o.ID)
+	if err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateOrderStatusFailed,
+			Msg:       constant.ErrorUpdateOrderStatusFailed,
+			Err:       err,
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	if err := s.inventoryRepo.IncrementStock(ctx, stockChanges(details)); err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeUpdateOrderStatusFailed,
+			Msg:       constant.ErrorUpdateOrderStatusFailed,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+func toStatusHistoryResponses(histories []order.OrderStatusHistoryModel) []order.OrderStatusHistoryResponse {
+	res := make([]order.OrderStatusHistoryResponse, len(histories))
+	for i, h := range histories {
+		res[i] = order.OrderStatusHistoryResponse{
+			FromStatus: h.FromStatus.String,
+			Status:     h.ToStatus,
+			Reason:     h.Reason.String,
+			CreatedAt:  h.CreatedAt,
+		}
+		if h.ActorID.Valid {
+			actorID := h.ActorID.Int64
+			res[i].ActorID = &actorID
+		}
+	}
+	return res
+}
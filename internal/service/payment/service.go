@@ -0,0 +1,448 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	gateway "github.com/erizkiatama/gotu-assignment/internal/pkg/payment"
+)
+
+// currency of the book prices, amounts are charged in rupiah
+const currency = "IDR"
+
+// activePaymentIndex is the unique index allowing one pending or succeeded payment per order
+const activePaymentIndex = "uq_payments_order_id_active"
+
+//go:generate mockgen -source=service.go -package=payment -destination=service_mock_test.go
+type transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type paymentGateway interface {
+	Name() string
+	CreateCharge(ctx context.Context, req gateway.ChargeRequest) (*gateway.Charge, error)
+	Capture(ctx context.Context, chargeID string) (*gateway.Charge, error)
+	Refund(ctx context.Context, chargeID string, amount int64) (*gateway.Charge, error)
+	ParseWebhook(header http.Header, body []byte) (*gateway.Event, error)
+}
+
+type paymentReposistory interface {
+	Create(ctx context.Context, req payment.PaymentModel) (*payment.PaymentModel, error)
+	UpdateChargeID(ctx context.Context, id int64, chargeID string) error
+	GetByChargeID(ctx context.Context, provider, chargeID string) (*payment.PaymentModel, error)
+	GetByOrderID(ctx context.Context, orderID int64, status string) (*payment.PaymentModel, error)
+	UpdateStatus(ctx context.Context, id int64, from, to string) error
+}
+
+type orderReposistory interface {
+	GetByID(ctx context.Context, orderID int64) (*order.OrderModel, error)
+}
+
+// orderService moves the orders through their statuses, payments never change an order directly
+type orderService interface {
+	UpdatePaymentStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error)
+	DetailOrder(ctx context.Context, userID, orderID int64) (*order.OrderResponse, error)
+}
+
+type service struct {
+	transactor  transactor
+	gateway     paymentGateway
+	paymentRepo paymentReposistory
+	orderRepo   orderReposistory
+	orderSvc    orderService
+}
+
+func New(transactor transactor, gateway paymentGateway, paymentRepo paymentReposistory, orderRepo orderReposistory, orderSvc orderService) *service {
+	return &service{
+		transactor:  transactor,
+		gateway:     gateway,
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		orderSvc:    orderSvc,
+	}
+}
+
+// CreatePayment charges the total price of a pending order of the user. The order is paid once the
+// gateway notifies the charge has succeeded. The payment is reserved before the charge is made, so
+// an order cannot be charged twice by concurrent requests.
+func (s *service) CreatePayment(ctx context.Context, userID, orderID int64) (*payment.PaymentResponse, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeOrderNotFound,
+				Msg:       constant.ErrorOrderNotFound,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreatePaymentFailed,
+			Msg:       constant.ErrorCreatePaymentFailed,
+			Err:       err,
+		}
+	}
+
+	if o.UserID != userID {
+		return nil, &response.ServiceError{
+			Code:      http.StatusNotFound,
+			ErrorCode: constant.CodeOrderNotFound,
+			Msg:       constant.ErrorOrderNotFound,
+			Err:       fmt.Errorf("[PaymentSvc.CreatePayment] order %d does not belong to user %d", orderID, userID),
+		}
+	}
+
+	if o.Status != order.StatusPending {
+		return nil, &response.ServiceError{
+			Code:      http.StatusConflict,
+			ErrorCode: constant.CodeOrderNotPayable,
+			Msg:       constant.ErrorOrderNotPayable,
+			Err:       fmt.Errorf("[PaymentSvc.CreatePayment] order %d is %s", orderID, o.Status),
+		}
+	}
+
+	p, err := s.paymentRepo.Create(ctx, payment.PaymentModel{
+		OrderID:  o.ID,
+		Provider: s.gateway.Name(),
+		Amount:   o.TotalPrice,
+		Currency: currency,
+		Status:   payment.StatusPending,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrUniqueViolation) && db.Constraint(err) == activePaymentIndex {
+			return nil, &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodePaymentInProgress,
+				Msg:       constant.ErrorPaymentInProgress,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreatePaymentFailed,
+			Msg:       constant.ErrorCreatePaymentFailed,
+			Err:       err,
+		}
+	}
+
+	charge, err := s.gateway.CreateCharge(ctx, gateway.ChargeRequest{
+		Reference: fmt.Sprintf("order-%d", o.ID),
+		Amount:    o.TotalPrice,
+		Currency:  currency,
+	})
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadGateway,
+			ErrorCode: constant.CodePaymentGatewayFailed,
+			Msg:       constant.ErrorPaymentGatewayFailed,
+			Err:       s.release(ctx, *p, "", err),
+		}
+	}
+
+	if err := s.paymentRepo.UpdateChargeID(ctx, p.ID, charge.ID); err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeCreatePaymentFailed,
+			Msg:       constant.ErrorCreatePaymentFailed,
+			Err:       s.release(ctx, *p, charge.ID, err),
+		}
+	}
+	p.ChargeID = charge.ID
+
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
+
+// release fails a reserved payment whose charge could not be made or recorded, so the order can be
+// paid again. A charge that was made is voided first; when it cannot be, the payment stays reserved
+// so the order is not charged twice. It returns cause along with the errors of the release.
+func (s *service) release(ctx context.Context, p payment.PaymentModel, chargeID string, cause error) error {
+	// The request may have been canceled, the payment still has to be released
+	ctx = context.WithoutCancel(ctx)
+
+	if chargeID != "" {
+		if _, err := s.gateway.Refund(ctx, chargeID, p.Amount); err != nil {
+			return errors.Join(cause, fmt.Errorf("[PaymentSvc.release] failed to void charge %s of payment %d: %w", chargeID, p.ID, err))
+		}
+	}
+
+	if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusPending, payment.StatusFailed); err != nil {
+		return errors.Join(cause, fmt.Errorf("[PaymentSvc.release] failed to release payment %d: %w", p.ID, err))
+	}
+
+	return cause
+}
+
+// HandleWebhook applies a notification of the gateway to its payment, see settle for succeeded charges.
+// Authorized charges are captured. Notifications already applied are ignored.
+func (s *service) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	event, err := s.gateway.ParseWebhook(header, body)
+	if err != nil {
+		if errors.Is(err, gateway.ErrInvalidSignature) {
+			return &response.ServiceError{
+				Code:      http.StatusUnauthorized,
+				ErrorCode: constant.CodeInvalidWebhookSignature,
+				Msg:       constant.ErrorInvalidWebhookSignature,
+				Err:       err,
+			}
+		}
+		return &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodeInvalidWebhookEvent,
+			Msg:       constant.ErrorInvalidWebhookEvent,
+			Err:       err,
+		}
+	}
+
+	p, err := s.paymentRepo.GetByChargeID(ctx, s.gateway.Name(), event.ChargeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodePaymentNotFound,
+				Msg:       constant.ErrorPaymentNotFound,
+				Err:       err,
+			}
+		}
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeHandleWebhookFailed,
+			Msg:       constant.ErrorHandleWebhookFailed,
+			Err:       err,
+		}
+	}
+
+	switch event.Type {
+	case gateway.EventChargeAuthorized:
+		if p.Status != payment.StatusPending {
+			return nil
+		}
+		if _, err := s.gateway.Capture(ctx, p.ChargeID); err != nil {
+			return &response.ServiceError{
+				Code:      http.StatusBadGateway,
+				ErrorCode: constant.CodePaymentGatewayFailed,
+				Msg:       constant.ErrorPaymentGatewayFailed,
+				Err:       err,
+			}
+		}
+		return nil
+	case gateway.EventChargeSucceeded:
+		return s.settle(ctx, *p)
+	case gateway.EventChargeFailed:
+		if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusPending, payment.StatusFailed); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeHandleWebhookFailed,
+				Msg:       constant.ErrorHandleWebhookFailed,
+				Err:       err,
+			}
+		}
+		return nil
+	default:
+		// Gateways notify more events than the ones payments care about
+		return nil
+	}
+}
+
+// settle marks the payment as succeeded and moves its order to paid in one transaction. A charge that
+// succeeds once its order is no longer pending, e.g. cancelled while it was being charged, is refunded
+// instead. A payment already refunding had its refund interrupted, the refund is resumed.
+func (s *service) settle(ctx context.Context, p payment.PaymentModel) error {
+	refund := p.Status == payment.StatusRefunding
+	if !refund {
+		err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			o, err := s.orderRepo.GetByID(ctx, p.OrderID)
+			if err != nil {
+				return err
+			}
+
+			if o.Status != order.StatusPending {
+				if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusPending, payment.StatusRefunding); err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						return nil
+					}
+					return err
+				}
+				refund = true
+				return nil
+			}
+
+			if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusPending, payment.StatusSucceeded); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil
+				}
+				return err
+			}
+
+			_, err = s.orderSvc.UpdatePaymentStatus(ctx, 0, p.OrderID, order.UpdateOrderStatusRequest{
+				Status: order.StatusPaid,
+				Reason: fmt.Sprintf("paid with %s charge %s", p.Provider, p.ChargeID),
+			})
+			return err
+		})
+		if err != nil {
+			var svcErr *response.ServiceError
+			if errors.As(err, &svcErr) {
+				return svcErr
+			}
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeHandleWebhookFailed,
+				Msg:       constant.ErrorHandleWebhookFailed,
+				Err:       err,
+			}
+		}
+	}
+
+	if !refund {
+		return nil
+	}
+
+	// A failed refund is retried when the gateway delivers the notification again
+	if err := s.refund(ctx, p); err != nil {
+		var svcErr *response.ServiceError
+		if errors.As(err, &svcErr) {
+			return svcErr
+		}
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeHandleWebhookFailed,
+			Msg:       constant.ErrorHandleWebhookFailed,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// RefundPayment refunds the settled payment of the order through its gateway and moves the order
+// to refunded on behalf of an admin. The refund is committed before the gateway is called, a refund
+// interrupted by a failure of the gateway is resumed by refunding the order again.
+func (s *service) RefundPayment(ctx context.Context, actorID, orderID int64, req payment.RefundPaymentRequest) (*order.OrderResponse, error) {
+	p, err := s.paymentRepo.GetByOrderID(ctx, orderID, payment.StatusSucceeded)
+	if errors.Is(err, sql.ErrNoRows) {
+		p, err = s.paymentRepo.GetByOrderID(ctx, orderID, payment.StatusRefunding)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodePaymentNotRefundable,
+				Msg:       constant.ErrorPaymentNotRefundable,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRefundPaymentFailed,
+			Msg:       constant.ErrorRefundPaymentFailed,
+			Err:       err,
+		}
+	}
+
+	var res *order.OrderResponse
+	if p.Status == payment.StatusSucceeded {
+		err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusSucceeded, payment.StatusRefunding); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return &response.ServiceError{
+						Code:      http.StatusConflict,
+						ErrorCode: constant.CodePaymentNotRefundable,
+						Msg:       constant.ErrorPaymentNotRefundable,
+						Err:       err,
+					}
+				}
+				return err
+			}
+
+			var err error
+			res, err = s.orderSvc.UpdatePaymentStatus(ctx, actorID, orderID, order.UpdateOrderStatusRequest{
+				Status: order.StatusRefunded,
+				Reason: req.Reason,
+			})
+			return err
+		})
+		if err != nil {
+			var svcErr *response.ServiceError
+			if errors.As(err, &svcErr) {
+				return nil, svcErr
+			}
+			return nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeRefundPaymentFailed,
+				Msg:       constant.ErrorRefundPaymentFailed,
+				Err:       err,
+			}
+		}
+	}
+
+	if err := s.refund(ctx, *p); err != nil {
+		var svcErr *response.ServiceError
+		if errors.As(err, &svcErr) {
+			return nil, svcErr
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRefundPaymentFailed,
+			Msg:       constant.ErrorRefundPaymentFailed,
+			Err:       err,
+		}
+	}
+
+	if res != nil {
+		return res, nil
+	}
+
+	// The order was moved by the interrupted refund
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRefundPaymentFailed,
+			Msg:       constant.ErrorRefundPaymentFailed,
+			Err:       err,
+		}
+	}
+	return s.orderSvc.DetailOrder(ctx, o.UserID, orderID)
+}
+
+// refund returns the charge of a refunding payment through its gateway and marks the payment refunded.
+// The gateway is only called once the refunding status is committed, never inside a transaction.
+func (s *service) refund(ctx context.Context, p payment.PaymentModel) error {
+	if _, err := s.gateway.Refund(ctx, p.ChargeID, p.Amount); err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusBadGateway,
+			ErrorCode: constant.CodePaymentGatewayFailed,
+			Msg:       constant.ErrorPaymentGatewayFailed,
+			Err:       err,
+		}
+	}
+
+	if err := s.paymentRepo.UpdateStatus(ctx, p.ID, payment.StatusRefunding, payment.StatusRefunded); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+func toPaymentResponse(p payment.PaymentModel) payment.PaymentResponse {
+	return payment.PaymentResponse{
+		ID:        p.ID,
+		OrderID:   p.OrderID,
+		Provider:  p.Provider,
+		ChargeID:  p.ChargeID,
+		Amount:    p.Amount,
+		Currency:  p.Currency,
+		Status:    p.Status,
+		CreatedAt: p.CreatedAt,
+	}
+}
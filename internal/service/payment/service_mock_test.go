@@ -0,0 +1,342 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -package=payment -destination=service_mock_test.go
+//
+
+// Package payment is a generated GoMock package.
+package payment
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	order "github.com/erizkiatama/gotu-assignment/internal/model/order"
+	payment "github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	payment0 "github.com/erizkiatama/gotu-assignment/internal/pkg/payment"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mocktransactor is a mock of transactor interface.
+type Mocktransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransactorMockRecorder
+}
+
+// MocktransactorMockRecorder is the mock recorder for Mocktransactor.
+type MocktransactorMockRecorder struct {
+	mock *Mocktransactor
+}
+
+// NewMocktransactor creates a new mock instance.
+func NewMocktransactor(ctrl *gomock.Controller) *Mocktransactor {
+	mock := &Mocktransactor{ctrl: ctrl}
+	mock.recorder = &MocktransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocktransactor) EXPECT() *MocktransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *Mocktransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MocktransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*Mocktransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockpaymentGateway is a mock of paymentGateway interface.
+type MockpaymentGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockpaymentGatewayMockRecorder
+}
+
+// MockpaymentGatewayMockRecorder is the mock recorder for MockpaymentGateway.
+type MockpaymentGatewayMockRecorder struct {
+	mock *MockpaymentGateway
+}
+
+// NewMockpaymentGateway creates a new mock instance.
+func NewMockpaymentGateway(ctrl *gomock.Controller) *MockpaymentGateway {
+	mock := &MockpaymentGateway{ctrl: ctrl}
+	mock.recorder = &MockpaymentGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpaymentGateway) EXPECT() *MockpaymentGatewayMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockpaymentGateway) Capture(ctx context.Context, chargeID string) (*payment0.Charge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, chargeID)
+	ret0, _ := ret[0].(*payment0.Charge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockpaymentGatewayMockRecorder) Capture(ctx, chargeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockpaymentGateway)(nil).Capture), ctx, chargeID)
+}
+
+// CreateCharge mocks base method.
+func (m *MockpaymentGateway) CreateCharge(ctx context.Context, req payment0.ChargeRequest) (*payment0.Charge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCharge", ctx, req)
+	ret0, _ := ret[0].(*payment0.Charge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCharge indicates an expected call of CreateCharge.
+func (mr *MockpaymentGatewayMockRecorder) CreateCharge(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCharge", reflect.TypeOf((*MockpaymentGateway)(nil).CreateCharge), ctx, req)
+}
+
+// Name mocks base method.
+func (m *MockpaymentGateway) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockpaymentGatewayMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockpaymentGateway)(nil).Name))
+}
+
+// ParseWebhook mocks base method.
+func (m *MockpaymentGateway) ParseWebhook(header http.Header, body []byte) (*payment0.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseWebhook", header, body)
+	ret0, _ := ret[0].(*payment0.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseWebhook indicates an expected call of ParseWebhook.
+func (mr *MockpaymentGatewayMockRecorder) ParseWebhook(header, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseWebhook", reflect.TypeOf((*MockpaymentGateway)(nil).ParseWebhook), header, body)
+}
+
+// Refund mocks base method.
+func (m *MockpaymentGateway) Refund(ctx context.Context, chargeID string, amount int64) (*payment0.Charge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, chargeID, amount)
+	ret0, _ := ret[0].(*payment0.Charge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockpaymentGatewayMockRecorder) Refund(ctx, chargeID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockpaymentGateway)(nil).Refund), ctx, chargeID, amount)
+}
+
+// MockpaymentReposistory is a mock of paymentReposistory interface.
+type MockpaymentReposistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockpaymentReposistoryMockRecorder
+}
+
+// MockpaymentReposistoryMockRecorder is the mock recorder for MockpaymentReposistory.
+type MockpaymentReposistoryMockRecorder struct {
+	mock *MockpaymentReposistory
+}
+
+// NewMockpaymentReposistory creates a new mock instance.
+func NewMockpaymentReposistory(ctrl *gomock.Controller) *MockpaymentReposistory {
+	mock := &MockpaymentReposistory{ctrl: ctrl}
+	mock.recorder = &MockpaymentReposistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpaymentReposistory) EXPECT() *MockpaymentReposistoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockpaymentReposistory) Create(ctx context.Context, req payment.PaymentModel) (*payment.PaymentModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*payment.PaymentModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockpaymentReposistoryMockRecorder) Create(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockpaymentReposistory)(nil).Create), ctx, req)
+}
+
+// GetByChargeID mocks base method.
+func (m *MockpaymentReposistory) GetByChargeID(ctx context.Context, provider, chargeID string) (*payment.PaymentModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByChargeID", ctx, provider, chargeID)
+	ret0, _ := ret[0].(*payment.PaymentModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByChargeID indicates an expected call of GetByChargeID.
+func (mr *MockpaymentReposistoryMockRecorder) GetByChargeID(ctx, provider, chargeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByChargeID", reflect.TypeOf((*MockpaymentReposistory)(nil).GetByChargeID), ctx, provider, chargeID)
+}
+
+// GetByOrderID mocks base method.
+func (m *MockpaymentReposistory) GetByOrderID(ctx context.Context, orderID int64, status string) (*payment.PaymentModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderID", ctx, orderID, status)
+	ret0, _ := ret[0].(*payment.PaymentModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderID indicates an expected call of GetByOrderID.
+func (mr *MockpaymentReposistoryMockRecorder) GetByOrderID(ctx, orderID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderID", reflect.TypeOf((*MockpaymentReposistory)(nil).GetByOrderID), ctx, orderID, status)
+}
+
+// UpdateChargeID mocks base method.
+func (m *MockpaymentReposistory) UpdateChargeID(ctx context.Context, id int64, chargeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChargeID", ctx, id, chargeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChargeID indicates an expected call of UpdateChargeID.
+func (mr *MockpaymentReposistoryMockRecorder) UpdateChargeID(ctx, id, chargeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChargeID", reflect.TypeOf((*MockpaymentReposistory)(nil).UpdateChargeID), ctx, id, chargeID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockpaymentReposistory) UpdateStatus(ctx context.Context, id int64, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockpaymentReposistoryMockRecorder) UpdateStatus(ctx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockpaymentReposistory)(nil).UpdateStatus), ctx, id, from, to)
+}
+
+// MockorderReposistory is a mock of orderReposistory interface.
+type MockorderReposistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockorderReposistoryMockRecorder
+}
+
+// MockorderReposistoryMockRecorder is the mock recorder for MockorderReposistory.
+type MockorderReposistoryMockRecorder struct {
+	mock *MockorderReposistory
+}
+
+// NewMockorderReposistory creates a new mock instance.
+func NewMockorderReposistory(ctrl *gomock.Controller) *MockorderReposistory {
+	mock := &MockorderReposistory{ctrl: ctrl}
+	mock.recorder = &MockorderReposistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorderReposistory) EXPECT() *MockorderReposistoryMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockorderReposistory) GetByID(ctx context.Context, orderID int64) (*order.OrderModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, orderID)
+	ret0, _ := ret[0].(*order.OrderModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockorderReposistoryMockRecorder) GetByID(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockorderReposistory)(nil).GetByID), ctx, orderID)
+}
+
+// MockorderService is a mock of orderService interface.
+type MockorderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockorderServiceMockRecorder
+}
+
+// MockorderServiceMockRecorder is the mock recorder for MockorderService.
+type MockorderServiceMockRecorder struct {
+	mock *MockorderService
+}
+
+// NewMockorderService creates a new mock instance.
+func NewMockorderService(ctrl *gomock.Controller) *MockorderService {
+	mock := &MockorderService{ctrl: ctrl}
+	mock.recorder = &MockorderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorderService) EXPECT() *MockorderServiceMockRecorder {
+	return m.recorder
+}
+
+// DetailOrder mocks base method.
+func (m *MockorderService) DetailOrder(ctx context.Context, userID, orderID int64) (*order.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetailOrder", ctx, userID, orderID)
+	ret0, _ := ret[0].(*order.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetailOrder indicates an expected call of DetailOrder.
+func (mr *MockorderServiceMockRecorder) DetailOrder(ctx, userID, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetailOrder", reflect.TypeOf((*MockorderService)(nil).DetailOrder), ctx, userID, orderID)
+}
+
+// UpdatePaymentStatus mocks base method.
+func (m *MockorderService) UpdatePaymentStatus(ctx context.Context, actorID, orderID int64, req order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentStatus", ctx, actorID, orderID, req)
+	ret0, _ := ret[0].(*order.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentStatus indicates an expected call of UpdatePaymentStatus.
+func (mr *MockorderServiceMockRecorder) UpdatePaymentStatus(ctx, actorID, orderID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentStatus", reflect.TypeOf((*MockorderService)(nil).UpdatePaymentStatus), ctx, actorID, orderID, req)
+}
@@ -0,0 +1,533 @@
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erizkiatama/gotu-assignment/internal/model/order"
+	"github.com/erizkiatama/gotu-assignment/internal/model/payment"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	gateway "github.com/erizkiatama/gotu-assignment/internal/pkg/payment"
+	"github.com/lib/pq"
+	. "github.com/smartystreets/goconvey/convey"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func newMock(
+	mockTransactor *Mocktransactor,
+	mockGateway *MockpaymentGateway,
+	mockPaymentRepo *MockpaymentReposistory,
+	mockOrderRepo *MockorderReposistory,
+	mockOrderSvc *MockorderService,
+) *service {
+	return New(mockTransactor, mockGateway, mockPaymentRepo, mockOrderRepo, mockOrderSvc)
+}
+
+// runInTx runs the unit of work as the transactor would, without a database
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func Test_service_CreatePayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	paymentGateway := NewMockpaymentGateway(mockCtrl)
+	paymentRepo := NewMockpaymentReposistory(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	orderSvc := NewMockorderService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, paymentGateway, paymentRepo, orderRepo, orderSvc)
+
+	createdAt := time.Now()
+	pending := &order.OrderModel{ID: 1, UserID: 1, TotalQty: 1, TotalPrice: 10000, Status: order.StatusPending}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		want     *payment.PaymentResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				gomock.InOrder(
+					paymentRepo.EXPECT().Create(gomock.Any(), payment.PaymentModel{
+						OrderID:  1,
+						Provider: "fake",
+						Amount:   10000,
+						Currency: "IDR",
+						Status:   payment.StatusPending,
+					}).Return(&payment.PaymentModel{
+						ID:        1,
+						OrderID:   1,
+						Provider:  "fake",
+						Amount:    10000,
+						Currency:  "IDR",
+						Status:    payment.StatusPending,
+						CreatedAt: createdAt,
+					}, nil),
+					paymentGateway.EXPECT().CreateCharge(gomock.Any(), gateway.ChargeRequest{
+						Reference: "order-1",
+						Amount:    10000,
+						Currency:  "IDR",
+					}).Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargePending, Amount: 10000, Currency: "IDR"}, nil),
+					paymentRepo.EXPECT().UpdateChargeID(gomock.Any(), int64(1), "ch_fake_1").Return(nil),
+				)
+			},
+			want: &payment.PaymentResponse{
+				ID:        1,
+				OrderID:   1,
+				Provider:  "fake",
+				ChargeID:  "ch_fake_1",
+				Amount:    10000,
+				Currency:  "IDR",
+				Status:    payment.StatusPending,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "order not found",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "order of another user",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{ID: 1, UserID: 2, Status: order.StatusPending}, nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "order is not pending",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&order.OrderModel{ID: 1, UserID: 1, Status: order.StatusCancelled}, nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "order already has a payment",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("error: %w", db.Translate(&pq.Error{Code: "23505", Constraint: activePaymentIndex})))
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "failed to create payment",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "gateway failed",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&payment.PaymentModel{ID: 1, Amount: 10000}, nil)
+				paymentGateway.EXPECT().CreateCharge(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusFailed).Return(nil)
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name: "failed to record the charge",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&payment.PaymentModel{ID: 1, Amount: 10000}, nil)
+				paymentGateway.EXPECT().CreateCharge(gomock.Any(), gomock.Any()).Return(&gateway.Charge{ID: "ch_fake_1"}, nil)
+				paymentRepo.EXPECT().UpdateChargeID(gomock.Any(), int64(1), "ch_fake_1").Return(errors.New("error"))
+				gomock.InOrder(
+					paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).Return(&gateway.Charge{ID: "ch_fake_1"}, nil),
+					paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusFailed).Return(nil),
+				)
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failed to void the charge",
+			mock: func() {
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pending, nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&payment.PaymentModel{ID: 1, Amount: 10000}, nil)
+				paymentGateway.EXPECT().CreateCharge(gomock.Any(), gomock.Any()).Return(&gateway.Charge{ID: "ch_fake_1"}, nil)
+				paymentRepo.EXPECT().UpdateChargeID(gomock.Any(), int64(1), "ch_fake_1").Return(errors.New("error"))
+				// The payment stays reserved, so the order cannot be charged again
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Payment Service - CreatePayment", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.CreatePayment(context.Background(), 1, 1)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_HandleWebhook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	paymentGateway := NewMockpaymentGateway(mockCtrl)
+	paymentRepo := NewMockpaymentReposistory(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	orderSvc := NewMockorderService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, paymentGateway, paymentRepo, orderRepo, orderSvc)
+
+	header := make(http.Header)
+	body := []byte(`{}`)
+	pending := &payment.PaymentModel{ID: 1, OrderID: 2, Provider: "fake", ChargeID: "ch_fake_1", Amount: 10000, Status: payment.StatusPending}
+	refunding := &payment.PaymentModel{ID: 1, OrderID: 2, Provider: "fake", ChargeID: "ch_fake_1", Amount: 10000, Status: payment.StatusRefunding}
+	pendingOrder := &order.OrderModel{ID: 2, UserID: 1, Status: order.StatusPending}
+	cancelledOrder := &order.OrderModel{ID: 2, UserID: 1, Status: order.StatusCancelled}
+	event := func(eventType string) *gateway.Event {
+		return &gateway.Event{ID: "evt_1", Type: eventType, ChargeID: "ch_fake_1"}
+	}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "invalid signature",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(nil, gateway.ErrInvalidSignature)
+			},
+			wantErr:  true,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "invalid event",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(nil, fmt.Errorf("%w: bad json", gateway.ErrInvalidEvent))
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "payment not found",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "charge succeeded",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(pendingOrder, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusSucceeded).Return(nil)
+				orderSvc.EXPECT().UpdatePaymentStatus(gomock.Any(), int64(0), int64(2), order.UpdateOrderStatusRequest{
+					Status: order.StatusPaid,
+					Reason: "paid with fake charge ch_fake_1",
+				}).Return(&order.OrderResponse{ID: 2, Status: order.StatusPaid}, nil)
+			},
+		},
+		{
+			name: "charge succeeded delivered twice",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(pendingOrder, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusSucceeded).
+					Return(fmt.Errorf("not pending: %w", sql.ErrNoRows))
+			},
+		},
+		{
+			name: "order cancelled concurrently",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(pendingOrder, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusSucceeded).Return(nil)
+				orderSvc.EXPECT().UpdatePaymentStatus(gomock.Any(), int64(0), int64(2), gomock.Any()).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Err:  errors.New("order is cancelled"),
+				})
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "charge succeeded on a cancelled order",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				gomock.InOrder(
+					transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx),
+					orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(cancelledOrder, nil),
+					paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusRefunding).Return(nil),
+					paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).
+						Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeRefunded}, nil),
+					paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusRefunding, payment.StatusRefunded).Return(nil),
+				)
+			},
+		},
+		{
+			name: "refund of a cancelled order failed",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(cancelledOrder, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusRefunding).Return(nil)
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name: "interrupted refund resumed",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeSucceeded), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(refunding, nil)
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).
+					Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeRefunded}, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusRefunding, payment.StatusRefunded).Return(nil)
+			},
+		},
+		{
+			name: "charge authorized",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeAuthorized), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				paymentGateway.EXPECT().Capture(gomock.Any(), "ch_fake_1").Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeSucceeded}, nil)
+			},
+		},
+		{
+			name: "capture failed",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeAuthorized), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				paymentGateway.EXPECT().Capture(gomock.Any(), "ch_fake_1").Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name: "charge failed",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event(gateway.EventChargeFailed), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusPending, payment.StatusFailed).Return(nil)
+			},
+		},
+		{
+			name: "unhandled event",
+			mock: func() {
+				paymentGateway.EXPECT().ParseWebhook(header, body).Return(event("charge.disputed"), nil)
+				paymentGateway.EXPECT().Name().Return("fake")
+				paymentRepo.EXPECT().GetByChargeID(gomock.Any(), "fake", "ch_fake_1").Return(pending, nil)
+			},
+		},
+	}
+
+	Convey("Test Payment Service - HandleWebhook", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				err := svc.HandleWebhook(context.Background(), header, body)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_RefundPayment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	transactor := NewMocktransactor(mockCtrl)
+	paymentGateway := NewMockpaymentGateway(mockCtrl)
+	paymentRepo := NewMockpaymentReposistory(mockCtrl)
+	orderRepo := NewMockorderReposistory(mockCtrl)
+	orderSvc := NewMockorderService(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(transactor, paymentGateway, paymentRepo, orderRepo, orderSvc)
+
+	adminID := int64(9)
+	succeeded := &payment.PaymentModel{ID: 1, OrderID: 2, Provider: "fake", ChargeID: "ch_fake_1", Amount: 10000, Status: payment.StatusSucceeded}
+	refunding := &payment.PaymentModel{ID: 1, OrderID: 2, Provider: "fake", ChargeID: "ch_fake_1", Amount: 10000, Status: payment.StatusRefunding}
+	req := payment.RefundPaymentRequest{Reason: "damaged book"}
+	orderReq := order.UpdateOrderStatusRequest{Status: order.StatusRefunded, Reason: "damaged book"}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		want     *order.OrderResponse
+		wantErr  bool
+		wantCode int
+	}{
+		{
+			name: "success",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(succeeded, nil)
+				gomock.InOrder(
+					transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx),
+					paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusSucceeded, payment.StatusRefunding).Return(nil),
+					orderSvc.EXPECT().UpdatePaymentStatus(gomock.Any(), adminID, int64(2), orderReq).
+						Return(&order.OrderResponse{ID: 2, Status: order.StatusRefunded}, nil),
+					paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).
+						Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeRefunded}, nil),
+					paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusRefunding, payment.StatusRefunded).Return(nil),
+				)
+			},
+			want: &order.OrderResponse{ID: 2, Status: order.StatusRefunded},
+		},
+		{
+			name: "order has no settled payment",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusRefunding).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "payment refunded concurrently",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(succeeded, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusSucceeded, payment.StatusRefunding).
+					Return(fmt.Errorf("not succeeded: %w", sql.ErrNoRows))
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "order cannot be refunded",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(succeeded, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusSucceeded, payment.StatusRefunding).Return(nil)
+				orderSvc.EXPECT().UpdatePaymentStatus(gomock.Any(), adminID, int64(2), orderReq).Return(nil, &response.ServiceError{
+					Code: http.StatusConflict,
+					Err:  errors.New("illegal transition"),
+				})
+			},
+			wantErr:  true,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "gateway failed",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(succeeded, nil)
+				transactor.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusSucceeded, payment.StatusRefunding).Return(nil)
+				orderSvc.EXPECT().UpdatePaymentStatus(gomock.Any(), adminID, int64(2), orderReq).
+					Return(&order.OrderResponse{ID: 2, Status: order.StatusRefunded}, nil)
+				// The payment is left refunding, so refunding the order again resumes the refund
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).Return(nil, errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusBadGateway,
+		},
+		{
+			name: "interrupted refund resumed",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusRefunding).Return(refunding, nil)
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).
+					Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeRefunded}, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusRefunding, payment.StatusRefunded).Return(nil)
+				orderRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&order.OrderModel{ID: 2, UserID: 1, Status: order.StatusRefunded}, nil)
+				orderSvc.EXPECT().DetailOrder(gomock.Any(), int64(1), int64(2)).Return(&order.OrderResponse{ID: 2, Status: order.StatusRefunded}, nil)
+			},
+			want: &order.OrderResponse{ID: 2, Status: order.StatusRefunded},
+		},
+		{
+			name: "failed to mark the payment refunded",
+			mock: func() {
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusSucceeded).Return(nil, fmt.Errorf("not found: %w", sql.ErrNoRows))
+				paymentRepo.EXPECT().GetByOrderID(gomock.Any(), int64(2), payment.StatusRefunding).Return(refunding, nil)
+				paymentGateway.EXPECT().Refund(gomock.Any(), "ch_fake_1", int64(10000)).
+					Return(&gateway.Charge{ID: "ch_fake_1", Status: gateway.ChargeRefunded}, nil)
+				paymentRepo.EXPECT().UpdateStatus(gomock.Any(), int64(1), payment.StatusRefunding, payment.StatusRefunded).Return(errors.New("error"))
+			},
+			wantErr:  true,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	Convey("Test Payment Service - RefundPayment", t, func() {
+		for _, tt := range tests {
+			tt := tt
+			Convey(tt.name, func() {
+				tt.mock()
+				got, err := svc.RefundPayment(context.Background(), adminID, 2, req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, tt.want)
+				}
+			})
+		}
+	})
+}
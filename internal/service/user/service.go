@@ -6,13 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
+	"github.com/erizkiatama/gotu-assignment/internal/config"
 	"github.com/erizkiatama/gotu-assignment/internal/constant"
 	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/helpers"
 	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/validation"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,15 +23,30 @@ import (
 type userReposistory interface {
 	Create(ctx context.Context, req user.UserModel) (*user.UserModel, error)
 	GetByEmail(ctx context.Context, email string) (*user.UserModel, error)
+	CreateRefreshToken(ctx context.Context, req user.RefreshTokenModel) error
+	GetRefreshToken(ctx context.Context, tokenID string) (*user.RefreshTokenModel, error)
+	UseRefreshToken(ctx context.Context, tokenID string) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int64) error
+	CreateRevokedToken(ctx context.Context, req user.RevokedTokenModel) (*user.RevokedTokenModel, error)
+	GetPermissions(ctx context.Context, userID int64) ([]user.UserPermissionModel, error)
+	AssignRole(ctx context.Context, userID int64, role string) error
+	Restore(ctx context.Context, userID int64) error
+}
+
+type tokenDenylist interface {
+	Add(entry user.RevokedTokenModel)
 }
 
 type service struct {
 	userRepo userReposistory
+	denylist tokenDenylist
 }
 
-func New(userRepo userReposistory) *service {
+func New(userRepo userReposistory, denylist tokenDenylist) *service {
 	return &service{
 		userRepo: userRepo,
+		denylist: denylist,
 	}
 }
 
@@ -36,9 +54,10 @@ func (s *service) Register(ctx context.Context, req user.RegisterRequest) (*user
 	hashedPassword, err := helpers.EncryptPassword([]byte(req.Password))
 	if err != nil {
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorInternalServer,
-			Err:  err,
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeInternalServer,
+			Msg:       constant.ErrorInternalServer,
+			Err:       err,
 		}
 	}
 
@@ -49,70 +68,398 @@ func (s *service) Register(ctx context.Context, req user.RegisterRequest) (*user
 	}
 	res, err := s.userRepo.Create(ctx, newUser)
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
+		if errors.Is(err, db.ErrUniqueViolation) {
 			return nil, &response.ServiceError{
-				Code: http.StatusConflict,
-				Msg:  constant.ErrorUserAlreadyExists,
-				Err:  err,
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeUserAlreadyExists,
+				Msg:       constant.ErrorUserAlreadyExists,
+				Err:       err,
 			}
 		} else {
 			return nil, &response.ServiceError{
-				Code: http.StatusInternalServerError,
-				Msg:  constant.ErrorCreateUserFailed,
-				Err:  err,
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCreateUserFailed,
+				Msg:       constant.ErrorCreateUserFailed,
+				Err:       err,
 			}
 		}
 	}
 
-	tokenPair, err := jwt.GenerateTokenPair(jwt.TokenClaim{
-		Id: res.ID,
-	})
+	return s.issueTokenPair(ctx, res.ID, "")
+}
+
+func (s *service) Login(ctx context.Context, req user.LoginRequest) (*user.TokenPairResponse, error) {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeUserNotFound,
+				Msg:       constant.ErrorUserNotFound,
+				Err:       err,
+			}
+		}
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorGenerateToken,
-			Err:  fmt.Errorf("[UserSvc.Register] failed to generate token: %v", err),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetUserFailed,
+			Msg:       constant.ErrorGetUserFailed,
+			Err:       err,
 		}
 	}
 
-	return tokenPair, nil
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusBadRequest,
+			ErrorCode: constant.CodePasswordNotMatch,
+			Msg:       constant.ErrorPasswordNotMatch,
+			Err:       fmt.Errorf("[UserSvc.Login] failed to compare password: %v", err),
+		}
+	}
+
+	return s.issueTokenPair(ctx, user.ID, "")
 }
 
-func (s *service) Login(ctx context.Context, req user.LoginRequest) (*user.TokenPairResponse, error) {
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+func (s *service) Refresh(ctx context.Context, req user.RefreshRequest) (*user.TokenPairResponse, error) {
+	claim, err := jwt.AuthorizeToken(req.Refresh, jwt.TokenTypeRefresh)
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusUnauthorized,
+			ErrorCode: constant.CodeInvalidRefreshToken,
+			Msg:       constant.ErrorInvalidRefreshToken,
+			Err:       fmt.Errorf("[UserSvc.Refresh] failed to authorize token: %v", err),
+		}
+	}
+
+	if claim.TokenID == "" {
+		return nil, &response.ServiceError{
+			Code:      http.StatusUnauthorized,
+			ErrorCode: constant.CodeInvalidRefreshToken,
+			Msg:       constant.ErrorInvalidRefreshToken,
+			Err:       errors.New("[UserSvc.Refresh] token does not have a token id"),
+		}
+	}
+
+	stored, err := s.userRepo.GetRefreshToken(ctx, claim.TokenID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, &response.ServiceError{
-				Code: http.StatusNotFound,
-				Msg:  constant.ErrorUserNotFound,
-				Err:  err,
+				Code:      http.StatusUnauthorized,
+				ErrorCode: constant.CodeInvalidRefreshToken,
+				Msg:       constant.ErrorInvalidRefreshToken,
+				Err:       err,
 			}
 		}
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorGetUserFailed,
-			Err:  err,
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRefreshTokenFailed,
+			Msg:       constant.ErrorRefreshTokenFailed,
+			Err:       err,
 		}
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if stored.IsRevoked {
+		return nil, &response.ServiceError{
+			Code:      http.StatusUnauthorized,
+			ErrorCode: constant.CodeInvalidRefreshToken,
+			Msg:       constant.ErrorInvalidRefreshToken,
+			Err:       fmt.Errorf("[UserSvc.Refresh] token family %s has been revoked", stored.FamilyID),
+		}
+	}
+
+	// A rotated refresh token must never be presented again. If it is, the token
+	// has most likely been stolen, so every token issued from the same login is revoked.
+	used := stored.IsUsed
+	if !used {
+		ok, err := s.userRepo.UseRefreshToken(ctx, stored.TokenID)
+		if err != nil {
+			return nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeRefreshTokenFailed,
+				Msg:       constant.ErrorRefreshTokenFailed,
+				Err:       err,
+			}
+		}
+		used = !ok
+	}
+
+	if used {
+		if err := s.userRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+			return nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeRefreshTokenFailed,
+				Msg:       constant.ErrorRefreshTokenFailed,
+				Err:       err,
+			}
+		}
+		return nil, &response.ServiceError{
+			Code:      http.StatusUnauthorized,
+			ErrorCode: constant.CodeRefreshTokenReused,
+			Msg:       constant.ErrorRefreshTokenReused,
+			Err:       fmt.Errorf("[UserSvc.Refresh] token %s has already been used", stored.TokenID),
+		}
+	}
+
+	return s.issueTokenPair(ctx, stored.UserID, stored.FamilyID)
+}
+
+// CreateAdmin grants the admin role to the user with the given email, registering the user
+// first when it does not exist yet. It is used to bootstrap the first admin account.
+func (s *service) CreateAdmin(ctx context.Context, req user.RegisterRequest) (int64, error) {
+	existing, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGetUserFailed,
+			Msg:       constant.ErrorGetUserFailed,
+			Err:       err,
+		}
+	}
+
+	var userID int64
+	if existing != nil {
+		userID = existing.ID
+	} else {
+		if req.Password == "" {
+			return 0, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeCreateUserFailed,
+				Msg:       constant.ErrorCreateUserFailed,
+				Err:       errors.New("[UserSvc.CreateAdmin] password is required to create a new user"),
+			}
+		}
+
+		// The password skips the request binding of the registration, so its rule is checked here
+		if !validation.IsStrongPassword(req.Password) {
+			return 0, &response.ServiceError{
+				Code:      http.StatusBadRequest,
+				ErrorCode: constant.CodeCreateUserFailed,
+				Msg:       constant.ErrorCreateUserFailed,
+				Err: fmt.Errorf("[UserSvc.CreateAdmin] password has to be at least %d characters and at most %d bytes, and contain a letter and a digit",
+					validation.MinPasswordLength, validation.MaxPasswordBytes),
+			}
+		}
+
+		hashedPassword, err := helpers.EncryptPassword([]byte(req.Password))
+		if err != nil {
+			return 0, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeInternalServer,
+				Msg:       constant.ErrorInternalServer,
+				Err:       err,
+			}
+		}
+
+		created, err := s.userRepo.Create(ctx, user.UserModel{
+			Email:    req.Email,
+			Password: hashedPassword,
+			Name:     req.Name,
+		})
+		if err != nil {
+			return 0, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeCreateUserFailed,
+				Msg:       constant.ErrorCreateUserFailed,
+				Err:       err,
+			}
+		}
+		userID = created.ID
+	}
+
+	if err := s.userRepo.AssignRole(ctx, userID, user.RoleAdmin); err != nil {
+		return 0, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeAssignRoleFailed,
+			Msg:       constant.ErrorAssignRoleFailed,
+			Err:       err,
+		}
+	}
+
+	return userID, nil
+}
+
+// Logout revokes the given access token and the refresh tokens issued from the same login
+func (s *service) Logout(ctx context.Context, claim jwt.TokenClaim) error {
+	revoked, err := s.userRepo.CreateRevokedToken(ctx, user.RevokedTokenModel{
+		UserID:    claim.Id,
+		TokenID:   sql.NullString{String: claim.TokenID, Valid: true},
+		ExpiresAt: claim.ExpiresAt,
+	})
+	if err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeLogoutFailed,
+			Msg:       constant.ErrorLogoutFailed,
+			Err:       err,
+		}
+	}
+
+	if claim.FamilyID != "" {
+		if err := s.userRepo.RevokeRefreshTokenFamily(ctx, claim.FamilyID); err != nil {
+			return &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeLogoutFailed,
+				Msg:       constant.ErrorLogoutFailed,
+				Err:       err,
+			}
+		}
+	}
+
+	s.denylist.Add(*revoked)
+	return nil
+}
+
+// LogoutAll revokes every token issued to the user so far
+func (s *service) LogoutAll(ctx context.Context, userID int64) error {
+	if err := s.userRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeLogoutFailed,
+			Msg:       constant.ErrorLogoutFailed,
+			Err:       err,
+		}
+	}
+
+	// The entry only has to outlive the longest living token issued before it
+	revoked, err := s.userRepo.CreateRevokedToken(ctx, user.RevokedTokenModel{
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Duration(config.Get().Jwt.RefreshTokenExpiryHours) * time.Hour).UTC(),
+	})
+	if err != nil {
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeLogoutFailed,
+			Msg:       constant.ErrorLogoutFailed,
+			Err:       err,
+		}
+	}
+
+	s.denylist.Add(*revoked)
+	return nil
+}
+
+// Restore undoes the soft deletion of a user. It fails when the email of the user has been
+// registered again in the meantime.
+func (s *service) Restore(ctx context.Context, userID int64) error {
+	err := s.userRepo.Restore(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &response.ServiceError{
+				Code:      http.StatusNotFound,
+				ErrorCode: constant.CodeUserIDNotFound,
+				Msg:       constant.ErrorUserIDNotFound,
+				Err:       err,
+			}
+		}
+		if errors.Is(err, db.ErrUniqueViolation) {
+			return &response.ServiceError{
+				Code:      http.StatusConflict,
+				ErrorCode: constant.CodeUserAlreadyExists,
+				Msg:       constant.ErrorUserAlreadyExists,
+				Err:       err,
+			}
+		}
+		return &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeRestoreUserFailed,
+			Msg:       constant.ErrorRestoreUserFailed,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// issueTokenPair generates a new token pair for the user and stores its refresh token.
+// An empty familyID starts a new token family, e.g. on register or login.
+func (s *service) issueTokenPair(ctx context.Context, userID int64, familyID string) (*user.TokenPairResponse, error) {
+	tokenID, err := jwt.NewTokenID()
 	if err != nil {
 		return nil, &response.ServiceError{
-			Code: http.StatusBadRequest,
-			Msg:  constant.ErrorPasswordNotMatch,
-			Err:  fmt.Errorf("[UserSvc.Login] failed to compare password: %v", err),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGenerateToken,
+			Msg:       constant.ErrorGenerateToken,
+			Err:       fmt.Errorf("[UserSvc.issueTokenPair] failed to generate token id: %v", err),
+		}
+	}
+
+	if familyID == "" {
+		familyID, err = jwt.NewTokenID()
+		if err != nil {
+			return nil, &response.ServiceError{
+				Code:      http.StatusInternalServerError,
+				ErrorCode: constant.CodeGenerateToken,
+				Msg:       constant.ErrorGenerateToken,
+				Err:       fmt.Errorf("[UserSvc.issueTokenPair] failed to generate family id: %v", err),
+			}
 		}
 	}
 
-	tokenPair, err := jwt.GenerateTokenPair(jwt.TokenClaim{Id: user.ID})
+	userPermissions, err := s.userRepo.GetPermissions(ctx, userID)
 	if err != nil {
 		return nil, &response.ServiceError{
-			Code: http.StatusInternalServerError,
-			Msg:  constant.ErrorGenerateToken,
-			Err:  fmt.Errorf("[UserSvc.Login] failed to generate token: %v", err),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGenerateToken,
+			Msg:       constant.ErrorGenerateToken,
+			Err:       err,
+		}
+	}
+	roles, permissions := collectPermissions(userPermissions)
+
+	tokenPair, err := jwt.GenerateTokenPair(jwt.TokenClaim{
+		Id:          userID,
+		TokenID:     tokenID,
+		FamilyID:    familyID,
+		Roles:       roles,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGenerateToken,
+			Msg:       constant.ErrorGenerateToken,
+			Err:       fmt.Errorf("[UserSvc.issueTokenPair] failed to generate token: %v", err),
+		}
+	}
+
+	err = s.userRepo.CreateRefreshToken(ctx, user.RefreshTokenModel{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenID:   tokenID,
+		ExpiresAt: time.Now().Add(time.Duration(config.Get().Jwt.RefreshTokenExpiryHours) * time.Hour).UTC(),
+	})
+	if err != nil {
+		return nil, &response.ServiceError{
+			Code:      http.StatusInternalServerError,
+			ErrorCode: constant.CodeGenerateToken,
+			Msg:       constant.ErrorGenerateToken,
+			Err:       err,
 		}
 	}
 
 	return tokenPair, nil
+}
+
+// collectPermissions returns the distinct role and permission names of the user
+func collectPermissions(userPermissions []user.UserPermissionModel) ([]string, []string) {
+	var (
+		roles           []string
+		permissions     []string
+		seenRoles       = make(map[string]bool)
+		seenPermissions = make(map[string]bool)
+	)
+
+	for _, p := range userPermissions {
+		if !seenRoles[p.Role] {
+			seenRoles[p.Role] = true
+			roles = append(roles, p.Role)
+		}
+
+		if p.Permission.Valid && !seenPermissions[p.Permission.String] {
+			seenPermissions[p.Permission.String] = true
+			permissions = append(permissions, p.Permission.String)
+		}
+	}
 
+	return roles, permissions
 }
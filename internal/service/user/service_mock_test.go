@@ -40,6 +40,20 @@ func (m *MockuserReposistory) EXPECT() *MockuserReposistoryMockRecorder {
 	return m.recorder
 }
 
+// AssignRole mocks base method.
+func (m *MockuserReposistory) AssignRole(ctx context.Context, userID int64, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockuserReposistoryMockRecorder) AssignRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockuserReposistory)(nil).AssignRole), ctx, userID, role)
+}
+
 // Create mocks base method.
 func (m *MockuserReposistory) Create(ctx context.Context, req user.UserModel) (*user.UserModel, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +69,35 @@ func (mr *MockuserReposistoryMockRecorder) Create(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockuserReposistory)(nil).Create), ctx, req)
 }
 
+// CreateRefreshToken mocks base method.
+func (m *MockuserReposistory) CreateRefreshToken(ctx context.Context, req user.RefreshTokenModel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockuserReposistoryMockRecorder) CreateRefreshToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockuserReposistory)(nil).CreateRefreshToken), ctx, req)
+}
+
+// CreateRevokedToken mocks base method.
+func (m *MockuserReposistory) CreateRevokedToken(ctx context.Context, req user.RevokedTokenModel) (*user.RevokedTokenModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRevokedToken", ctx, req)
+	ret0, _ := ret[0].(*user.RevokedTokenModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRevokedToken indicates an expected call of CreateRevokedToken.
+func (mr *MockuserReposistoryMockRecorder) CreateRevokedToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRevokedToken", reflect.TypeOf((*MockuserReposistory)(nil).CreateRevokedToken), ctx, req)
+}
+
 // GetByEmail mocks base method.
 func (m *MockuserReposistory) GetByEmail(ctx context.Context, email string) (*user.UserModel, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +112,125 @@ func (mr *MockuserReposistoryMockRecorder) GetByEmail(ctx, email any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockuserReposistory)(nil).GetByEmail), ctx, email)
 }
+
+// GetPermissions mocks base method.
+func (m *MockuserReposistory) GetPermissions(ctx context.Context, userID int64) ([]user.UserPermissionModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx, userID)
+	ret0, _ := ret[0].([]user.UserPermissionModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockuserReposistoryMockRecorder) GetPermissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockuserReposistory)(nil).GetPermissions), ctx, userID)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockuserReposistory) GetRefreshToken(ctx context.Context, tokenID string) (*user.RefreshTokenModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenID)
+	ret0, _ := ret[0].(*user.RefreshTokenModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockuserReposistoryMockRecorder) GetRefreshToken(ctx, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockuserReposistory)(nil).GetRefreshToken), ctx, tokenID)
+}
+
+// Restore mocks base method.
+func (m *MockuserReposistory) Restore(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockuserReposistoryMockRecorder) Restore(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockuserReposistory)(nil).Restore), ctx, userID)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockuserReposistory) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockuserReposistoryMockRecorder) RevokeRefreshTokenFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockuserReposistory)(nil).RevokeRefreshTokenFamily), ctx, familyID)
+}
+
+// RevokeUserRefreshTokens mocks base method.
+func (m *MockuserReposistory) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRefreshTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRefreshTokens indicates an expected call of RevokeUserRefreshTokens.
+func (mr *MockuserReposistoryMockRecorder) RevokeUserRefreshTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRefreshTokens", reflect.TypeOf((*MockuserReposistory)(nil).RevokeUserRefreshTokens), ctx, userID)
+}
+
+// UseRefreshToken mocks base method.
+func (m *MockuserReposistory) UseRefreshToken(ctx context.Context, tokenID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRefreshToken", ctx, tokenID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRefreshToken indicates an expected call of UseRefreshToken.
+func (mr *MockuserReposistoryMockRecorder) UseRefreshToken(ctx, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRefreshToken", reflect.TypeOf((*MockuserReposistory)(nil).UseRefreshToken), ctx, tokenID)
+}
+
+// MocktokenDenylist is a mock of tokenDenylist interface.
+type MocktokenDenylist struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenDenylistMockRecorder
+}
+
+// MocktokenDenylistMockRecorder is the mock recorder for MocktokenDenylist.
+type MocktokenDenylistMockRecorder struct {
+	mock *MocktokenDenylist
+}
+
+// NewMocktokenDenylist creates a new mock instance.
+func NewMocktokenDenylist(ctrl *gomock.Controller) *MocktokenDenylist {
+	mock := &MocktokenDenylist{ctrl: ctrl}
+	mock.recorder = &MocktokenDenylistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenDenylist) EXPECT() *MocktokenDenylistMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MocktokenDenylist) Add(entry user.RevokedTokenModel) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Add", entry)
+}
+
+// Add indicates an expected call of Add.
+func (mr *MocktokenDenylistMockRecorder) Add(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MocktokenDenylist)(nil).Add), entry)
+}
@@ -4,25 +4,34 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	gomock "go.uber.org/mock/gomock"
 
 	"github.com/erizkiatama/gotu-assignment/internal/config"
+	"github.com/erizkiatama/gotu-assignment/internal/constant"
+	"github.com/erizkiatama/gotu-assignment/internal/model/response"
 	"github.com/erizkiatama/gotu-assignment/internal/model/user"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/db"
+	"github.com/erizkiatama/gotu-assignment/internal/pkg/jwt"
+	"github.com/lib/pq"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
-func newMock(mockUserRepo *MockuserReposistory) *service {
-	return New(mockUserRepo)
+func newMock(mockUserRepo *MockuserReposistory, mockDenylist *MocktokenDenylist) *service {
+	return New(mockUserRepo, mockDenylist)
 }
 
 func Test_service_Register(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
 	defer mockCtrl.Finish()
 
-	svc := newMock(userRepo)
+	svc := newMock(userRepo, denylist)
 
 	type args struct {
 		req user.RegisterRequest
@@ -57,7 +66,7 @@ func Test_service_Register(t *testing.T) {
 				},
 			},
 			mock: func(arg args) {
-				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("duplicate key error"))
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error: %w", db.Translate(&pq.Error{Code: "23505", Constraint: "users_email_unique_idx"})))
 			},
 			want:    nil,
 			wantErr: true,
@@ -87,10 +96,50 @@ func Test_service_Register(t *testing.T) {
 				},
 			},
 			mock: func(arg args) {
+				config.Get().Server.Env = config.EnvTest
 				config.Get().Server.SecretKey = "testing"
 				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&user.UserModel{ID: 1}, nil)
+				userRepo.EXPECT().GetPermissions(gomock.Any(), int64(1)).Return(nil, nil)
+				userRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
+		{
+			name: "failed to get permissions",
+			args: args{
+				req: user.RegisterRequest{
+					Email:    "test@testing.com",
+					Password: "password",
+					Name:     "test",
+				},
+			},
+			mock: func(arg args) {
+				config.Get().Server.Env = config.EnvTest
+				config.Get().Server.SecretKey = "testing"
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&user.UserModel{ID: 1}, nil)
+				userRepo.EXPECT().GetPermissions(gomock.Any(), int64(1)).Return(nil, errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "failed to store refresh token",
+			args: args{
+				req: user.RegisterRequest{
+					Email:    "test@testing.com",
+					Password: "password",
+					Name:     "test",
+				},
+			},
+			mock: func(arg args) {
+				config.Get().Server.Env = config.EnvTest
+				config.Get().Server.SecretKey = "testing"
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&user.UserModel{ID: 1}, nil)
+				userRepo.EXPECT().GetPermissions(gomock.Any(), int64(1)).Return(nil, nil)
+				userRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 
 	Convey("Test User Service - Register", t, func() {
@@ -111,9 +160,10 @@ func Test_service_Register(t *testing.T) {
 func Test_service_Login(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
 	defer mockCtrl.Finish()
 
-	svc := newMock(userRepo)
+	svc := newMock(userRepo, denylist)
 
 	type args struct {
 		req user.LoginRequest
@@ -177,6 +227,8 @@ func Test_service_Login(t *testing.T) {
 			},
 			mock: func(arg args) {
 				userRepo.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).Return(&user.UserModel{ID: 1, Password: "$2a$10$zxOrik5iLL4LBOXNRzCTY.5QUBFFTRbKxpemQbygAN6nouR7G3CU6"}, nil)
+				userRepo.EXPECT().GetPermissions(gomock.Any(), int64(1)).Return(nil, nil)
+				userRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			want:    &user.TokenPairResponse{},
 			wantErr: false,
@@ -197,3 +249,490 @@ func Test_service_Login(t *testing.T) {
 		}
 	})
 }
+
+func Test_service_Refresh(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(userRepo, denylist)
+
+	config.Get().Server.Env = config.EnvTest
+
+	config.Get().Server.SecretKey = "testing"
+	config.Get().Jwt.AccessTokenExpiryHours = 1
+	config.Get().Jwt.RefreshTokenExpiryHours = 24
+	tokenPair, _ := jwt.GenerateTokenPair(jwt.TokenClaim{Id: 1, TokenID: "token-id", FamilyID: "family-id"})
+	legacyPair, _ := jwt.GenerateTokenPair(jwt.TokenClaim{Id: 1})
+
+	storedToken := &user.RefreshTokenModel{
+		ID:       1,
+		UserID:   1,
+		FamilyID: "family-id",
+		TokenID:  "token-id",
+	}
+
+	type args struct {
+		req user.RefreshRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+		err     string
+	}{
+		{
+			name:    "invalid token",
+			args:    args{req: user.RefreshRequest{Refresh: "invalid"}},
+			mock:    func(arg args) {},
+			wantErr: true,
+			err:     constant.ErrorInvalidRefreshToken,
+		},
+		{
+			name:    "access token given",
+			args:    args{req: user.RefreshRequest{Refresh: tokenPair.Access}},
+			mock:    func(arg args) {},
+			wantErr: true,
+			err:     constant.ErrorInvalidRefreshToken,
+		},
+		{
+			name:    "token without token id",
+			args:    args{req: user.RefreshRequest{Refresh: legacyPair.Refresh}},
+			mock:    func(arg args) {},
+			wantErr: true,
+			err:     constant.ErrorInvalidRefreshToken,
+		},
+		{
+			name: "token not found",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(nil, sql.ErrNoRows)
+			},
+			wantErr: true,
+			err:     constant.ErrorInvalidRefreshToken,
+		},
+		{
+			name: "failed to get token",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenFailed,
+		},
+		{
+			name: "token family revoked",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				revoked := *storedToken
+				revoked.IsRevoked = true
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(&revoked, nil)
+			},
+			wantErr: true,
+			err:     constant.ErrorInvalidRefreshToken,
+		},
+		{
+			name: "token reused",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				used := *storedToken
+				used.IsUsed = true
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(&used, nil)
+				userRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family-id").Return(nil)
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenReused,
+		},
+		{
+			name: "token reused concurrently",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(storedToken, nil)
+				userRepo.EXPECT().UseRefreshToken(gomock.Any(), "token-id").Return(false, nil)
+				userRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family-id").Return(nil)
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenReused,
+		},
+		{
+			name: "failed to revoke token family",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				used := *storedToken
+				used.IsUsed = true
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(&used, nil)
+				userRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family-id").Return(errors.New("error"))
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenFailed,
+		},
+		{
+			name: "failed to use token",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(storedToken, nil)
+				userRepo.EXPECT().UseRefreshToken(gomock.Any(), "token-id").Return(false, errors.New("error"))
+			},
+			wantErr: true,
+			err:     constant.ErrorRefreshTokenFailed,
+		},
+		{
+			name: "success",
+			args: args{req: user.RefreshRequest{Refresh: tokenPair.Refresh}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetRefreshToken(gomock.Any(), "token-id").Return(storedToken, nil)
+				userRepo.EXPECT().UseRefreshToken(gomock.Any(), "token-id").Return(true, nil)
+				userRepo.EXPECT().GetPermissions(gomock.Any(), int64(1)).Return(nil, nil)
+				userRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req user.RefreshTokenModel) error {
+						So(req.UserID, ShouldEqual, 1)
+						So(req.FamilyID, ShouldEqual, "family-id")
+						So(req.TokenID, ShouldNotEqual, "token-id")
+						return nil
+					})
+			},
+		},
+	}
+
+	Convey("Test User Service - Refresh", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := svc.Refresh(context.Background(), tt.args.req)
+				if tt.wantErr {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Msg, ShouldEqual, tt.err)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldNotBeNil)
+					So(got.Refresh, ShouldNotEqual, tt.args.req.Refresh)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_Logout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(userRepo, denylist)
+
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	revoked := &user.RevokedTokenModel{
+		ID:        1,
+		UserID:    1,
+		TokenID:   sql.NullString{String: "token-id", Valid: true},
+		ExpiresAt: expiresAt,
+	}
+
+	type args struct {
+		claim jwt.TokenClaim
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "failed to create revoked token",
+			args: args{claim: jwt.TokenClaim{Id: 1, TokenID: "token-id", FamilyID: "family-id", ExpiresAt: expiresAt}},
+			mock: func(arg args) {
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to revoke token family",
+			args: args{claim: jwt.TokenClaim{Id: 1, TokenID: "token-id", FamilyID: "family-id", ExpiresAt: expiresAt}},
+			mock: func(arg args) {
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), gomock.Any()).Return(revoked, nil)
+				userRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family-id").Return(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{claim: jwt.TokenClaim{Id: 1, TokenID: "token-id", FamilyID: "family-id", ExpiresAt: expiresAt}},
+			mock: func(arg args) {
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), user.RevokedTokenModel{
+					UserID:    1,
+					TokenID:   sql.NullString{String: "token-id", Valid: true},
+					ExpiresAt: expiresAt,
+				}).Return(revoked, nil)
+				userRepo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family-id").Return(nil)
+				denylist.EXPECT().Add(*revoked)
+			},
+		},
+		{
+			name: "success without token family",
+			args: args{claim: jwt.TokenClaim{Id: 1, TokenID: "token-id", ExpiresAt: expiresAt}},
+			mock: func(arg args) {
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), gomock.Any()).Return(revoked, nil)
+				denylist.EXPECT().Add(*revoked)
+			},
+		},
+	}
+
+	Convey("Test User Service - Logout", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := svc.Logout(context.Background(), tt.args.claim)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_LogoutAll(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(userRepo, denylist)
+
+	revoked := &user.RevokedTokenModel{
+		ID:        1,
+		UserID:    1,
+		ExpiresAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	type args struct {
+		userID int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		wantErr bool
+	}{
+		{
+			name: "failed to revoke refresh tokens",
+			args: args{userID: 1},
+			mock: func(arg args) {
+				userRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), arg.userID).Return(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to create revoked token",
+			args: args{userID: 1},
+			mock: func(arg args) {
+				userRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), arg.userID).Return(nil)
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: args{userID: 1},
+			mock: func(arg args) {
+				userRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), arg.userID).Return(nil)
+				userRepo.EXPECT().CreateRevokedToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req user.RevokedTokenModel) (*user.RevokedTokenModel, error) {
+						So(req.UserID, ShouldEqual, arg.userID)
+						So(req.TokenID.Valid, ShouldBeFalse)
+						return revoked, nil
+					})
+				denylist.EXPECT().Add(*revoked)
+			},
+		},
+	}
+
+	Convey("Test User Service - LogoutAll", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				err := svc.LogoutAll(context.Background(), tt.args.userID)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
+
+func Test_service_CreateAdmin(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(userRepo, denylist)
+
+	type args struct {
+		req user.RegisterRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func(args)
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "failed to get user",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com", Password: "password123"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "new user without password",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(nil, sql.ErrNoRows)
+			},
+			wantErr: true,
+		},
+		{
+			name: "new user with a weak password",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com", Password: "password"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(nil, sql.ErrNoRows)
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to create user",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com", Password: "password123"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(nil, sql.ErrNoRows)
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to assign role",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com", Password: "password123"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(&user.UserModel{ID: 1}, nil)
+				userRepo.EXPECT().AssignRole(gomock.Any(), int64(1), user.RoleAdmin).Return(errors.New("error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success with new user",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com", Password: "password123", Name: "Admin"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(nil, sql.ErrNoRows)
+				userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&user.UserModel{ID: 2}, nil)
+				userRepo.EXPECT().AssignRole(gomock.Any(), int64(2), user.RoleAdmin).Return(nil)
+			},
+			want: 2,
+		},
+		{
+			name: "success with existing user",
+			args: args{req: user.RegisterRequest{Email: "admin@testing.com"}},
+			mock: func(arg args) {
+				userRepo.EXPECT().GetByEmail(gomock.Any(), arg.req.Email).Return(&user.UserModel{ID: 1}, nil)
+				userRepo.EXPECT().AssignRole(gomock.Any(), int64(1), user.RoleAdmin).Return(nil)
+			},
+			want: 1,
+		},
+	}
+
+	Convey("Test User Service - CreateAdmin", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock(tt.args)
+				got, err := svc.CreateAdmin(context.Background(), tt.args.req)
+				if tt.wantErr {
+					So(err, ShouldNotBeNil)
+				} else {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func Test_collectPermissions(t *testing.T) {
+	Convey("Test collect permissions", t, func() {
+		roles, permissions := collectPermissions([]user.UserPermissionModel{
+			{Role: "admin", Permission: sql.NullString{String: "book:write", Valid: true}},
+			{Role: "admin", Permission: sql.NullString{String: "order:read", Valid: true}},
+			{Role: "support", Permission: sql.NullString{String: "order:read", Valid: true}},
+			{Role: "guest"},
+		})
+
+		So(roles, ShouldResemble, []string{"admin", "support", "guest"})
+		So(permissions, ShouldResemble, []string{"book:write", "order:read"})
+	})
+}
+
+func Test_service_Restore(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	userRepo := NewMockuserReposistory(mockCtrl)
+	denylist := NewMocktokenDenylist(mockCtrl)
+	defer mockCtrl.Finish()
+
+	svc := newMock(userRepo, denylist)
+
+	tests := []struct {
+		name     string
+		mock     func()
+		wantCode int
+	}{
+		{
+			name: "user not found",
+			mock: func() {
+				userRepo.EXPECT().Restore(gomock.Any(), int64(1)).Return(fmt.Errorf("error: %w", sql.ErrNoRows))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "email registered again",
+			mock: func() {
+				userRepo.EXPECT().Restore(gomock.Any(), int64(1)).Return(fmt.Errorf("error: %w", db.Translate(&pq.Error{Code: "23505", Constraint: "users_email_unique_idx"})))
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "error from repository",
+			mock: func() {
+				userRepo.EXPECT().Restore(gomock.Any(), int64(1)).Return(errors.New("error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "success",
+			mock: func() {
+				userRepo.EXPECT().Restore(gomock.Any(), int64(1)).Return(nil)
+			},
+		},
+	}
+
+	Convey("Test User Service - Restore", t, func() {
+		for _, tt := range tests {
+			Convey(tt.name, func() {
+				tt.mock()
+				err := svc.Restore(context.Background(), 1)
+				if tt.wantCode != 0 {
+					var svcErr *response.ServiceError
+					So(errors.As(err, &svcErr), ShouldBeTrue)
+					So(svcErr.Code, ShouldEqual, tt.wantCode)
+				} else {
+					So(err, ShouldBeNil)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  "id"            SERIAL          PRIMARY KEY,
+  "user_id"       INT8            NOT NULL,
+  "family_id"     VARCHAR(64)     NOT NULL,
+  "token_id"      VARCHAR(64)     NOT NULL UNIQUE,
+  "expires_at"    TIMESTAMP(6)    NOT NULL,
+  "is_used"       BOOLEAN         NOT NULL DEFAULT false,
+  "is_revoked"    BOOLEAN         NOT NULL DEFAULT false,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"    TIMESTAMP(6),
+  "is_deleted"    BOOLEAN         NOT NULL DEFAULT false,
+  CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+  "id"            SERIAL          PRIMARY KEY,
+  "user_id"       INT8            NOT NULL,
+  "token_id"      VARCHAR(64)     UNIQUE,
+  "expires_at"    TIMESTAMP(6)    NOT NULL,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"    TIMESTAMP(6),
+  "is_deleted"    BOOLEAN         NOT NULL DEFAULT false,
+  CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+-- A row without token_id revokes every token of the user issued before created_at (logout from all sessions)
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
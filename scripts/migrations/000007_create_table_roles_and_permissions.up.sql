@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS roles (
+  "id"            SERIAL          PRIMARY KEY,
+  "name"          VARCHAR(64)     NOT NULL UNIQUE,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"    TIMESTAMP(6),
+  "is_deleted"    BOOLEAN         NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+  "id"            SERIAL          PRIMARY KEY,
+  "name"          VARCHAR(64)     NOT NULL UNIQUE,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"    TIMESTAMP(6),
+  "is_deleted"    BOOLEAN         NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  "role_id"       INT8            NOT NULL,
+  "permission_id" INT8            NOT NULL,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  PRIMARY KEY (role_id, permission_id),
+  CONSTRAINT fk_role_id
+        FOREIGN KEY (role_id)
+            REFERENCES roles(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE,
+  CONSTRAINT fk_permission_id
+        FOREIGN KEY (permission_id)
+            REFERENCES permissions(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  "user_id"       INT8            NOT NULL,
+  "role_id"       INT8            NOT NULL,
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  PRIMARY KEY (user_id, role_id),
+  CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE,
+  CONSTRAINT fk_role_id
+        FOREIGN KEY (role_id)
+            REFERENCES roles(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+
+  INSERT INTO roles ("name")
+    VALUES
+      ('admin');
+
+  INSERT INTO permissions ("name")
+    VALUES
+      ('book:write');
+
+  INSERT INTO role_permissions ("role_id", "permission_id")
+    SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin';
@@ -0,0 +1,11 @@
+DELETE FROM permissions WHERE "name" = 'user:write';
+
+DROP INDEX IF EXISTS users_email_unique_idx;
+
+-- An email of a soft deleted user can have been registered again, so the deleted users
+-- sharing their email with another user get a unique email before it is made unique again
+UPDATE users u SET email = LEFT('deleted-' || u.id || '.' || u.email, 255)
+  WHERE u.is_deleted = true
+    AND EXISTS (SELECT 1 FROM users o WHERE o.email = u.email AND o.id <> u.id);
+
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,11 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique_idx ON users (email) WHERE is_deleted = false;
+
+
+  INSERT INTO permissions ("name")
+    VALUES
+      ('user:write');
+
+  INSERT INTO role_permissions ("role_id", "permission_id")
+    SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'user:write';
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS books_created_at_id_idx ON books (created_at, id) WHERE is_deleted = false;
+CREATE INDEX IF NOT EXISTS books_price_id_idx ON books (price, id) WHERE is_deleted = false;
+CREATE INDEX IF NOT EXISTS books_title_id_idx ON books (title, id) WHERE is_deleted = false;
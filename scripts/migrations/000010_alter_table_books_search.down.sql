@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS escape_html(TEXT);
+
+DROP INDEX IF EXISTS books_author_trgm_idx;
+DROP INDEX IF EXISTS books_title_trgm_idx;
+DROP INDEX IF EXISTS books_search_vector_idx;
+
+ALTER TABLE books DROP COLUMN IF EXISTS "search_vector";
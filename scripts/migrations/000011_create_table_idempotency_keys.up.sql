@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  "id"                SERIAL          PRIMARY KEY,
+  "user_id"           INT8            NOT NULL,
+  "idempotency_key"   VARCHAR(255)    NOT NULL,
+  "request_hash"      VARCHAR(64)     NOT NULL,
+  "attempt_id"        VARCHAR(32)     NOT NULL,
+  "status_code"       INT4,
+  "content_type"      VARCHAR(255),
+  "response_body"     BYTEA,
+  "created_at"        TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"        TIMESTAMP(6),
+  CONSTRAINT uq_idempotency_keys_user_id_idempotency_key
+        UNIQUE (user_id, idempotency_key),
+  CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+-- A row without status_code belongs to a request that is still in flight
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
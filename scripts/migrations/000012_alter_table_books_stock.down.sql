@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stock_adjustments;
+
+ALTER TABLE books DROP CONSTRAINT IF EXISTS chk_books_stock;
+ALTER TABLE books DROP COLUMN IF EXISTS "stock";
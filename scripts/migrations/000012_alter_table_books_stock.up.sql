@@ -0,0 +1,24 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS "stock" INT8 NOT NULL DEFAULT 0;
+ALTER TABLE books ADD CONSTRAINT chk_books_stock CHECK (stock >= 0);
+
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+  "id"                SERIAL          PRIMARY KEY,
+  "book_id"           INT8            NOT NULL,
+  "user_id"           INT8            NOT NULL,
+  "quantity_change"   INT8            NOT NULL,
+  "stock"             INT8            NOT NULL,
+  "reason"            VARCHAR(255)    NOT NULL,
+  "created_at"        TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  CONSTRAINT fk_book_id
+        FOREIGN KEY (book_id)
+            REFERENCES books(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE,
+  CONSTRAINT fk_user_id
+        FOREIGN KEY (user_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_book_id_created_at ON stock_adjustments (book_id, created_at DESC, id DESC);
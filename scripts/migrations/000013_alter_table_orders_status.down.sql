@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE "name" = 'order:write';
+
+DROP TABLE IF EXISTS order_status_histories;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS "status";
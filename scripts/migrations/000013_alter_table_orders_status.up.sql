@@ -0,0 +1,36 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS "status" VARCHAR(32) NOT NULL DEFAULT 'pending';
+
+CREATE TABLE IF NOT EXISTS order_status_histories (
+  "id"            SERIAL          PRIMARY KEY,
+  "order_id"      INT8            NOT NULL,
+  "from_status"   VARCHAR(32),
+  "to_status"     VARCHAR(32)     NOT NULL,
+  "actor_id"      INT8,
+  "reason"        VARCHAR(255),
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  CONSTRAINT fk_order_id
+        FOREIGN KEY (order_id)
+            REFERENCES orders(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE,
+  CONSTRAINT fk_actor_id
+        FOREIGN KEY (actor_id)
+            REFERENCES users(id)
+            ON UPDATE CASCADE
+            ON DELETE SET NULL
+);
+
+-- A history entry without actor_id was made by the system
+CREATE INDEX IF NOT EXISTS idx_order_status_histories_order_id ON order_status_histories (order_id, created_at);
+
+-- Existing orders start their history as pending, placed by their owner
+INSERT INTO order_status_histories ("order_id", "to_status", "actor_id", "created_at")
+  SELECT id, status, user_id, created_at FROM orders;
+
+
+  INSERT INTO permissions ("name")
+    VALUES
+      ('order:write');
+
+  INSERT INTO role_permissions ("role_id", "permission_id")
+    SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'order:write';
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS payments (
+  "id"            SERIAL          PRIMARY KEY,
+  "order_id"      INT8            NOT NULL,
+  "provider"      VARCHAR(32)     NOT NULL,
+  -- A payment is reserved before its charge is made, so its charge id is only known afterwards
+  "charge_id"     VARCHAR(255),
+  "amount"        INT8            NOT NULL,
+  "currency"      VARCHAR(3)      NOT NULL,
+  "status"        VARCHAR(32)     NOT NULL DEFAULT 'pending',
+  "created_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  "updated_at"    TIMESTAMP(6)    NOT NULL DEFAULT (TIMEZONE('UTC', NOW())),
+  CONSTRAINT uq_payments_provider_charge_id UNIQUE (provider, charge_id),
+  CONSTRAINT fk_order_id
+        FOREIGN KEY (order_id)
+            REFERENCES orders(id)
+            ON UPDATE CASCADE
+            ON DELETE CASCADE
+);
+
+-- An order can only have one payment that is not failed or refunded, so it cannot be charged twice
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payments_order_id_active ON payments (order_id) WHERE status IN ('pending', 'succeeded');